@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// account is a named set of provider credentials
+type account struct {
+	// Alias is a name of a subdirectory the account is exported into, empty for a single account
+	Alias string
+	// Prefix of account environmental variables
+	Prefix string
+}
+
+var (
+	aliasPattern  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	prefixPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// accounts returns named accounts listed in '<PROVIDER>_ACCOUNTS' with their variables bound,
+// or a single unnamed account that uses '<PROVIDER>_<KEY>' variables when the list is not set
+func accounts(v *viper.Viper, provider string, keys ...string) ([]account, error) {
+	list := fmt.Sprintf("%s_ACCOUNTS", provider)
+
+	err := v.BindEnv(list)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.GetString(list) == "" {
+		return []account{{Prefix: provider}}, nil
+	}
+
+	var a []account
+	aliases := make(map[string]string)
+	for _, alias := range strings.Split(v.GetString(list), ",") {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+
+		if !aliasPattern.MatchString(alias) {
+			return nil, fmt.Errorf("invalid account alias '%s' in env.var '%s'", alias, list)
+		}
+
+		// aliases such as 'prod-a' and 'prod_a' would read credentials of each other
+		prefix := fmt.Sprintf("%s_%s", provider, prefixPattern.ReplaceAllString(strings.ToUpper(alias), "_"))
+		if other, ok := aliases[prefix]; ok {
+			return nil, fmt.Errorf("account aliases '%s' and '%s' in env.var '%s' share variables prefix '%s'", other, alias, list, prefix)
+		}
+		aliases[prefix] = alias
+
+		for _, key := range keys {
+			err := v.BindEnv(fmt.Sprintf("%s_%s", prefix, key))
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("error binding variables of account '%s'", alias))
+			}
+		}
+
+		a = append(a, account{
+			Alias:  alias,
+			Prefix: prefix,
+		})
+	}
+
+	return a, nil
+}
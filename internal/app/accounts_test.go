@@ -0,0 +1,65 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+
+	"dns-exporter/internal/app"
+
+	"github.com/spf13/viper"
+)
+
+func TestAccounts(t *testing.T) {
+	suite := map[string]struct {
+		list     string
+		expected []app.Account
+	}{
+		"single": {
+			list:     "",
+			expected: []app.Account{{Prefix: "CLOUDFLARE"}},
+		},
+		"named": {
+			list: "production, marketing.eu,dev-1",
+			expected: []app.Account{
+				{Alias: "production", Prefix: "CLOUDFLARE_PRODUCTION"},
+				{Alias: "marketing.eu", Prefix: "CLOUDFLARE_MARKETING_EU"},
+				{Alias: "dev-1", Prefix: "CLOUDFLARE_DEV_1"},
+			},
+		},
+	}
+
+	for name, test := range suite {
+		v := viper.New()
+		v.Set("CLOUDFLARE_ACCOUNTS", test.list)
+
+		a, err := app.Accounts(v, "CLOUDFLARE", "API_TOKEN")
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(test.expected, a) {
+			t.Errorf("%s: \nEXPECTED accounts: \n%+v\n\nGOT accounts: \n%+v\n\n", name, test.expected, a)
+		}
+	}
+}
+
+func TestAccountsInvalid(t *testing.T) {
+	suite := map[string]string{
+		"leading dash":       "-production",
+		"slash":              "production/eu",
+		"space":              "prod eu",
+		"shared prefix":      "prod-a,prod_a",
+		"shared prefix case": "prod,PROD",
+		"duplicate":          "prod,prod",
+	}
+
+	for name, list := range suite {
+		v := viper.New()
+		v.Set("ROUTE53_ACCOUNTS", list)
+
+		_, err := app.Accounts(v, "ROUTE53", "PROFILE")
+		if err == nil {
+			t.Errorf("%s: \nEXPECTED error: \ninvalid account aliases '%s'\n\nGOT error: \n<nil>", name, list)
+		}
+	}
+}
@@ -2,14 +2,24 @@ package app
 
 import (
 	"fmt"
-	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"time"
 
+	"dns-exporter/internal/pkg/axfr"
+	az "dns-exporter/internal/pkg/azure"
+	gcd "dns-exporter/internal/pkg/clouddns"
+	cf "dns-exporter/internal/pkg/cloudflare"
+	do "dns-exporter/internal/pkg/digitalocean"
 	vcs "dns-exporter/internal/pkg/git"
-	"dns-exporter/internal/pkg/utils"
+	hz "dns-exporter/internal/pkg/hetzner"
+	ln "dns-exporter/internal/pkg/linode"
+	pdns "dns-exporter/internal/pkg/powerdns"
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
@@ -17,13 +27,15 @@ import (
 )
 
 var conf Configuration
-var providers Providers
 
-func init() {
+// configure reads configuration of an application and its providers from environmental variables
+func configure() {
 	v := viper.New()
 
 	vars := []string{
 		"DELAY",
+		"ZONEFILE_MODE",
+		"EXPORT_FORMATS",
 		"GIT_REMOTE_ENABLED",
 		"GIT_URL",
 		"GIT_BRANCH",
@@ -31,10 +43,39 @@ func init() {
 		"GIT_EMAIL",
 		"GIT_TOKEN",
 		"CLOUDFLARE_ENABLED",
+		"CLOUDFLARE_API_TOKEN",
 		"CLOUDFLARE_EMAIL",
 		"CLOUDFLARE_TOKEN",
 		"ROUTE53_ENABLED",
+		"ROUTE53_PROFILE",
+		"ROUTE53_ROLE_ARN",
+		"ROUTE53_ORGANIZATION_ROLE",
+		"ROUTE53_ORGANIZATION_SKIP",
+		"ROUTE53_RESOLVER_REGIONS",
 		"AWS_REGION",
+		"CLOUDDNS_ENABLED",
+		"CLOUDDNS_CREDENTIALS",
+		"CLOUDDNS_PROJECT",
+		"AZURE_ENABLED",
+		"AZURE_TENANT_ID",
+		"AZURE_CLIENT_ID",
+		"AZURE_CLIENT_SECRET",
+		"DIGITALOCEAN_ENABLED",
+		"DIGITALOCEAN_TOKEN",
+		"HETZNER_ENABLED",
+		"HETZNER_TOKEN",
+		"LINODE_ENABLED",
+		"LINODE_TOKEN",
+		"AXFR_ENABLED",
+		"AXFR_ZONES",
+		"AXFR_SERVERS",
+		"AXFR_TSIG_NAME",
+		"AXFR_TSIG_SECRET",
+		"AXFR_TSIG_ALGORITHM",
+		"POWERDNS_ENABLED",
+		"POWERDNS_URL",
+		"POWERDNS_API_KEY",
+		"POWERDNS_SERVER",
 	}
 
 	for _, variable := range vars {
@@ -45,10 +86,7 @@ func init() {
 	}
 
 	conf = Configuration{
-		Providers: []string{},
-		Clients: &Clients{
-			HTTP: &http.Client{},
-		},
+		Providers: []Provider{},
 		FileSystem: &Filesystems{
 			Global: afero.NewOsFs(),
 			Meta:   osfs.New("./data/.git"),
@@ -65,9 +103,35 @@ func init() {
 		conf.Delay = 1
 	}
 
-	initCloudflare(v)
+	switch mode := zone.Mode(v.GetString("ZONEFILE_MODE")); mode {
+	case "", zone.Annotated:
+		conf.Mode = zone.Annotated
+	case zone.Strict:
+		conf.Mode = zone.Strict
+	default:
+		log.Fatalf("unsupported zonefile mode '%s'", mode)
+	}
+
+	if v.GetString("EXPORT_FORMATS") != "" {
+		zone.Formats = []zone.Format{}
+		for _, f := range strings.Split(v.GetString("EXPORT_FORMATS"), ",") {
+			format, err := zone.ParseFormat(strings.TrimSpace(f))
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			zone.Formats = append(zone.Formats, format)
+		}
+	}
+
+	for _, initialize := range registry {
+		p, err := initialize(v)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-	initRoute53(v)
+		conf.Providers = append(conf.Providers, p...)
+	}
 
 	initGit(v)
 
@@ -76,45 +140,226 @@ func init() {
 	}
 }
 
-func initCloudflare(v *viper.Viper) {
-	if v.GetBool("CLOUDFLARE_ENABLED") {
-		var err error
+func initCloudflare(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("CLOUDFLARE_ENABLED") {
+		return nil, nil
+	}
+
+	a, err := accounts(v, "CLOUDFLARE", "API_TOKEN", "EMAIL", "TOKEN")
+	if err != nil {
+		return nil, err
+	}
 
-		if !v.IsSet("CLOUDFLARE_EMAIL") {
-			log.Fatal("missing env.var 'CLOUDFLARE_EMAIL'")
+	var providers []Provider
+	for _, account := range a {
+		p, err := newCloudFlareProvider(v, account)
+		if err != nil {
+			return nil, err
 		}
 
-		if !v.IsSet("CLOUDFLARE_TOKEN") {
-			log.Fatal("missing env.var 'CLOUDFLARE_TOKEN'")
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
+
+func newCloudFlareProvider(v *viper.Viper, a account) (Provider, error) {
+	token := fmt.Sprintf("%s_API_TOKEN", a.Prefix)
+	email := fmt.Sprintf("%s_EMAIL", a.Prefix)
+	key := fmt.Sprintf("%s_TOKEN", a.Prefix)
+
+	// scoped API token takes precedence over Global API Key
+	var credentials cf.Credentials
+	if v.IsSet(token) {
+		credentials.Token = v.GetString(token)
+	} else {
+		if !v.IsSet(email) {
+			return nil, fmt.Errorf("missing env.var '%s'", email)
 		}
 
-		conf.Clients.CloudFlare, err = newCloudFlareClient(v.Get("CLOUDFLARE_EMAIL"), v.Get("CLOUDFLARE_TOKEN"))
-		if err != nil {
-			log.Fatal(err)
+		if !v.IsSet(key) {
+			return nil, fmt.Errorf("missing env.var '%s'", key)
 		}
 
-		providers.CloudFlare.Public = make(map[string]string)
-		conf.Providers = append(conf.Providers, "CloudFlare")
+		credentials.Email = v.GetString(email)
+		credentials.Key = v.GetString(key)
+	}
+
+	c, err := newCloudFlareClient(credentials)
+	if err != nil {
+		return nil, err
 	}
+
+	// scoped API token is verified when zones are fetched, not on startup
+	p := cf.NewProvider(c)
+	p.Account = a.Alias
+	p.Scoped = credentials.Token != ""
+
+	return p, nil
 }
 
-func initRoute53(v *viper.Viper) {
-	if v.GetBool("ROUTE53_ENABLED") {
-		var err error
+func initRoute53(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("ROUTE53_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("AWS_REGION") {
+		return nil, errors.New("missing env.var 'AWS_REGION'")
+	}
+
+	// Resolver resources are exported for listed regions only
+	regions := splitList(v.GetString("ROUTE53_RESOLVER_REGIONS"))
+
+	// organization accounts are discovered instead of being listed explicitly
+	if v.IsSet("ROUTE53_ORGANIZATION_ROLE") {
+		skip := splitList(v.GetString("ROUTE53_ORGANIZATION_SKIP"))
+
+		discovered, err := newOrganizationProviders(v.GetString("ROUTE53_PROFILE"), v.GetString("ROUTE53_ORGANIZATION_ROLE"), skip, regions)
+		if err != nil {
+			return nil, err
+		}
 
-		if !v.IsSet("AWS_REGION") {
-			log.Fatal("missing env.var 'AWS_REGION'")
+		var providers []Provider
+		for _, p := range discovered {
+			providers = append(providers, p)
 		}
 
-		conf.Clients.Route53, err = newRoute53Client()
+		return providers, nil
+	}
+
+	a, err := accounts(v, "ROUTE53", "PROFILE", "ROLE_ARN")
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []Provider
+	for _, account := range a {
+		// a named account has to define either a profile or a role, the unnamed one may use the default credentials chain
+		profile := v.GetString(fmt.Sprintf("%s_PROFILE", account.Prefix))
+		role := v.GetString(fmt.Sprintf("%s_ROLE_ARN", account.Prefix))
+		if account.Alias != "" && profile == "" && role == "" {
+			return nil, fmt.Errorf("missing env.var '%s_PROFILE' or '%s_ROLE_ARN'", account.Prefix, account.Prefix)
+		}
+
+		c, resolvers, err := newRoute53Client(profile, role, regions)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
-		providers.Route53.Public = make(map[string]string)
-		providers.Route53.Private = make(map[string]string)
-		conf.Providers = append(conf.Providers, "Route53")
+		p := r53.NewProvider(c)
+		p.Account = account.Alias
+		p.Resolvers = resolvers
+
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
+
+func initCloudDNS(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("CLOUDDNS_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("CLOUDDNS_CREDENTIALS") {
+		return nil, errors.New("missing env.var 'CLOUDDNS_CREDENTIALS'")
+	}
+
+	c, err := newCloudDNSClient(v.Get("CLOUDDNS_CREDENTIALS"), v.Get("CLOUDDNS_PROJECT"))
+	if err != nil {
+		return nil, err
 	}
+
+	return []Provider{gcd.NewProvider(c)}, nil
+}
+
+func initAzure(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("AZURE_ENABLED") {
+		return nil, nil
+	}
+
+	for _, variable := range []string{"AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET"} {
+		if !v.IsSet(variable) {
+			return nil, fmt.Errorf("missing env.var '%s'", variable)
+		}
+	}
+
+	return []Provider{az.NewProvider(newAzureClient(v.Get("AZURE_TENANT_ID"), v.Get("AZURE_CLIENT_ID"), v.Get("AZURE_CLIENT_SECRET")))}, nil
+}
+
+func initDigitalOcean(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("DIGITALOCEAN_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("DIGITALOCEAN_TOKEN") {
+		return nil, errors.New("missing env.var 'DIGITALOCEAN_TOKEN'")
+	}
+
+	return []Provider{do.NewProvider(newDigitalOceanClient(v.Get("DIGITALOCEAN_TOKEN")))}, nil
+}
+
+func initHetzner(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("HETZNER_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("HETZNER_TOKEN") {
+		return nil, errors.New("missing env.var 'HETZNER_TOKEN'")
+	}
+
+	return []Provider{hz.NewProvider(newHetznerClient(v.Get("HETZNER_TOKEN")))}, nil
+}
+
+func initLinode(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("LINODE_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("LINODE_TOKEN") {
+		return nil, errors.New("missing env.var 'LINODE_TOKEN'")
+	}
+
+	return []Provider{ln.NewProvider(newLinodeClient(v.Get("LINODE_TOKEN")))}, nil
+}
+
+func initAXFR(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("AXFR_ENABLED") {
+		return nil, nil
+	}
+
+	zones := strings.FieldsFunc(v.GetString("AXFR_ZONES"), func(r rune) bool { return r == ',' || r == ' ' })
+	if len(zones) == 0 {
+		return nil, errors.New("missing env.var 'AXFR_ZONES'")
+	}
+
+	servers := splitServers(v.GetString("AXFR_SERVERS"))
+	if len(servers) == 0 {
+		return nil, errors.New("missing env.var 'AXFR_SERVERS'")
+	}
+
+	c, err := newAXFRClient(v.Get("AXFR_TSIG_NAME"), v.Get("AXFR_TSIG_SECRET"), v.Get("AXFR_TSIG_ALGORITHM"))
+	if err != nil {
+		return nil, err
+	}
+
+	return []Provider{axfr.NewProvider(c, zones, servers)}, nil
+}
+
+func initPowerDNS(v *viper.Viper) ([]Provider, error) {
+	if !v.GetBool("POWERDNS_ENABLED") {
+		return nil, nil
+	}
+
+	if !v.IsSet("POWERDNS_URL") {
+		return nil, errors.New("missing env.var 'POWERDNS_URL'")
+	}
+
+	if !v.IsSet("POWERDNS_API_KEY") {
+		return nil, errors.New("missing env.var 'POWERDNS_API_KEY'")
+	}
+
+	return []Provider{pdns.NewProvider(newPowerDNSClient(v.Get("POWERDNS_URL"), v.Get("POWERDNS_API_KEY"), v.Get("POWERDNS_SERVER")))}, nil
 }
 
 func initGit(v *viper.Viper) {
@@ -162,53 +407,38 @@ func initGit(v *viper.Viper) {
 func Entrypoint(version string) {
 	log.Info(fmt.Sprintf("dns-exporter v%s", version))
 
-	dir, err := utils.ValidateDir(fmt.Sprintf("./%v/.git", "data"), false, conf.FileSystem.Global)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if conf.Project.Remote.URL != "" {
-		if dir {
-			// Pull repository
-			log.Info("pulling remote git repository")
-			err := conf.Project.Pull(conf.FileSystem.Meta, conf.FileSystem.Data)
-			if err != nil && err.Error() != "already up-to-date" {
-				log.Fatal(err)
-			} else if err.Error() == "already up-to-date" {
-				log.Info("local repository is up-to-date with 'origin'")
-			}
+	configure()
 
-		} else {
-			// Clone repository
-			log.Info("cloning remote git repository")
-			err := conf.Project.Clone(conf.FileSystem.Meta, conf.FileSystem.Data)
-			if err != nil {
-				log.Fatal(err)
-			}
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		if err := restore(os.Args[2:]); err != nil {
+			log.Fatal(err)
 		}
-	} else {
-		if dir {
-			log.Info("using local git repository")
-		} else {
-			// Init new repository
-			log.Info("creating local git repository")
-			err := vcs.Init(conf.FileSystem.Meta, conf.FileSystem.Data)
-			if err != nil {
-				log.Fatal(err)
-			}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
 		}
+
+		return
+	}
+
+	if err := conf.checkout(true); err != nil {
+		log.Fatal(err)
 	}
 
-	if err := conf.fetch(&providers); err != nil {
+	if err := conf.fetch(); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := conf.export(&providers); err != nil {
+	if err := conf.export(); err != nil {
 		log.Fatal(err)
 	}
 
 	log.Info("commiting changes to local git repository")
-	err = conf.Project.Commit(time.Now(), conf.FileSystem.Meta, conf.FileSystem.Data)
+	err := conf.Project.Commit(time.Now(), conf.FileSystem.Meta, conf.FileSystem.Data)
 	if err == nil {
 		if conf.Project.Remote.URL != "" {
 			log.Info("pushing to remote git repository")
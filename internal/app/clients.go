@@ -1,20 +1,50 @@
 package app
 
 import (
+	"context"
+	"dns-exporter/internal/pkg/axfr"
+	az "dns-exporter/internal/pkg/azure"
+	gcd "dns-exporter/internal/pkg/clouddns"
 	cf "dns-exporter/internal/pkg/cloudflare"
+	do "dns-exporter/internal/pkg/digitalocean"
+	hz "dns-exporter/internal/pkg/hetzner"
+	ln "dns-exporter/internal/pkg/linode"
+	pdns "dns-exporter/internal/pkg/powerdns"
 	r53 "dns-exporter/internal/pkg/route53"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53resolver"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/google"
 )
 
-// newCloudFlareClient returns new Cloudflare client
-func newCloudFlareClient(email, token interface{}) (cf.Client, error) {
-	c, err := cloudflare.New(fmt.Sprintf("%v", token), fmt.Sprintf("%v", email))
+// newCloudFlareClient returns new Cloudflare client authenticated either by a scoped API token or by a Global API Key
+func newCloudFlareClient(credentials cf.Credentials) (cf.Client, error) {
+	var c *cloudflare.API
+	var err error
+
+	if credentials.Token != "" {
+		c, err = cloudflare.NewWithAPIToken(credentials.Token)
+	} else {
+		c, err = cloudflare.New(credentials.Key, credentials.Email)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating CloudFlare client")
 	}
@@ -22,8 +52,8 @@ func newCloudFlareClient(email, token interface{}) (cf.Client, error) {
 	return c, nil
 }
 
-// newRoute53Client returns new Route53 client
-func newRoute53Client() (r53.Client, error) {
+// newAWSSession returns new AWS session of a shared config profile
+func newAWSSession(profile string) (*session.Session, error) {
 	r := os.Getenv("AWS_REGION")
 	if r == "" {
 		return nil, errors.New("missing required environmental variable 'AWS_REGION'")
@@ -37,5 +67,202 @@ func newRoute53Client() (r53.Client, error) {
 		}
 	}
 
-	return route53.New(session.Must(session.NewSession())), nil
+	s, err := session.NewSessionWithOptions(session.Options{
+		Profile: profile,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating AWS session")
+	}
+
+	return s, nil
+}
+
+// newRoute53Client returns new Route53 client and Resolver clients of regions, authenticated by a shared config profile, optionally assuming a role
+func newRoute53Client(profile, role string, regions []string) (r53.Client, map[string]r53.Resolver, error) {
+	s, err := newAWSSession(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c *credentials.Credentials
+	if role != "" {
+		c = stscreds.NewCredentials(s, role)
+	}
+
+	client, resolvers := newRoute53Clients(s, c, regions)
+
+	return client, resolvers, nil
+}
+
+// newRoute53Clients returns Route53 and Resolver clients of a session, authenticated by credentials other than session's ones when set
+func newRoute53Clients(s *session.Session, c *credentials.Credentials, regions []string) (r53.Client, map[string]r53.Resolver) {
+	cfg := aws.NewConfig()
+	if c != nil {
+		cfg = cfg.WithCredentials(c)
+	}
+
+	resolvers := make(map[string]r53.Resolver)
+	for _, region := range regions {
+		resolvers[region] = route53resolver.New(s, cfg.Copy().WithRegion(region))
+	}
+
+	return route53.New(s, cfg), resolvers
+}
+
+// newOrganizationProviders returns Route53 providers of every organization account, authenticated by assuming a role in it
+func newOrganizationProviders(profile, role string, skip, regions []string) ([]*r53.Provider, error) {
+	s, err := newAWSSession(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return r53.Discover(organizations.New(s), sts.New(s), role, skip, func(c *credentials.Credentials) (r53.Client, map[string]r53.Resolver) {
+		return newRoute53Clients(s, c, regions)
+	})
+}
+
+// splitList returns a list of comma separated values, such as AWS regions or account IDs
+func splitList(values string) []string {
+	var l []string
+
+	for _, value := range strings.Split(values, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			l = append(l, value)
+		}
+	}
+
+	return l
+}
+
+// newCloudDNSClient returns new Google Cloud DNS client authenticated by a service account
+func newCloudDNSClient(credentials, project interface{}) (gcd.Client, error) {
+	b, err := ioutil.ReadFile(fmt.Sprintf("%v", credentials))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading Google Cloud service account file")
+	}
+
+	c, err := google.CredentialsFromJSON(context.Background(), b, "https://www.googleapis.com/auth/ndev.clouddns.readonly")
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing Google Cloud service account file")
+	}
+
+	p := c.ProjectID
+	if project != nil && fmt.Sprintf("%v", project) != "" {
+		p = fmt.Sprintf("%v", project)
+	}
+
+	if p == "" {
+		return nil, errors.New("missing Google Cloud project, set env.var 'CLOUDDNS_PROJECT'")
+	}
+
+	return &gcd.API{
+		HTTP:    oauth2.NewClient(context.Background(), c.TokenSource),
+		URL:     gcd.DefaultURL,
+		Project: p,
+	}, nil
+}
+
+// newAzureClient returns new Azure Resource Manager client authenticated by a service principal
+func newAzureClient(tenant, id, secret interface{}) az.Client {
+	c := clientcredentials.Config{
+		ClientID:     fmt.Sprintf("%v", id),
+		ClientSecret: fmt.Sprintf("%v", secret),
+		TokenURL:     fmt.Sprintf("https://login.microsoftonline.com/%v/oauth2/v2.0/token", tenant),
+		Scopes:       []string{"https://management.azure.com/.default"},
+	}
+
+	return &az.API{
+		HTTP: c.Client(context.Background()),
+		URL:  az.DefaultURL,
+	}
+}
+
+// newDigitalOceanClient returns new DigitalOcean client
+func newDigitalOceanClient(token interface{}) do.Client {
+	return &do.API{
+		HTTP:  &http.Client{},
+		URL:   do.DefaultURL,
+		Token: fmt.Sprintf("%v", token),
+	}
+}
+
+// newHetznerClient returns new Hetzner DNS client
+func newHetznerClient(token interface{}) hz.Client {
+	return &hz.API{
+		HTTP:  &http.Client{},
+		URL:   hz.DefaultURL,
+		Token: fmt.Sprintf("%v", token),
+	}
+}
+
+// newLinodeClient returns new Linode client
+func newLinodeClient(token interface{}) ln.Client {
+	return &ln.API{
+		HTTP:  &http.Client{},
+		URL:   ln.DefaultURL,
+		Token: fmt.Sprintf("%v", token),
+	}
+}
+
+// newAXFRClient returns new zone transfer client, TSIG signing is enabled when a key name is provided
+func newAXFRClient(name, secret, algorithm interface{}) (axfr.Client, error) {
+	r := &axfr.Resolver{
+		Timeout: 10 * time.Second,
+	}
+
+	if name == nil || fmt.Sprintf("%v", name) == "" {
+		return r, nil
+	}
+
+	if secret == nil || fmt.Sprintf("%v", secret) == "" {
+		return nil, errors.New("missing env.var 'AXFR_TSIG_SECRET'")
+	}
+
+	r.TSIG = &axfr.TSIG{
+		Name:      fmt.Sprintf("%v", name),
+		Secret:    fmt.Sprintf("%v", secret),
+		Algorithm: dns.HmacSHA256,
+	}
+
+	if algorithm != nil && fmt.Sprintf("%v", algorithm) != "" {
+		r.TSIG.Algorithm = fmt.Sprintf("%v", algorithm)
+	}
+
+	return r, nil
+}
+
+// splitServers returns a list of 'host:port' addresses, port 53 is used by default
+func splitServers(servers string) []string {
+	var s []string
+
+	for _, server := range strings.Split(servers, ",") {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+
+		s = append(s, server)
+	}
+
+	return s
+}
+
+// newPowerDNSClient returns new PowerDNS Authoritative HTTP API client
+func newPowerDNSClient(url, key, server interface{}) pdns.Client {
+	s := pdns.DefaultServer
+	if server != nil && fmt.Sprintf("%v", server) != "" {
+		s = fmt.Sprintf("%v", server)
+	}
+
+	return &pdns.API{
+		HTTP:   &http.Client{},
+		URL:    strings.TrimSuffix(fmt.Sprintf("%v", url), "/"),
+		Server: s,
+		Key:    fmt.Sprintf("%v", key),
+	}
 }
@@ -0,0 +1,27 @@
+package app
+
+import (
+	vcs "dns-exporter/internal/pkg/git"
+
+	"github.com/spf13/viper"
+)
+
+type Account = account
+
+var (
+	Exports = exports
+	Lookup  = lookup
+	Restore = restore
+)
+
+func Accounts(v *viper.Viper, provider string, keys ...string) ([]Account, error) {
+	return accounts(v, provider, keys...)
+}
+
+func Configure(providers []Provider, project *vcs.Project, fs *Filesystems) {
+	conf = Configuration{
+		Providers:  providers,
+		Project:    project,
+		FileSystem: fs,
+	}
+}
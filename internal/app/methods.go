@@ -3,12 +3,56 @@ package app
 import (
 	"sync"
 
+	vcs "dns-exporter/internal/pkg/git"
+	"dns-exporter/internal/pkg/utils"
+
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// checkout prepares the local git repository of exports, cloned or pulled when a remote repository is enabled,
+// a new local repository is created only when 'create' is set, as there is nothing to read from it otherwise
+func (c *Configuration) checkout(create bool) error {
+	dir, err := utils.ValidateDir("./data/.git", false, c.FileSystem.Global)
+	if err != nil {
+		return err
+	}
+
+	if c.Project.Remote.URL != "" {
+		if dir {
+			// Pull repository
+			log.Info("pulling remote git repository")
+			err := c.Project.Pull(c.FileSystem.Meta, c.FileSystem.Data)
+			if err != nil && err.Error() != "already up-to-date" {
+				return err
+			} else if err != nil {
+				log.Info("local repository is up-to-date with 'origin'")
+			}
+
+			return nil
+		}
+
+		// Clone repository
+		log.Info("cloning remote git repository")
+		return c.Project.Clone(c.FileSystem.Meta, c.FileSystem.Data)
+	}
+
+	if dir {
+		log.Info("using local git repository")
+		return nil
+	}
+
+	if !create {
+		return errors.New("no local git repository of exports in './data'")
+	}
+
+	// Init new repository
+	log.Info("creating local git repository")
+	return vcs.Init(c.FileSystem.Meta, c.FileSystem.Data)
+}
+
 // fetch hosted zones from configured providers
-func (c *Configuration) fetch(p *Providers) error {
+func (c *Configuration) fetch() error {
 	errs := make(chan error, len(c.Providers))
 
 	var wg sync.WaitGroup
@@ -17,16 +61,10 @@ func (c *Configuration) fetch(p *Providers) error {
 	// fetch each provide in a separate routine
 	for _, provider := range c.Providers {
 		log.WithFields(log.Fields{
-			"provider": provider,
+			"provider": provider.Name(),
 		}).Info("fetching zones")
 
-		if provider == "CloudFlare" {
-			go p.CloudFlare.Fetch(c.Clients.CloudFlare, errs, &wg)
-		}
-
-		if provider == "Route53" {
-			go p.Route53.Fetch(c.Clients.Route53, errs, &wg)
-		}
+		go provider.Fetch(errs, &wg)
 	}
 
 	wg.Wait()
@@ -48,7 +86,7 @@ func (c *Configuration) fetch(p *Providers) error {
 }
 
 // export zonefiles from configured providers
-func (c *Configuration) export(p *Providers) error {
+func (c *Configuration) export() error {
 	errs := make(chan error, len(c.Providers))
 
 	var wg sync.WaitGroup
@@ -56,13 +94,7 @@ func (c *Configuration) export(p *Providers) error {
 
 	// fetch each provide in a sepparate routine
 	for _, provider := range c.Providers {
-		if provider == "CloudFlare" {
-			go p.CloudFlare.Export(c.Clients.HTTP, c.Delay, errs, &wg, "./data", c.FileSystem.Global)
-		}
-
-		if provider == "Route53" {
-			go p.Route53.Export(c.Clients.Route53, c.Delay, errs, &wg, "./data", c.FileSystem.Global)
-		}
+		go provider.Export(c.Delay, c.Mode, errs, &wg, "./data", c.FileSystem.Global)
 	}
 
 	wg.Wait()
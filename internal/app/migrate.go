@@ -0,0 +1,78 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// migrate recreates a zone exported from one provider on another one out of its backup at a git revision,
+// records are translated to the target provider and the ones that cannot be are reported
+func migrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	from := flags.String("from", "route53", "provider the zone is exported from, 'route53' or 'cloudflare'")
+	to := flags.String("to", "cloudflare", "provider the zone is migrated to, 'route53' or 'cloudflare'")
+	revision := flags.String("revision", "HEAD", "git revision of a backup, such as a commit hash, a tag or 'HEAD~1'")
+	fromAccount := flags.String("from-account", "", "alias of an account the zone is exported from")
+	toAccount := flags.String("to-account", "", "alias of an account the zone is migrated to")
+	apply := flags.Bool("apply", false, "apply the plan, otherwise it is only printed")
+	yes := flags.Bool("yes", false, "apply the plan without a confirmation")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: dns-exporter migrate [-from route53|cloudflare] [-to route53|cloudflare] [-revision HEAD] [-from-account alias] [-to-account alias] [-apply] [-yes] <zone>")
+	}
+
+	if *from == *to {
+		return fmt.Errorf("zone cannot be migrated from '%s' to itself, use restore instead", *from)
+	}
+
+	domain := dns.Fqdn(flags.Arg(0))
+
+	// the source provider does not have to be configured, its exports are enough
+	dir, err := exports(*from, *fromAccount, false)
+	if err != nil {
+		return err
+	}
+
+	provider, err := lookup(*to, *toAccount)
+	if err != nil {
+		return err
+	}
+
+	backup, zonefile, err := readBackup(*from, *revision, dir, domain)
+	if err != nil {
+		return err
+	}
+
+	migrated, report, err := backup.Migrate(provider.Name())
+	if err != nil {
+		return err
+	}
+
+	if len(report) > 0 {
+		fmt.Printf("records not migrated as they are in %s:\n", backup.Provider)
+		for _, r := range report {
+			fmt.Printf("  %s\n", r)
+		}
+	}
+
+	fields := log.Fields{
+		"from":     backup.Provider,
+		"provider": provider.Name(),
+		"zone":     strings.TrimSuffix(domain, "."),
+		"revision": *revision,
+	}
+	if *toAccount != "" {
+		fields["account"] = *toAccount
+	}
+
+	return reconcile(provider, migrated, zonefile, fields, *apply, *yes)
+}
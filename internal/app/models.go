@@ -1,13 +1,13 @@
 package app
 
 import (
-	cf "dns-exporter/internal/pkg/cloudflare"
+	"sync"
 
 	vcs "dns-exporter/internal/pkg/git"
-
-	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
 
 	"github.com/spf13/afero"
+	"github.com/spf13/viper"
 	"gopkg.in/src-d/go-billy.v4"
 )
 
@@ -16,11 +16,11 @@ const Version string = "1.0.13"
 
 // Configuration contains app runtime config
 type Configuration struct {
-	Providers  []string
+	Providers  []Provider
 	Project    *vcs.Project
 	FileSystem *Filesystems
-	Clients    *Clients
 	Delay      int
+	Mode       zone.Mode
 }
 
 // Filesystems contains different filesystems abstractions
@@ -30,15 +30,12 @@ type Filesystems struct {
 	Data   billy.Filesystem
 }
 
-// Clients contains authenticated DNS provider clients
-type Clients struct {
-	CloudFlare cf.Client
-	HTTP       cf.HTTPClient
-	Route53    r53.Client
+// Provider is a DNS backend able to list and export its hosted zones
+type Provider interface {
+	Name() string
+	Fetch(errs chan error, wg *sync.WaitGroup)
+	Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs)
 }
 
-// Providers contains fetched providers Zones
-type Providers struct {
-	CloudFlare cf.Zones
-	Route53    r53.Zones
-}
+// Initializer returns configured providers, one per account, or none when the provider is disabled
+type Initializer func(v *viper.Viper) ([]Provider, error)
@@ -0,0 +1,15 @@
+package app
+
+// registry contains initializers of all supported DNS providers,
+// a new provider only has to be appended here in order to be exported
+var registry = []Initializer{
+	initCloudflare,
+	initRoute53,
+	initCloudDNS,
+	initAzure,
+	initDigitalOcean,
+	initHetzner,
+	initLinode,
+	initAXFR,
+	initPowerDNS,
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// restore diffs a zone against its backup at a git revision of the local repository and prints the plan,
+// changes are applied only with '-apply', after a confirmation unless '-yes' is set
+func restore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	name := flags.String("provider", "route53", "provider of the zone, 'route53' or 'cloudflare'")
+	revision := flags.String("revision", "HEAD", "git revision of a backup, such as a commit hash, a tag or 'HEAD~1'")
+	account := flags.String("account", "", "alias of an account the zone is exported from")
+	private := flags.Bool("private", false, "restore a private Route53 hosted zone")
+	apply := flags.Bool("apply", false, "apply the plan, otherwise it is only printed")
+	yes := flags.Bool("yes", false, "apply the plan without a confirmation")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: dns-exporter restore [-provider route53|cloudflare] [-revision HEAD] [-account alias] [-private] [-apply] [-yes] <zone>")
+	}
+
+	domain := dns.Fqdn(flags.Arg(0))
+
+	provider, err := lookup(*name, *account)
+	if err != nil {
+		return err
+	}
+
+	dir, err := exports(*name, *account, *private)
+	if err != nil {
+		return err
+	}
+
+	backup, zonefile, err := readBackup(*name, *revision, dir, domain)
+	if err != nil {
+		return err
+	}
+
+	fields := log.Fields{
+		"provider": provider.Name(),
+		"zone":     strings.TrimSuffix(domain, "."),
+		"revision": *revision,
+	}
+	if *account != "" {
+		fields["account"] = *account
+	}
+
+	return reconcile(provider, backup, zonefile, fields, *apply, *yes)
+}
+
+// lookup returns a configured provider of an account, 'route53' or 'cloudflare'
+func lookup(name, account string) (Provider, error) {
+	for _, p := range conf.Providers {
+		switch p := p.(type) {
+		case *r53.Provider:
+			if name == "route53" && p.Account == account {
+				if p.Skipped {
+					return nil, fmt.Errorf("organization account '%s' is skipped by 'ROUTE53_ORGANIZATION_SKIP'", account)
+				}
+
+				return p, nil
+			}
+		case *cf.Provider:
+			if name == "cloudflare" && p.Account == account {
+				return p, nil
+			}
+		}
+	}
+
+	if name != "route53" && name != "cloudflare" {
+		return nil, fmt.Errorf("provider '%s' is not supported, only 'route53' and 'cloudflare' are", name)
+	}
+
+	return nil, fmt.Errorf("no '%s' provider is configured for account '%s'", name, account)
+}
+
+// exports returns a directory of zone exports of a provider account, relative to the git repository
+func exports(name, account string, private bool) (string, error) {
+	var dir string
+
+	switch name {
+	case "route53":
+		dir = "Route53"
+	case "cloudflare":
+		dir = "CloudFlare"
+	default:
+		return "", fmt.Errorf("provider '%s' is not supported, only 'route53' and 'cloudflare' are", name)
+	}
+
+	if account != "" {
+		dir = fmt.Sprintf("%s/%s", dir, account)
+	}
+
+	// only Route53 splits public and private zones
+	if name == "route53" {
+		if private {
+			dir = fmt.Sprintf("%s/Private", dir)
+		} else {
+			dir = fmt.Sprintf("%s/Public", dir)
+		}
+	}
+
+	return dir, nil
+}
+
+// reconcile plans changes that turn a live zone of a provider into 'z' and prints them, 'zonefile' marks a backup read from a zonefile export,
+// the plan is applied only when asked to, after a confirmation unless 'yes' is set
+func reconcile(provider Provider, z zone.Zone, zonefile bool, fields log.Fields, apply, yes bool) error {
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	provider.Fetch(errs, &wg)
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	log.WithFields(fields).Info("planning zone changes")
+
+	// plan is printed as is, apply is bound to the plan of a provider
+	var plan interface {
+		fmt.Stringer
+		Len() int
+	}
+	var changes func() error
+
+	switch p := provider.(type) {
+	case *r53.Provider:
+		r, err := p.Plan(z)
+		if err != nil {
+			return err
+		}
+
+		plan, changes = r, func() error { return p.Apply(r) }
+	case *cf.Provider:
+		r, err := p.Plan(z, zonefile)
+		if err != nil {
+			return err
+		}
+
+		plan, changes = r, func() error { return p.Apply(r) }
+	default:
+		return fmt.Errorf("zone changes are not supported for provider '%s'", provider.Name())
+	}
+
+	fmt.Print(plan)
+
+	if plan.Len() == 0 {
+		return nil
+	}
+
+	if !apply {
+		log.WithFields(fields).Info("dry run, rerun with '-apply' to apply the plan")
+		return nil
+	}
+
+	if !yes && !confirm(fmt.Sprintf("apply %d changes to zone %s?", plan.Len(), strings.TrimSuffix(z.Name, "."))) {
+		log.WithFields(fields).Info("changes cancelled")
+		return nil
+	}
+
+	log.WithFields(fields).Info("applying zone changes")
+
+	return changes()
+}
+
+// confirm asks a question on the standard input, anything but 'y' or 'yes' is a refusal
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// readBackup returns a zone from its JSON or YAML export in 'dir' at a git revision, and whether it is read from a zonefile export instead,
+// zonefiles are read as a fallback, they do not keep record IDs, comments and tags
+func readBackup(name, revision, dir, domain string) (zone.Zone, bool, error) {
+	// backups of a remote repository are read from its latest state
+	err := conf.checkout(false)
+	if err != nil {
+		return zone.Zone{}, false, err
+	}
+
+	var z zone.Zone
+
+	content, err := conf.Project.Read(revision, fmt.Sprintf("%s/%s", dir, utils.FileName(domain, "json")), conf.FileSystem.Meta, conf.FileSystem.Data)
+	if err == nil {
+		err = json.Unmarshal(content, &z)
+		if err != nil {
+			return zone.Zone{}, false, errors.Wrap(err, "error decoding JSON export")
+		}
+
+		return z, false, nil
+	}
+
+	content, err = conf.Project.Read(revision, fmt.Sprintf("%s/%s", dir, utils.FileName(domain, "yaml")), conf.FileSystem.Meta, conf.FileSystem.Data)
+	if err == nil {
+		err = yaml.Unmarshal(content, &z)
+		if err != nil {
+			return zone.Zone{}, false, errors.Wrap(err, "error decoding YAML export")
+		}
+
+		return z, false, nil
+	}
+
+	// zonefiles keep proxied status, aliases and routing policies in record annotations
+	content, err = conf.Project.Read(revision, fmt.Sprintf("%s/%s", dir, utils.FileName(domain, "txt")), conf.FileSystem.Meta, conf.FileSystem.Data)
+	if err != nil {
+		return zone.Zone{}, false, errors.Wrap(err, fmt.Sprintf("no export of zone '%s' at revision '%s'", domain, revision))
+	}
+
+	switch name {
+	case "cloudflare":
+		z, err = cf.ReadZonefile(domain, content)
+	case "route53":
+		z, err = r53.ReadZonefile(domain, strings.HasSuffix(dir, "/Private"), content)
+	default:
+		err = fmt.Errorf("zonefile exports of provider '%s' cannot be read", name)
+	}
+	if err != nil {
+		return zone.Zone{}, false, err
+	}
+
+	return z, true, nil
+}
@@ -0,0 +1,272 @@
+package app_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"dns-exporter/internal/app"
+	cf "dns-exporter/internal/pkg/cloudflare"
+	vcs "dns-exporter/internal/pkg/git"
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/cloudflare/cloudflare-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+)
+
+func TestExports(t *testing.T) {
+	suite := map[string]struct {
+		name     string
+		account  string
+		private  bool
+		expected string
+	}{
+		"route53":            {name: "route53", expected: "Route53/Public"},
+		"route53 private":    {name: "route53", private: true, expected: "Route53/Private"},
+		"route53 account":    {name: "route53", account: "production", expected: "Route53/production/Public"},
+		"cloudflare":         {name: "cloudflare", expected: "CloudFlare"},
+		"cloudflare private": {name: "cloudflare", private: true, expected: "CloudFlare"},
+		"cloudflare account": {name: "cloudflare", account: "marketing", expected: "CloudFlare/marketing"},
+	}
+
+	for name, test := range suite {
+		dir, err := app.Exports(test.name, test.account, test.private)
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", name, err)
+		}
+
+		if dir != test.expected {
+			t.Errorf("%s: \nEXPECTED directory: \n%s\n\nGOT directory: \n%s\n\n", name, test.expected, dir)
+		}
+	}
+
+	_, err := app.Exports("clouddns", "", false)
+	if err == nil || err.Error() != "provider 'clouddns' is not supported, only 'route53' and 'cloudflare' are" {
+		t.Errorf("\nEXPECTED error: \nprovider 'clouddns' is not supported, only 'route53' and 'cloudflare' are\n\nGOT error: \n%v\n\n", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	production := r53.NewProvider(&mocks.Route53{})
+	production.Account = "production"
+
+	sandbox := r53.NewProvider(nil)
+	sandbox.Account = "222222222222-sandbox"
+	sandbox.Skipped = true
+
+	cloudflare := cf.NewProvider(&mocks.Cloudflare{})
+
+	app.Configure([]app.Provider{production, sandbox, cloudflare}, &vcs.Project{Remote: &vcs.Origin{}}, &app.Filesystems{})
+
+	suite := map[string]struct {
+		name     string
+		account  string
+		expected app.Provider
+		err      string
+	}{
+		"route53 account":      {name: "route53", account: "production", expected: production},
+		"cloudflare":           {name: "cloudflare", expected: cloudflare},
+		"unknown account":      {name: "route53", account: "staging", err: "no 'route53' provider is configured for account 'staging'"},
+		"skipped account":      {name: "route53", account: "222222222222-sandbox", err: "organization account '222222222222-sandbox' is skipped by 'ROUTE53_ORGANIZATION_SKIP'"},
+		"unsupported provider": {name: "azure", err: "provider 'azure' is not supported, only 'route53' and 'cloudflare' are"},
+	}
+
+	for name, test := range suite {
+		p, err := app.Lookup(test.name, test.account)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: \nEXPECTED error: \n%s\n\nGOT error: \n%v\n\n", name, test.err, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", name, err)
+		}
+
+		if p != test.expected {
+			t.Errorf("%s: \nEXPECTED provider: \n%+v\n\nGOT provider: \n%+v\n\n", name, test.expected, p)
+		}
+	}
+}
+
+func TestRestoreFlags(t *testing.T) {
+	app.Configure(nil, &vcs.Project{Remote: &vcs.Origin{}}, &app.Filesystems{})
+
+	suite := map[string]struct {
+		args []string
+		err  string
+	}{
+		"missing zone":         {args: []string{"-provider", "cloudflare"}, err: "usage: dns-exporter restore"},
+		"several zones":        {args: []string{"domain.com", "domain.org"}, err: "usage: dns-exporter restore"},
+		"unknown flag":         {args: []string{"-force", "domain.com"}, err: "flag provided but not defined: -force"},
+		"unsupported provider": {args: []string{"-provider", "azure", "domain.com"}, err: "provider 'azure' is not supported"},
+		"unknown account":      {args: []string{"-account", "staging", "domain.com"}, err: "no 'route53' provider is configured for account 'staging'"},
+	}
+
+	for name, test := range suite {
+		err := app.Restore(test.args)
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%s: \nEXPECTED error: \n%s\n\nGOT error: \n%v\n\n", name, test.err, err)
+		}
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	backup, err := json.Marshal(zone.Zone{
+		Name:     "domain.com",
+		ID:       "zone-1",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{ID: "r1", Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+		},
+	})
+	if err != nil {
+		t.Fatal("error composing backup:", err)
+	}
+
+	// test: a plan of a JSON backup is only printed unless '-apply' is set
+	restoreDryRun(t, "CloudFlare/domain-com.json", backup)
+}
+
+func TestRestoreZonefile(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	backup := `;; SOA Record
+domain.com.	3600	IN	SOA	ns1.domain.com. dns.cloudflare.com. 1 10000 2400 604800 1800
+
+;; A Records
+; proxied=false
+domain.com.	300	IN	A	1.2.3.4
+`
+
+	// test: CloudFlare zonefile is a fallback of a backup without a JSON or YAML export
+	restoreDryRun(t, "CloudFlare/domain-com.txt", []byte(backup))
+}
+
+func TestRestoreRoute53Zonefile(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	backup := `;; A Records
+; routing: set-identifier="blue" weight=10
+api.domain.com.	60	IN	A	1.2.3.6
+
+;; Route53 Alias Records
+; alias: hosted-zone=Z2 evaluate-target-health=false
+www.domain.com.	IN	A	domain.com.
+`
+
+	project, filesystems := repository(t, "Route53/Public/domain-com.txt", []byte(backup))
+
+	// test: Route53 zonefile is a fallback of a backup without a JSON or YAML export, its aliases and routing policies match the live zone
+	c := mocks.Route53{}
+	c.On("ListHostedZones").Return(&route53.ListHostedZonesOutput{
+		IsTruncated: aws.Bool(false),
+		HostedZones: []*route53.HostedZone{
+			{
+				Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)},
+				Id:     aws.String("/hostedzone/Z2"),
+				Name:   aws.String("domain.com."),
+			},
+		},
+	}, nil).Once()
+	c.On("ListResourceRecordSets").Return(&route53.ListResourceRecordSetsOutput{
+		IsTruncated: aws.Bool(false),
+		ResourceRecordSets: []*route53.ResourceRecordSet{
+			{
+				Name:            aws.String("api.domain.com."),
+				Type:            aws.String("A"),
+				TTL:             aws.Int64(60),
+				SetIdentifier:   aws.String("blue"),
+				Weight:          aws.Int64(10),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.6")}},
+			},
+			{
+				Name: aws.String("www.domain.com."),
+				Type: aws.String("A"),
+				AliasTarget: &route53.AliasTarget{
+					HostedZoneId:         aws.String("Z2"),
+					DNSName:              aws.String("domain.com."),
+					EvaluateTargetHealth: aws.Bool(false),
+				},
+			},
+		},
+	}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+
+	app.Configure([]app.Provider{r53.NewProvider(&c)}, project, filesystems)
+
+	// any change would fail on a call the mock does not expect
+	err := app.Restore([]string{"-apply", "-yes", "domain.com"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+}
+
+// restoreDryRun restores 'domain.com' CloudFlare zone out of a backup committed at 'path', failing a test on any change applied
+func restoreDryRun(t *testing.T, path string, backup []byte) {
+	project, filesystems := repository(t, path, backup)
+
+	// live zone differs from its backup, any change would fail on a call the mock does not expect
+	c := mocks.Cloudflare{}
+	c.On("ListZones").Return([]cloudflare.Zone{{ID: "zone-1", Name: "domain.com"}}, nil).Once()
+	c.On("ListDNSRecords", "zone-1").Return([]cloudflare.DNSRecord{
+		{ID: "r1", Name: "domain.com", Type: "A", TTL: 300, Content: "1.2.3.5"},
+	}, nil).Once()
+
+	app.Configure([]app.Provider{cf.NewProvider(&c)}, project, filesystems)
+
+	err := app.Restore([]string{"-provider", "cloudflare", "domain.com"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+}
+
+// repository returns a local repository with a backup committed at 'path'
+func repository(t *testing.T, path string, backup []byte) (*vcs.Project, *app.Filesystems) {
+	meta, data := memfs.New(), memfs.New()
+	project := &vcs.Project{
+		AuthorName:  "DNS-EXPORTER",
+		AuthorEmail: "no-email@dns-exporter.com",
+		Remote:      &vcs.Origin{},
+	}
+
+	global := afero.NewMemMapFs()
+	if err := global.MkdirAll("./data/.git", 0777); err != nil {
+		t.Fatal("error creating repository directory:", err)
+	}
+
+	err := vcs.Init(meta, data)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	err = util.WriteFile(data, path, backup, 0644)
+	if err != nil {
+		t.Fatal("error writing backup:", err)
+	}
+
+	err = project.Commit(time.Unix(0, 0), meta, data)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	return project, &app.Filesystems{Global: global, Meta: meta, Data: data}
+}
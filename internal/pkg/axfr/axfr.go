@@ -0,0 +1,120 @@
+package axfr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch locates an authoritative server for every configured zone, servers are tried in order
+func (z Zones) Fetch(c Client, zones, servers []string, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for _, name := range zones {
+		var failures []string
+
+		for _, server := range servers {
+			_, err := c.SOA(name, server)
+			if err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+
+			z.Public[dns.Fqdn(name)] = server
+			break
+		}
+
+		if _, ok := z.Public[dns.Fqdn(name)]; !ok {
+			errs <- fmt.Errorf("AXFR: no authoritative server for zone '%s': %s", name, strings.Join(failures, "; "))
+			return
+		}
+	}
+
+	errs <- nil
+}
+
+// Export transferred zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	// validate provider export dir
+	dir := fmt.Sprintf("%v/AXFR", root)
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "AXFR: error exporting zones")
+		return
+	}
+
+	// export zonefiles
+	for domain, server := range z.Public {
+		log.WithFields(log.Fields{
+			"provider": "AXFR",
+			"zone":     strings.TrimSuffix(domain, "."),
+			"server":   server,
+		}).Info("exporting zone")
+
+		r, err := getRecords(domain, server, c)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("AXFR: error retrieving zone '%s' records", domain))
+			return
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       server,
+			Provider: "AXFR",
+			Records:  r,
+		}
+
+		// write zone exports
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("AXFR: error exporting zone: '%s'", domain))
+			return
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	errs <- nil
+}
+
+// getRecords returns transferred zone records, without the closing SOA record
+func getRecords(domain, server string, c Client) ([]zone.Record, error) {
+	rrs, err := c.Transfer(domain, server)
+	if err != nil {
+		return nil, errors.Wrap(err, "error transferring zone")
+	}
+
+	var r []zone.Record
+	var soa bool
+
+	for _, rr := range rrs {
+		h := rr.Header()
+
+		if h.Rrtype == dns.TypeSOA {
+			if soa {
+				continue
+			}
+			soa = true
+		}
+
+		r = append(r, zone.Record{
+			Name:  h.Name,
+			Type:  dns.TypeToString[h.Rrtype],
+			TTL:   int64(h.Ttl),
+			Value: []string{strings.TrimPrefix(rr.String(), h.String())},
+		})
+	}
+
+	return r, nil
+}
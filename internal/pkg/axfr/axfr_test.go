@@ -0,0 +1,233 @@
+package axfr_test
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dns-exporter/internal/pkg/axfr"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const fixture = `$ORIGIN domain.com.
+@	3600	IN	SOA	ns1.domain.com. hostmaster.domain.com. 2023010101 7200 3600 1209600 3600
+@	3600	IN	NS	ns1.domain.com.
+@	300	IN	A	192.168.1.51
+@	300	IN	MX	10 mail.domain.com.
+www	300	IN	CNAME	domain.com.
+txt	300	IN	TXT	"v=spf1 -all"
+`
+
+var key = axfr.TSIG{
+	Name:      "transfer.",
+	Secret:    "c2VjcmV0LXRzaWcta2V5",
+	Algorithm: dns.HmacSHA256,
+}
+
+// serve starts an in-process authoritative server of a fixture zone, requiring TSIG signed requests
+func serve(t *testing.T) (string, func()) {
+	var records []dns.RR
+
+	p := dns.NewZoneParser(strings.NewReader(fixture), "", "")
+	for rr, ok := p.Next(); ok; rr, ok = p.Next() {
+		records = append(records, rr)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal("error parsing fixture zone:", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("error starting DNS server:", err)
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if r.IsTsig() == nil || w.TsigStatus() != nil {
+			m := new(dns.Msg)
+			m.SetRcode(r, dns.RcodeRefused)
+			_ = w.WriteMsg(m)
+			return
+		}
+
+		if r.Question[0].Name != "domain.com." {
+			m := new(dns.Msg)
+			m.SetRcode(r, dns.RcodeRefused)
+			m.SetTsig(key.Name, key.Algorithm, 300, time.Now().Unix())
+			_ = w.WriteMsg(m)
+			return
+		}
+
+		if r.Question[0].Qtype == dns.TypeAXFR {
+			ch := make(chan *dns.Envelope, 1)
+			tr := new(dns.Transfer)
+			tr.TsigSecret = map[string]string{key.Name: key.Secret}
+
+			go func() {
+				ch <- &dns.Envelope{RR: append(records, records[0])}
+				close(ch)
+			}()
+
+			_ = tr.Out(w, r, ch)
+			w.Hijack()
+			return
+		}
+
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.Authoritative = true
+		m.Answer = []dns.RR{records[0]}
+		m.SetTsig(key.Name, key.Algorithm, 300, time.Now().Unix())
+		_ = w.WriteMsg(m)
+	})
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		Listener:          l,
+		Handler:           handler,
+		TsigSecret:        map[string]string{key.Name: key.Secret},
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+
+	return l.Addr().String(), func() { _ = server.Shutdown() }
+}
+
+func TestFetch(t *testing.T) {
+	address, shutdown := serve(t)
+	defer shutdown()
+
+	c := &axfr.Resolver{TSIG: &key, Timeout: 2 * time.Second}
+
+	z := axfr.Zones{
+		Public: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// first server is unreachable
+	z.Fetch(c, []string{"domain.com"}, []string{"127.0.0.1:1", address}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := axfr.Zones{
+		Public: map[string]string{
+			"domain.com.": address,
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+
+	// not served zone
+	wg.Add(1)
+	z.Fetch(c, []string{"domain.org"}, []string{address}, errs, &wg)
+
+	err = <-errs
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nno authoritative server for zone 'domain.org'\n\nGOT error: \n<nil>")
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	address, shutdown := serve(t)
+	defer shutdown()
+
+	c := &axfr.Resolver{TSIG: &key, Timeout: 2 * time.Second}
+
+	fs := afero.NewMemMapFs()
+
+	z := axfr.Zones{
+		Public: map[string]string{
+			"domain.com.": address,
+		},
+	}
+
+	expected := `;; SOA Record
+domain.com.	3600	IN	SOA	ns1.domain.com. hostmaster.domain.com. 2023010101 7200 3600 1209600 3600
+
+;; NS Records
+domain.com.	3600	IN	NS	ns1.domain.com.
+
+;; MX Records
+domain.com.	300	IN	MX	10 mail.domain.com.
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+
+;; CNAME Records
+www.domain.com.	300	IN	CNAME	domain.com.
+
+;; TXT Records
+txt.domain.com.	300	IN	TXT	"v=spf1 -all"
+
+`
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(c, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	content, err := afero.ReadFile(fs, "./AXFR/domain-com.txt")
+	if err != nil {
+		t.Fatal("error reading exported zonefile:", err)
+	}
+
+	if !reflect.DeepEqual(expected, string(content)) {
+		t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", expected, string(content))
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	address, shutdown := serve(t)
+	defer shutdown()
+
+	// signed transfer
+	r, err := axfr.GetRecords("domain.com.", address, &axfr.Resolver{TSIG: &key, Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := zone.Record{
+		Name:  "www.domain.com.",
+		Type:  "CNAME",
+		TTL:   300,
+		Value: []string{"domain.com."},
+	}
+
+	if len(r) != 6 || !reflect.DeepEqual(expected, r[4]) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// unsigned transfer is refused
+	_, err = axfr.GetRecords("domain.com.", address, &axfr.Resolver{Timeout: 2 * time.Second})
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nerror transferring zone\n\nGOT error: \n<nil>")
+	}
+}
@@ -0,0 +1,3 @@
+package axfr
+
+var GetRecords = getRecords
@@ -0,0 +1,31 @@
+package axfr
+
+import (
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Zones served by authoritative servers, zone name is mapped to a server able to transfer it
+type Zones struct {
+	Public map[string]string
+}
+
+// Client interface
+type Client interface {
+	SOA(zone, server string) (*dns.SOA, error)
+	Transfer(zone, server string) ([]dns.RR, error)
+}
+
+// Resolver queries authoritative servers over TCP, optionally signing messages with TSIG
+type Resolver struct {
+	TSIG    *TSIG
+	Timeout time.Duration
+}
+
+// TSIG key used to sign queries and zone transfers
+type TSIG struct {
+	Name      string
+	Secret    string
+	Algorithm string
+}
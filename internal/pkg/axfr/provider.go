@@ -0,0 +1,44 @@
+package axfr
+
+import (
+	"sync"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+// Provider binds configured zones and authoritative servers to a resolver
+type Provider struct {
+	Client  Client
+	Zones   Zones
+	Names   []string
+	Servers []string
+}
+
+// NewProvider returns new AXFR provider
+func NewProvider(c Client, names, servers []string) *Provider {
+	return &Provider{
+		Client:  c,
+		Names:   names,
+		Servers: servers,
+		Zones: Zones{
+			Public: make(map[string]string),
+		},
+	}
+}
+
+// Name of a provider
+func (p *Provider) Name() string {
+	return "AXFR"
+}
+
+// Fetch authoritative servers of configured zones
+func (p *Provider) Fetch(errs chan error, wg *sync.WaitGroup) {
+	p.Zones.Fetch(p.Client, p.Names, p.Servers, errs, wg)
+}
+
+// Export transferred zones
+func (p *Provider) Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	p.Zones.Export(p.Client, delay, mode, errs, wg, root, fs)
+}
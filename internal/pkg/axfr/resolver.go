@@ -0,0 +1,77 @@
+package axfr
+
+import (
+	"fmt"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// SOA returns a zone SOA record served by a server
+func (r *Resolver) SOA(zone, server string) (*dns.SOA, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(zone), dns.TypeSOA)
+
+	c := &dns.Client{
+		Net:     "tcp",
+		Timeout: r.Timeout,
+	}
+
+	if r.TSIG != nil {
+		m.SetTsig(dns.Fqdn(r.TSIG.Name), dns.Fqdn(r.TSIG.Algorithm), 300, 0)
+		c.TsigSecret = map[string]string{dns.Fqdn(r.TSIG.Name): r.TSIG.Secret}
+	}
+
+	response, _, err := c.Exchange(m, server)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error querying SOA of '%s' from '%s'", zone, server))
+	}
+
+	if response.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("error querying SOA of '%s' from '%s': %s", zone, server, dns.RcodeToString[response.Rcode])
+	}
+
+	if !response.Authoritative {
+		return nil, fmt.Errorf("server '%s' is not authoritative for '%s'", server, zone)
+	}
+
+	for _, rr := range response.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa, nil
+		}
+	}
+
+	return nil, fmt.Errorf("missing SOA of '%s' in a response from '%s'", zone, server)
+}
+
+// Transfer returns all zone records using AXFR
+func (r *Resolver) Transfer(zone, server string) ([]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zone))
+
+	t := &dns.Transfer{
+		DialTimeout: r.Timeout,
+		ReadTimeout: r.Timeout,
+	}
+
+	if r.TSIG != nil {
+		m.SetTsig(dns.Fqdn(r.TSIG.Name), dns.Fqdn(r.TSIG.Algorithm), 300, 0)
+		t.TsigSecret = map[string]string{dns.Fqdn(r.TSIG.Name): r.TSIG.Secret}
+	}
+
+	envelopes, err := t.In(m, server)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error transferring '%s' from '%s'", zone, server))
+	}
+
+	var records []dns.RR
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			return nil, errors.Wrap(envelope.Error, fmt.Sprintf("error transferring '%s' from '%s'", zone, server))
+		}
+
+		records = append(records, envelope.RR...)
+	}
+
+	return records, nil
+}
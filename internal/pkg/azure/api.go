@@ -0,0 +1,128 @@
+package az
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL of an Azure Resource Manager REST API
+const DefaultURL string = "https://management.azure.com"
+
+const (
+	subscriptionsVersion string = "2020-01-01"
+	publicVersion        string = "2018-05-01"
+	privateVersion       string = "2020-06-01"
+)
+
+// ListSubscriptions returns all subscriptions visible to the credentials
+func (a *API) ListSubscriptions() ([]Subscription, error) {
+	var s []Subscription
+
+	err := a.list(fmt.Sprintf("%s/subscriptions?api-version=%s", a.URL, subscriptionsVersion), func(b []byte) error {
+		var p []Subscription
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		s = append(s, p...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ListZones returns public or private DNS zones of all resource groups of a subscription
+func (a *API) ListZones(subscription string, private bool) ([]DNSZone, error) {
+	var z []DNSZone
+
+	u := fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.Network/dnszones?api-version=%s", a.URL, subscription, publicVersion)
+	if private {
+		u = fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.Network/privateDnsZones?api-version=%s", a.URL, subscription, privateVersion)
+	}
+
+	err := a.list(u, func(b []byte) error {
+		var p []DNSZone
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		z = append(z, p...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// ListRecordSets returns all record sets of a DNS zone resource
+func (a *API) ListRecordSets(zone string, private bool) ([]RecordSet, error) {
+	var r []RecordSet
+
+	u := fmt.Sprintf("%s%s/recordsets?api-version=%s", a.URL, zone, publicVersion)
+	if private {
+		u = fmt.Sprintf("%s%s/ALL?api-version=%s", a.URL, zone, privateVersion)
+	}
+
+	err := a.list(u, func(b []byte) error {
+		var p []RecordSet
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		r = append(r, p...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// list follows 'nextLink' of a list operation, passing every page value to a callback
+func (a *API) list(u string, callback func([]byte) error) error {
+	for u != "" {
+		request, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error constructing HTTP request '%s'", u))
+		}
+
+		request.Header.Add("Accept", "application/json")
+
+		response, err := a.HTTP.Do(request)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error consuming '%s'", u))
+		}
+
+		b, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return errors.Wrap(err, "error reading responce body")
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code %v consuming '%s': %s", response.StatusCode, u, string(b))
+		}
+
+		var p page
+		if err := json.Unmarshal(b, &p); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", u))
+		}
+
+		if len(p.Value) > 0 {
+			if err := callback(p.Value); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", u))
+			}
+		}
+
+		u = p.NextLink
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package az
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch public and private zones of all visible subscriptions
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	subscriptions, err := c.ListSubscriptions()
+	if err != nil {
+		errs <- errors.Wrap(err, "AzureDNS: error fetching subscriptions")
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		public, err := c.ListZones(subscription.ID, false)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("AzureDNS: error fetching zones of subscription '%s'", subscription.DisplayName))
+			return
+		}
+
+		// zone names repeat across subscriptions and resource groups, resource IDs do not
+		for _, zone := range public {
+			z.Public[zone.ID] = zone.Name
+		}
+
+		private, err := c.ListZones(subscription.ID, true)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("AzureDNS: error fetching private zones of subscription '%s'", subscription.DisplayName))
+			return
+		}
+
+		for _, zone := range private {
+			z.Private[zone.ID] = zone.Name
+		}
+	}
+
+	errs <- nil
+}
+
+// Export hosted zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	parent := fmt.Sprintf("%v/AzureDNS", root)
+	public := fmt.Sprintf("%v/AzureDNS/Public", root)
+	private := fmt.Sprintf("%v/AzureDNS/Private", root)
+
+	// validate filetree
+	for _, dir := range []string{parent, public, private} {
+		_, err := utils.ValidateDir(dir, true, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, "AzureDNS: error exporting zones")
+			return
+		}
+	}
+
+	export := func(id, domain, t, root string, c Client, fs afero.Fs) error {
+		subscription, group, err := location(id)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("AzureDNS: error exporting zone: '%s'", domain))
+		}
+
+		log.WithFields(log.Fields{
+			"provider":       "AzureDNS",
+			"zone":           strings.TrimSuffix(domain, "."),
+			"type":           t,
+			"subscription":   subscription,
+			"resource-group": group,
+		}).Info("exporting zone")
+
+		dir := fmt.Sprintf("%s/%s/%s", root, subscription, group)
+		_, err = utils.ValidateDir(dir, true, fs)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("AzureDNS: error exporting zone: '%s'", domain))
+		}
+
+		r, err := getRecords(id, t == "private", c)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("AzureDNS: error retrieving zone '%s' records", domain))
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "AzureDNS",
+			Private:  t == "private",
+			Records:  r,
+		}
+
+		// write zone exports
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("AzureDNS: error exporting zone: '%s'", domain))
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+
+		return nil
+	}
+
+	// export zonefiles
+	for id, domain := range z.Public {
+		if err := export(id, domain, "public", public, c, fs); err != nil {
+			errs <- err
+			return
+		}
+	}
+
+	for id, domain := range z.Private {
+		if err := export(id, domain, "private", private, c, fs); err != nil {
+			errs <- err
+			return
+		}
+	}
+
+	errs <- nil
+}
+
+// location returns a subscription and a resource group of a zone resource ID,
+// lower cased as Azure treats them case-insensitively and returns them in varying case
+func location(id string) (string, string, error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) < 4 || !strings.EqualFold(parts[0], "subscriptions") || !strings.EqualFold(parts[2], "resourceGroups") {
+		return "", "", fmt.Errorf("unexpected resource ID '%s'", id)
+	}
+
+	return strings.ToLower(parts[1]), strings.ToLower(parts[3]), nil
+}
+
+// getRecords returns all record sets of a zone
+func getRecords(id string, private bool, c Client) ([]zone.Record, error) {
+	sets, err := c.ListRecordSets(id, private)
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving zone records")
+	}
+
+	var r []zone.Record
+	for _, set := range sets {
+		r = append(r, convert(set))
+	}
+
+	return r, nil
+}
+
+// convert an Azure record set into a zone record
+func convert(set RecordSet) zone.Record {
+	p := set.Properties
+
+	r := zone.Record{
+		Name: p.FQDN,
+		Type: set.Type[strings.LastIndex(set.Type, "/")+1:],
+		TTL:  p.TTL,
+	}
+
+	if p.TargetResource.ID != "" {
+		r.Value = []string{p.TargetResource.ID}
+		r.Alias = true
+		return r
+	}
+
+	for _, v := range p.ARecords {
+		r.Value = append(r.Value, v.IPv4Address)
+	}
+
+	for _, v := range p.AAAARecords {
+		r.Value = append(r.Value, v.IPv6Address)
+	}
+
+	if p.CNAMERecord != nil {
+		r.Value = append(r.Value, p.CNAMERecord.CNAME)
+	}
+
+	for _, v := range p.MXRecords {
+		r.Value = append(r.Value, fmt.Sprintf("%v %s", v.Preference, v.Exchange))
+	}
+
+	for _, v := range p.NSRecords {
+		r.Value = append(r.Value, v.NSDName)
+	}
+
+	for _, v := range p.PTRRecords {
+		r.Value = append(r.Value, v.PTRDName)
+	}
+
+	if p.SOARecord != nil {
+		s := p.SOARecord
+		r.Value = append(r.Value, fmt.Sprintf("%s %s %v %v %v %v %v", s.Host, s.Email, s.SerialNumber, s.RefreshTime, s.RetryTime, s.ExpireTime, s.MinimumTTL))
+	}
+
+	for _, v := range p.SRVRecords {
+		r.Value = append(r.Value, fmt.Sprintf("%v %v %v %s", v.Priority, v.Weight, v.Port, v.Target))
+	}
+
+	for _, v := range p.TXTRecords {
+		var chunks []string
+		for _, chunk := range v.Value {
+			chunks = append(chunks, zone.Quote(chunk))
+		}
+		r.Value = append(r.Value, strings.Join(chunks, " "))
+	}
+
+	for _, v := range p.CAARecords {
+		r.Value = append(r.Value, fmt.Sprintf("%v %s %s", v.Flags, v.Tag, zone.Quote(v.Value)))
+	}
+
+	return r
+}
@@ -0,0 +1,277 @@
+package az_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	az "dns-exporter/internal/pkg/azure"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	publicID  = "/subscriptions/sub-1/resourceGroups/dns/providers/Microsoft.Network/dnszones/domain.com"
+	privateID = "/subscriptions/sub-1/resourceGroups/vnet/providers/Microsoft.Network/privateDnsZones/domain.local"
+	// privateLinkID shares a zone name with a zone of another subscription
+	privateLinkID = "/subscriptions/sub-2/resourceGroups/VNET/providers/Microsoft.Network/privateDnsZones/domain.local"
+)
+
+// stub replies to a single request of an URL
+func stub(c *mocks.HTTP, url, body string) {
+	c.On("Do", mock.MatchedBy(func(r *http.Request) bool {
+		return r.URL.String() == url
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}, nil).Once()
+}
+
+func TestFetch(t *testing.T) {
+	c := mocks.HTTP{}
+
+	stub(&c, "https://management.azure.com/subscriptions?api-version=2020-01-01", `{
+		"value": [{"subscriptionId": "sub-1", "displayName": "Production"}],
+		"nextLink": "https://management.azure.com/subscriptions?api-version=2020-01-01&$skiptoken=2"
+	}`)
+	stub(&c, "https://management.azure.com/subscriptions?api-version=2020-01-01&$skiptoken=2", `{
+		"value": [{"subscriptionId": "sub-2", "displayName": "Staging"}]
+	}`)
+	stub(&c, "https://management.azure.com/subscriptions/sub-1/providers/Microsoft.Network/dnszones?api-version=2018-05-01", `{
+		"value": [{"id": "`+publicID+`", "name": "domain.com"}]
+	}`)
+	stub(&c, "https://management.azure.com/subscriptions/sub-1/providers/Microsoft.Network/privateDnsZones?api-version=2020-06-01", `{
+		"value": [{"id": "`+privateID+`", "name": "domain.local"}]
+	}`)
+	stub(&c, "https://management.azure.com/subscriptions/sub-2/providers/Microsoft.Network/dnszones?api-version=2018-05-01", `{"value": []}`)
+	stub(&c, "https://management.azure.com/subscriptions/sub-2/providers/Microsoft.Network/privateDnsZones?api-version=2020-06-01", `{
+		"value": [{"id": "`+privateLinkID+`", "name": "domain.local"}]
+	}`)
+
+	z := az.Zones{
+		Public:  make(map[string]string),
+		Private: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(&az.API{HTTP: &c, URL: az.DefaultURL}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := az.Zones{
+		Public: map[string]string{
+			publicID: "domain.com",
+		},
+		Private: map[string]string{
+			privateID:     "domain.local",
+			privateLinkID: "domain.local",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.HTTP{}
+
+	stub(&c, "https://management.azure.com"+publicID+"/recordsets?api-version=2018-05-01", `{
+		"value": [
+			{
+				"name": "@",
+				"type": "Microsoft.Network/dnszones/SOA",
+				"properties": {
+					"TTL": 3600,
+					"fqdn": "domain.com.",
+					"SOARecord": {"host": "ns1-01.azure-dns.com.", "email": "azuredns-hostmaster.microsoft.com", "serialNumber": 1, "refreshTime": 3600, "retryTime": 300, "expireTime": 2419200, "minimumTTL": 300}
+				}
+			},
+			{
+				"name": "www",
+				"type": "Microsoft.Network/dnszones/CNAME",
+				"properties": {"TTL": 300, "fqdn": "www.domain.com.", "CNAMERecord": {"cname": "domain.com."}}
+			}
+		]
+	}`)
+	stub(&c, "https://management.azure.com"+privateID+"/ALL?api-version=2020-06-01", `{
+		"value": [
+			{
+				"name": "db",
+				"type": "Microsoft.Network/privateDnsZones/A",
+				"properties": {"ttl": 10, "fqdn": "db.domain.local.", "aRecords": [{"ipv4Address": "10.0.0.4"}]}
+			}
+		]
+	}`)
+	stub(&c, "https://management.azure.com"+privateLinkID+"/ALL?api-version=2020-06-01", `{
+		"value": [
+			{
+				"name": "db",
+				"type": "Microsoft.Network/privateDnsZones/A",
+				"properties": {"ttl": 10, "fqdn": "db.domain.local.", "aRecords": [{"ipv4Address": "10.1.0.4"}]}
+			}
+		]
+	}`)
+
+	fs := afero.NewMemMapFs()
+
+	z := az.Zones{
+		Public: map[string]string{
+			publicID: "domain.com",
+		},
+		Private: map[string]string{
+			privateID:     "domain.local",
+			privateLinkID: "domain.local",
+		},
+	}
+
+	expected := map[string]string{
+		"./AzureDNS/Public/sub-1/dns/domain-com.txt": `;; SOA Record
+domain.com.	3600	IN	SOA	ns1-01.azure-dns.com. azuredns-hostmaster.microsoft.com 1 3600 300 2419200 300
+
+;; CNAME Records
+www.domain.com.	300	IN	CNAME	domain.com.
+
+`,
+		"./AzureDNS/Private/sub-1/vnet/domain-local.txt": `;; A Records
+db.domain.local.	10	IN	A	10.0.0.4
+
+`,
+		"./AzureDNS/Private/sub-2/vnet/domain-local.txt": `;; A Records
+db.domain.local.	10	IN	A	10.1.0.4
+
+`,
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&az.API{HTTP: &c, URL: az.DefaultURL}, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for file, zonefile := range expected {
+		content, err := afero.ReadFile(fs, file)
+		if err != nil {
+			t.Fatal("error reading exported zonefile:", err)
+		}
+
+		if !reflect.DeepEqual(zonefile, string(content)) {
+			t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", zonefile, string(content))
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	suite := map[string]struct {
+		input    string
+		expected zone.Record
+	}{
+		"MX": {
+			input: `{"type": "Microsoft.Network/dnszones/MX", "properties": {"TTL": 300, "fqdn": "domain.com.", "MXRecords": [{"preference": 10, "exchange": "mail.domain.com."}]}}`,
+			expected: zone.Record{
+				Name:  "domain.com.",
+				Type:  "MX",
+				TTL:   300,
+				Value: []string{"10 mail.domain.com."},
+			},
+		},
+		"TXT": {
+			input: `{"type": "Microsoft.Network/dnszones/TXT", "properties": {"TTL": 300, "fqdn": "txt.domain.com.", "TXTRecords": [{"value": ["v=spf1", "-all"]}]}}`,
+			expected: zone.Record{
+				Name:  "txt.domain.com.",
+				Type:  "TXT",
+				TTL:   300,
+				Value: []string{`"v=spf1" "-all"`},
+			},
+		},
+		"TXT escapes": {
+			input: `{"type": "Microsoft.Network/dnszones/TXT", "properties": {"TTL": 300, "fqdn": "txt.domain.com.", "TXTRecords": [{"value": ["caf\u00e9 \"quoted\""]}]}}`,
+			expected: zone.Record{
+				Name:  "txt.domain.com.",
+				Type:  "TXT",
+				TTL:   300,
+				Value: []string{`"caf\195\169 \"quoted\""`},
+			},
+		},
+		"SRV": {
+			input: `{"type": "Microsoft.Network/privateDnsZones/SRV", "properties": {"ttl": 300, "fqdn": "_sip._tcp.domain.local.", "srvRecords": [{"priority": 1, "weight": 10, "port": 5060, "target": "sip.domain.local."}]}}`,
+			expected: zone.Record{
+				Name:  "_sip._tcp.domain.local.",
+				Type:  "SRV",
+				TTL:   300,
+				Value: []string{"1 10 5060 sip.domain.local."},
+			},
+		},
+		"CAA": {
+			input: `{"type": "Microsoft.Network/dnszones/CAA", "properties": {"TTL": 300, "fqdn": "domain.com.", "caaRecords": [{"flags": 0, "tag": "issue", "value": "letsencrypt.org"}]}}`,
+			expected: zone.Record{
+				Name:  "domain.com.",
+				Type:  "CAA",
+				TTL:   300,
+				Value: []string{`0 issue "letsencrypt.org"`},
+			},
+		},
+		"Alias": {
+			input: `{"type": "Microsoft.Network/dnszones/A", "properties": {"TTL": 60, "fqdn": "cdn.domain.com.", "targetResource": {"id": "/subscriptions/sub-1/resourceGroups/cdn/providers/Microsoft.Cdn/profiles/cdn/endpoints/web"}}}`,
+			expected: zone.Record{
+				Name:  "cdn.domain.com.",
+				Type:  "A",
+				TTL:   60,
+				Value: []string{"/subscriptions/sub-1/resourceGroups/cdn/providers/Microsoft.Cdn/profiles/cdn/endpoints/web"},
+				Alias: true,
+			},
+		},
+	}
+
+	for name, test := range suite {
+		c := mocks.HTTP{}
+		stub(&c, "https://management.azure.com/zone/recordsets?api-version=2018-05-01", `{"value": [`+test.input+`]}`)
+
+		r, err := az.GetRecords("/zone", false, &az.API{HTTP: &c, URL: az.DefaultURL})
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", name, err)
+		}
+
+		if !reflect.DeepEqual([]zone.Record{test.expected}, r) {
+			t.Errorf("%s: \nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", name, test.expected, r)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	subscription, group, err := az.Location(privateLinkID)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if subscription != "sub-2" || group != "vnet" {
+		t.Errorf("\nEXPECTED location: \nsub-2/vnet\n\nGOT location: \n%s/%s\n\n", subscription, group)
+	}
+
+	_, _, err = az.Location("/providers/Microsoft.Network/dnszones/domain.com")
+	if err == nil {
+		t.Error("\nEXPECTED error: \nunexpected resource ID\n\nGOT error: \n<nil>")
+	}
+}
@@ -0,0 +1,6 @@
+package az
+
+var (
+	GetRecords = getRecords
+	Location   = location
+)
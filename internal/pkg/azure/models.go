@@ -0,0 +1,110 @@
+package az
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Zones hosted by a DNS provider, zone names by resource IDs
+type Zones struct {
+	Public  map[string]string
+	Private map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListSubscriptions() ([]Subscription, error)
+	ListZones(subscription string, private bool) ([]DNSZone, error)
+	ListRecordSets(zone string, private bool) ([]RecordSet, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is an Azure Resource Manager REST API client
+type API struct {
+	HTTP HTTPClient
+	URL  string
+}
+
+// page is a single page of an Azure Resource Manager list operation
+type page struct {
+	Value    json.RawMessage `json:"value"`
+	NextLink string          `json:"nextLink"`
+}
+
+// Subscription is an Azure subscription visible to the credentials
+type Subscription struct {
+	ID          string `json:"subscriptionId"`
+	DisplayName string `json:"displayName"`
+}
+
+// DNSZone is an Azure public or private DNS zone
+type DNSZone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// RecordSet is an Azure DNS record set, public and private zones share the same properties
+type RecordSet struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Type       string              `json:"type"`
+	Properties RecordSetProperties `json:"properties"`
+}
+
+// RecordSetProperties of an Azure DNS record set
+type RecordSetProperties struct {
+	TTL            int64          `json:"TTL"`
+	FQDN           string         `json:"fqdn"`
+	TargetResource TargetResource `json:"targetResource"`
+	ARecords       []struct {
+		IPv4Address string `json:"ipv4Address"`
+	} `json:"ARecords"`
+	AAAARecords []struct {
+		IPv6Address string `json:"ipv6Address"`
+	} `json:"AAAARecords"`
+	CNAMERecord *struct {
+		CNAME string `json:"cname"`
+	} `json:"CNAMERecord"`
+	MXRecords []struct {
+		Preference int    `json:"preference"`
+		Exchange   string `json:"exchange"`
+	} `json:"MXRecords"`
+	NSRecords []struct {
+		NSDName string `json:"nsdname"`
+	} `json:"NSRecords"`
+	PTRRecords []struct {
+		PTRDName string `json:"ptrdname"`
+	} `json:"PTRRecords"`
+	SOARecord *struct {
+		Host         string `json:"host"`
+		Email        string `json:"email"`
+		SerialNumber int64  `json:"serialNumber"`
+		RefreshTime  int64  `json:"refreshTime"`
+		RetryTime    int64  `json:"retryTime"`
+		ExpireTime   int64  `json:"expireTime"`
+		MinimumTTL   int64  `json:"minimumTTL"`
+	} `json:"SOARecord"`
+	SRVRecords []struct {
+		Priority int    `json:"priority"`
+		Weight   int    `json:"weight"`
+		Port     int    `json:"port"`
+		Target   string `json:"target"`
+	} `json:"SRVRecords"`
+	TXTRecords []struct {
+		Value []string `json:"value"`
+	} `json:"TXTRecords"`
+	CAARecords []struct {
+		Flags int    `json:"flags"`
+		Tag   string `json:"tag"`
+		Value string `json:"value"`
+	} `json:"caaRecords"`
+}
+
+// TargetResource of an Azure alias record set
+type TargetResource struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,75 @@
+package gcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL of a Cloud DNS REST API
+const DefaultURL string = "https://dns.googleapis.com/dns/v1"
+
+// ListManagedZones returns a single page of project managed zones
+func (a *API) ListManagedZones(pageToken string) (*ManagedZonesResponse, error) {
+	var r ManagedZonesResponse
+
+	err := a.get(fmt.Sprintf("/projects/%s/managedZones", url.PathEscape(a.Project)), pageToken, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// ListResourceRecordSets returns a single page of managed zone record sets
+func (a *API) ListResourceRecordSets(zone, pageToken string) (*ResourceRecordSetsResponse, error) {
+	var r ResourceRecordSetsResponse
+
+	err := a.get(fmt.Sprintf("/projects/%s/managedZones/%s/rrsets", url.PathEscape(a.Project), url.PathEscape(zone)), pageToken, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// get decodes a JSON response of an API path into v
+func (a *API) get(path, pageToken string, v interface{}) error {
+	u := a.URL + path
+	if pageToken != "" {
+		u = fmt.Sprintf("%s?pageToken=%s", u, url.QueryEscape(pageToken))
+	}
+
+	request, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error constructing HTTP request '%s'", path))
+	}
+
+	request.Header.Add("Accept", "application/json")
+
+	response, err := a.HTTP.Do(request)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error consuming '%s'", path))
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading responce body")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v consuming '%s': %s", response.StatusCode, path, string(b))
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package gcd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch managed zones
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	var t string
+
+	for {
+		o, err := c.ListManagedZones(t)
+		if err != nil {
+			errs <- errors.Wrap(err, "GoogleCloudDNS: error fetching zones")
+			return
+		}
+
+		// managed zone names are unique within a project, DNS names are not: private zones of a split horizon share them
+		for _, zone := range o.ManagedZones {
+			if zone.Visibility == "private" {
+				z.Private[zone.Name] = zone.DNSName
+			} else {
+				z.Public[zone.Name] = zone.DNSName
+			}
+		}
+
+		if o.NextPageToken != "" {
+			t = o.NextPageToken
+		} else {
+			break
+		}
+	}
+
+	errs <- nil
+}
+
+// Export managed zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	parent := fmt.Sprintf("%v/GoogleCloudDNS", root)
+	public := fmt.Sprintf("%v/GoogleCloudDNS/Public", root)
+	private := fmt.Sprintf("%v/GoogleCloudDNS/Private", root)
+
+	// validate filetree
+	for _, dir := range []string{parent, public, private} {
+		_, err := utils.ValidateDir(dir, true, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, "GoogleCloudDNS: error exporting zones")
+			return
+		}
+	}
+
+	export := func(id, domain, t, dir string, c Client, fs afero.Fs) error {
+		log.WithFields(log.Fields{
+			"provider": "GoogleCloudDNS",
+			"zone":     strings.TrimSuffix(domain, "."),
+			"type":     t,
+		}).Info("exporting zone")
+
+		r, err := getRecords(id, c)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("GoogleCloudDNS: error retrieving zone '%s' records", domain))
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "GoogleCloudDNS",
+			Private:  t == "private",
+			Records:  r,
+		}
+
+		// write zone exports, named by a managed zone
+		err = records.WriteAs(id, dir, mode, fs)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("GoogleCloudDNS: error exporting zone: '%s'", domain))
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+
+		return nil
+	}
+
+	// export zonefiles
+	for id, domain := range z.Public {
+		if err := export(id, domain, "public", public, c, fs); err != nil {
+			errs <- err
+			return
+		}
+	}
+
+	for id, domain := range z.Private {
+		if err := export(id, domain, "private", private, c, fs); err != nil {
+			errs <- err
+			return
+		}
+	}
+
+	errs <- nil
+}
+
+// getRecords returns all record sets of a managed zone
+func getRecords(id string, c Client) ([]zone.Record, error) {
+	var r []zone.Record
+
+	var t string
+	for {
+		o, err := c.ListResourceRecordSets(id, t)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving zone records")
+		}
+
+		for _, set := range o.ResourceRecordSets {
+			r = append(r, zone.Record{
+				Name:  set.Name,
+				Type:  set.Type,
+				TTL:   set.TTL,
+				Value: set.Rrdatas,
+			})
+		}
+
+		if o.NextPageToken != "" {
+			t = o.NextPageToken
+		} else {
+			break
+		}
+	}
+
+	return r, nil
+}
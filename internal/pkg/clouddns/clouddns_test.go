@@ -0,0 +1,230 @@
+package gcd_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	gcd "dns-exporter/internal/pkg/clouddns"
+	"dns-exporter/internal/pkg/zone"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// fake serves a Cloud DNS REST API of a single project
+func fake(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/projects/project/managedZones": `{
+			"managedZones": [
+				{"id": "1", "name": "domain-com", "dnsName": "domain.com.", "visibility": "public"}
+			],
+			"nextPageToken": "page-2"
+		}`,
+		"/projects/project/managedZones?pageToken=page-2": `{
+			"managedZones": [
+				{"id": "2", "name": "domain-local", "dnsName": "domain.local.", "visibility": "private"},
+				{"id": "3", "name": "domain-local-staging", "dnsName": "domain.local.", "visibility": "private"}
+			]
+		}`,
+		"/projects/project/managedZones/domain-com/rrsets": `{
+			"rrsets": [
+				{"name": "domain.com.", "type": "SOA", "ttl": 21600, "rrdatas": ["ns-cloud-a1.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300"]},
+				{"name": "domain.com.", "type": "A", "ttl": 300, "rrdatas": ["192.168.1.51", "192.168.1.52"]}
+			],
+			"nextPageToken": "page-2"
+		}`,
+		"/projects/project/managedZones/domain-com/rrsets?pageToken=page-2": `{
+			"rrsets": [
+				{"name": "www.domain.com.", "type": "CNAME", "ttl": 300, "rrdatas": ["domain.com."]}
+			]
+		}`,
+		"/projects/project/managedZones/domain-local/rrsets": `{
+			"rrsets": [
+				{"name": "domain.local.", "type": "SOA", "ttl": 21600, "rrdatas": ["ns-gcp-private.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300"]},
+				{"name": "db.domain.local.", "type": "A", "ttl": 60, "rrdatas": ["10.0.0.5"]}
+			]
+		}`,
+		"/projects/project/managedZones/domain-local-staging/rrsets": `{
+			"rrsets": [
+				{"name": "domain.local.", "type": "SOA", "ttl": 21600, "rrdatas": ["ns-gcp-private.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300"]},
+				{"name": "db.domain.local.", "type": "A", "ttl": 60, "rrdatas": ["10.1.0.5"]}
+			]
+		}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			t.Logf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	c := &gcd.API{
+		HTTP:    server.Client(),
+		URL:     server.URL,
+		Project: "project",
+	}
+
+	z := gcd.Zones{
+		Public:  make(map[string]string),
+		Private: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(c, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := gcd.Zones{
+		Public: map[string]string{
+			"domain-com": "domain.com.",
+		},
+		Private: map[string]string{
+			"domain-local":         "domain.local.",
+			"domain-local-staging": "domain.local.",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	server := fake(t)
+	defer server.Close()
+
+	c := &gcd.API{
+		HTTP:    server.Client(),
+		URL:     server.URL,
+		Project: "project",
+	}
+
+	fs := afero.NewMemMapFs()
+
+	z := gcd.Zones{
+		Public: map[string]string{
+			"domain-com": "domain.com.",
+		},
+		Private: map[string]string{
+			"domain-local":         "domain.local.",
+			"domain-local-staging": "domain.local.",
+		},
+	}
+
+	expected := map[string]string{
+		"./GoogleCloudDNS/Public/domain-com.txt": `;; SOA Record
+domain.com.	21600	IN	SOA	ns-cloud-a1.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+domain.com.	300	IN	A	192.168.1.52
+
+;; CNAME Records
+www.domain.com.	300	IN	CNAME	domain.com.
+
+`,
+		"./GoogleCloudDNS/Private/domain-local.txt": `;; SOA Record
+domain.local.	21600	IN	SOA	ns-gcp-private.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300
+
+;; A Records
+db.domain.local.	60	IN	A	10.0.0.5
+
+`,
+		"./GoogleCloudDNS/Private/domain-local-staging.txt": `;; SOA Record
+domain.local.	21600	IN	SOA	ns-gcp-private.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300
+
+;; A Records
+db.domain.local.	60	IN	A	10.1.0.5
+
+`,
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(c, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for file, zonefile := range expected {
+		content, err := afero.ReadFile(fs, file)
+		if err != nil {
+			t.Fatal("error reading exported zonefile:", err)
+		}
+
+		if !reflect.DeepEqual(zonefile, string(content)) {
+			t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", zonefile, string(content))
+		}
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	c := &gcd.API{
+		HTTP:    server.Client(),
+		URL:     server.URL,
+		Project: "project",
+	}
+
+	expected := []zone.Record{
+		{
+			Name:  "domain.local.",
+			Type:  "SOA",
+			TTL:   21600,
+			Value: []string{"ns-gcp-private.googledomains.com. cloud-dns-hostmaster.google.com. 1 21600 3600 259200 300"},
+		},
+		{
+			Name:  "db.domain.local.",
+			Type:  "A",
+			TTL:   60,
+			Value: []string{"10.0.0.5"},
+		},
+	}
+
+	r, err := gcd.GetRecords("domain-local", c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, r) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// unknown zone
+	_, err = gcd.GetRecords("missing", c)
+	if err == nil || !strings.Contains(err.Error(), "unexpected status code 404") {
+		t.Fatal("\nEXPECTED error: \nunexpected status code 404\n\nGOT error:", err)
+	}
+}
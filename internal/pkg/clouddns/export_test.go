@@ -0,0 +1,3 @@
+package gcd
+
+var GetRecords = getRecords
@@ -0,0 +1,57 @@
+package gcd
+
+import (
+	"net/http"
+)
+
+// Zones hosted by a DNS provider, DNS names by managed zone names
+type Zones struct {
+	Public  map[string]string
+	Private map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListManagedZones(pageToken string) (*ManagedZonesResponse, error)
+	ListResourceRecordSets(zone, pageToken string) (*ResourceRecordSetsResponse, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is a Cloud DNS REST API client
+type API struct {
+	HTTP    HTTPClient
+	URL     string
+	Project string
+}
+
+// ManagedZonesResponse is a single page of 'managedZones.list'
+type ManagedZonesResponse struct {
+	ManagedZones  []ManagedZone `json:"managedZones"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// ManagedZone is a Cloud DNS hosted zone
+type ManagedZone struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	DNSName    string `json:"dnsName"`
+	Visibility string `json:"visibility"`
+}
+
+// ResourceRecordSetsResponse is a single page of 'resourceRecordSets.list'
+type ResourceRecordSetsResponse struct {
+	ResourceRecordSets []ResourceRecordSet `json:"rrsets"`
+	NextPageToken      string              `json:"nextPageToken"`
+}
+
+// ResourceRecordSet is a Cloud DNS record set
+type ResourceRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int64    `json:"ttl"`
+	Rrdatas []string `json:"rrdatas"`
+}
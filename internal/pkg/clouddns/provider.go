@@ -0,0 +1,41 @@
+package gcd
+
+import (
+	"sync"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+// Provider binds Cloud DNS managed zones to an authenticated client
+type Provider struct {
+	Client Client
+	Zones  Zones
+}
+
+// NewProvider returns new Google Cloud DNS provider
+func NewProvider(c Client) *Provider {
+	return &Provider{
+		Client: c,
+		Zones: Zones{
+			Public:  make(map[string]string),
+			Private: make(map[string]string),
+		},
+	}
+}
+
+// Name of a provider
+func (p *Provider) Name() string {
+	return "GoogleCloudDNS"
+}
+
+// Fetch managed zones
+func (p *Provider) Fetch(errs chan error, wg *sync.WaitGroup) {
+	p.Zones.Fetch(p.Client, errs, wg)
+}
+
+// Export managed zones
+func (p *Provider) Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	p.Zones.Export(p.Client, delay, mode, errs, wg, root, fs)
+}
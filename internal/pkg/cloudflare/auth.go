@@ -0,0 +1,21 @@
+package cf
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Verify scoped API token is active, a single request that does not depend on a number of zones
+func Verify(c Client) error {
+	t, err := c.VerifyAPIToken(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "CloudFlare: error verifying API token")
+	}
+
+	if t.Status != "active" {
+		return errors.Errorf("CloudFlare: API token is '%s'", t.Status)
+	}
+
+	return nil
+}
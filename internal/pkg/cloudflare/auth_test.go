@@ -0,0 +1,50 @@
+package cf_test
+
+import (
+	"sync"
+	"testing"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/mocks"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+func TestVerify(t *testing.T) {
+	c := mocks.Cloudflare{}
+
+	c.On("VerifyAPIToken").Return(cloudflare.APITokenVerifyBody{Status: "active"}, nil).Once()
+
+	err := cf.Verify(&c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// disabled token
+	c.On("VerifyAPIToken").Return(cloudflare.APITokenVerifyBody{Status: "disabled"}, nil).Once()
+
+	err = cf.Verify(&c)
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nCloudFlare: API token is 'disabled'\n\nGOT error: \n<nil>")
+	}
+
+	// a scoped token is verified before zones are fetched, zones of a disabled token are not
+	c.On("VerifyAPIToken").Return(cloudflare.APITokenVerifyBody{Status: "disabled"}, nil).Once()
+
+	p := cf.NewProvider(&c)
+	p.Scoped = true
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	p.Fetch(errs, &wg)
+
+	err = <-errs
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nCloudFlare: API token is 'disabled'\n\nGOT error: \n<nil>")
+	}
+
+	c.AssertNotCalled(t, "ListZones")
+}
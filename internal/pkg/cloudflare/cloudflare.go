@@ -3,16 +3,18 @@ package cf
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
 
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -36,101 +38,382 @@ func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
 }
 
 // Export hosted zones
-func (z Zones) Export(c HTTPClient, delay int, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root, account string, fs afero.Fs) {
 	defer wg.Done()
 
-	// validate provider export dir
+	// validate provider export dir, every named account is exported into its own subdirectory
 	dir := fmt.Sprintf("%v/CloudFlare", root)
+	if account != "" {
+		dir = fmt.Sprintf("%v/%v", dir, account)
+	}
+
 	_, err := utils.ValidateDir(dir, true, fs)
 	if err != nil {
 		errs <- errors.Wrap(err, "CloudFlare: error exporting zones")
 		return
 	}
 
+	// zones an API token is not permitted to read DNS records of, which are skipped
+	var forbidden []string
+
 	// export zonefiles
 	for domain, id := range z.Public {
-		log.WithFields(log.Fields{
+		fields := log.Fields{
 			"provider": "CloudFlare",
 			"zone":     domain,
-		}).Info("exporting zone")
+		}
+		if account != "" {
+			fields["account"] = account
+		}
+		log.WithFields(fields).Info("exporting zone")
 
-		// export zone
-		content, err := exportZone(c, id)
+		records, err := getRecords(c, id)
 		if err != nil {
+			var unauthorized *cloudflare.AuthorizationError
+			if errors.As(err, &unauthorized) {
+				forbidden = append(forbidden, domain)
+				continue
+			}
+
 			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone: '%s'", domain))
 			return
 		}
 
-		// write zonefile
-		c, err := format(content)
+		export := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "CloudFlare",
+			Records:  records,
+		}
+
+		// write zone exports
+		err = export.Write(dir, mode, fs)
 		if err != nil {
-			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone: '%s': formatter error", domain))
+			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone: '%s'", domain))
 			return
 		}
 
-		_, err = utils.WriteToFile(domain, c, dir, fs)
+		if zone.Enabled(zone.TerraformFormat) {
+			err = zone.WriteConfig(domain, "tf", terraform(id, export.Records), fmt.Sprintf("%s/terraform", dir), fs)
+			if err != nil {
+				errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone Terraform config: '%s'", domain))
+				return
+			}
+		}
+
+		// write zone configuration
+		err = exportSettings(c, id, domain, dir, fs)
 		if err != nil {
-			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone: '%s'", domain))
+			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone settings: '%s'", domain))
 			return
 		}
 
 		time.Sleep(time.Duration(delay) * time.Second)
 	}
 
+	if len(forbidden) > 0 {
+		sort.Strings(forbidden)
+
+		fields := log.Fields{
+			"provider":   "CloudFlare",
+			"zones":      strings.Join(forbidden, ","),
+			"permission": "Zone:DNS:Read",
+		}
+		if account != "" {
+			fields["account"] = account
+		}
+		log.WithFields(fields).Warn("skipping zones, API token is not permitted to read their DNS records")
+	}
+
 	errs <- nil
 }
 
-// exportZone returns zonefile content for specified zone id
-func exportZone(c HTTPClient, zoneID string) ([]byte, error) {
-	// request export
-	request, err := http.NewRequest("GET", fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records/export", zoneID), nil)
+// getRecords returns zone records out of records API, with SOA record built out of zone details as records API does not expose it
+func getRecords(c Client, id string) ([]zone.Record, error) {
+	details, err := c.ZoneDetails(context.Background(), id)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("CloudFlare: error constructing HTTP request (id='%s')", zoneID))
+		return nil, errors.Wrap(err, "error retrieving zone details")
 	}
 
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-Auth-Email", os.Getenv("CLOUDFLARE_EMAIL"))
-	request.Header.Add("X-Auth-Key", os.Getenv("CLOUDFLARE_TOKEN"))
+	var records []zone.Record
+	if soa, ok := soa(details); ok {
+		records = append(records, soa)
+	}
 
-	response, err := c.Do(request)
+	list, _, err := c.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(id), cloudflare.ListDNSRecordsParams{})
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("CloudFlare: error consuming '/client/v4/zones/%s/dns_records/export'", zoneID))
+		var unauthorized *cloudflare.AuthorizationError
+		if errors.As(err, &unauthorized) {
+			return nil, errors.Wrap(err, "error listing DNS records, an API token requires 'Zone:DNS:Read' permission")
+		}
+
+		return nil, errors.Wrap(err, "error listing DNS records")
+	}
+
+	for _, r := range list {
+		records = append(records, convert(r))
 	}
 
-	b, err := ioutil.ReadAll(response.Body)
+	return records, nil
+}
+
+// soa returns SOA record of a zone out of its primary name server and CloudFlare default SOA timers.
+// Serial is static, it changes on every zone change and would otherwise change every export.
+func soa(z cloudflare.Zone) (zone.Record, bool) {
+	if len(z.NameServers) == 0 {
+		return zone.Record{}, false
+	}
+
+	return zone.Record{
+		Name:  fqdn(z.Name),
+		Type:  "SOA",
+		TTL:   3600,
+		Value: []string{fmt.Sprintf("%s dns.cloudflare.com. 1 10000 2400 604800 1800", fqdn(z.NameServers[0]))},
+	}, true
+}
+
+// convert records API DNS record into a zonefile record
+func convert(r cloudflare.DNSRecord) zone.Record {
+	value := r.Content
+
+	switch r.Type {
+	case "CNAME", "NS", "PTR", "MX":
+		value = fqdn(value)
+	case "SRV":
+		// content is 'weight port target' with the target not qualified
+		fields := strings.Fields(value)
+		if len(fields) > 0 {
+			fields[len(fields)-1] = fqdn(fields[len(fields)-1])
+			value = strings.Join(fields, " ")
+		}
+	case "TXT", "SPF":
+		if !strings.HasPrefix(value, `"`) {
+			value = zone.QuoteTXT(value)
+		}
+	}
+
+	// priority is a separate field of records API
+	if r.Priority != nil && (r.Type == "MX" || r.Type == "SRV" || r.Type == "URI") {
+		value = fmt.Sprintf("%v %s", *r.Priority, value)
+	}
+
+	return zone.Record{
+		ID:      r.ID,
+		Name:    fqdn(r.Name),
+		Type:    r.Type,
+		TTL:     int64(r.TTL),
+		Value:   []string{value},
+		Proxied: r.Proxied,
+		AutoTTL: r.TTL == 1,
+		Comment: r.Comment,
+		Tags:    r.Tags,
+	}
+}
+
+// fqdn returns fully qualified domain name
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	return name + "."
+}
+
+// field of structured record data
+type field struct {
+	Name  string
+	Value interface{}
+}
+
+// params of a records API DNS record, either a content or structured data of types that records API keeps structured
+type params struct {
+	Content  string
+	Priority *uint16
+	Data     []field
+}
+
+// parameters returns records API parameters of a record, reverting 'convert'
+func parameters(r zone.Record) (params, error) {
+	if len(r.Value) == 0 {
+		return params{}, fmt.Errorf("no value")
+	}
+
+	rr, err := dns.NewRR(fmt.Sprintf("%s %v IN %s %s", fqdn(r.Name), r.TTL, r.Type, r.Value[0]))
+	if err != nil || rr == nil {
+		return params{}, fmt.Errorf("invalid value")
+	}
+
+	var p params
+
+	switch v := rr.(type) {
+	case *dns.A, *dns.AAAA:
+		p.Content = r.Value[0]
+	case *dns.CNAME:
+		p.Content = strings.TrimSuffix(v.Target, ".")
+	case *dns.NS:
+		p.Content = strings.TrimSuffix(v.Ns, ".")
+	case *dns.PTR:
+		p.Content = strings.TrimSuffix(v.Ptr, ".")
+	case *dns.TXT:
+		p.Content = content(r.Value[0])
+	case *dns.SPF:
+		p.Content = content(r.Value[0])
+	case *dns.MX:
+		p.Content = strings.TrimSuffix(v.Mx, ".")
+		p.Priority = &v.Preference
+	case *dns.URI:
+		p.Priority = &v.Priority
+		p.Data = []field{{"weight", v.Weight}, {"target", v.Target}}
+	case *dns.SRV:
+		p.Data = []field{{"priority", v.Priority}, {"weight", v.Weight}, {"port", v.Port}, {"target", strings.TrimSuffix(v.Target, ".")}}
+	case *dns.CAA:
+		p.Data = []field{{"flags", v.Flag}, {"tag", v.Tag}, {"value", v.Value}}
+	case *dns.TLSA:
+		p.Data = []field{{"usage", v.Usage}, {"selector", v.Selector}, {"matching_type", v.MatchingType}, {"certificate", v.Certificate}}
+	case *dns.SSHFP:
+		p.Data = []field{{"algorithm", v.Algorithm}, {"type", v.Type}, {"fingerprint", v.FingerPrint}}
+	case *dns.DS:
+		p.Data = []field{{"key_tag", v.KeyTag}, {"algorithm", v.Algorithm}, {"digest_type", v.DigestType}, {"digest", v.Digest}}
+	case *dns.NAPTR:
+		p.Data = []field{{"order", v.Order}, {"preference", v.Preference}, {"flags", v.Flags}, {"service", v.Service}, {"regex", v.Regexp}, {"replacement", v.Replacement}}
+	case *dns.HTTPS:
+		p.Data = svcb(&v.SVCB)
+	case *dns.SVCB:
+		p.Data = svcb(v)
+	default:
+		return params{}, fmt.Errorf("unsupported type")
+	}
+
+	return p, nil
+}
+
+// svcb returns structured data of SVCB and HTTPS records
+func svcb(v *dns.SVCB) []field {
+	var values []string
+	for _, p := range v.Value {
+		values = append(values, fmt.Sprintf("%s=%q", p.Key(), p.String()))
+	}
+
+	return []field{{"priority", v.Priority}, {"target", v.Target}, {"value", strings.Join(values, " ")}}
+}
+
+// content returns TXT record content of a records API, reverting quotes added by 'convert'.
+// Content of several character strings is quoted by CloudFlare itself and is kept as is.
+func content(value string) string {
+	if s, err := strconv.Unquote(value); err == nil {
+		return s
+	}
+
+	return value
+}
+
+// annotation of a zonefile record that carries its proxied status
+var annotation = regexp.MustCompile(`^;\s*proxied=(true|false)\b`)
+
+// rows of CNAME records a strict zonefile keeps as comments, as a DNS server would reject them
+var commented = regexp.MustCompile(`^; (?:CNAME at zone apex|CNAME alongside other data): (.+)$`)
+
+// ReadZonefile returns a zone out of its zonefile export, annotated or strict, for backups without a JSON or YAML export.
+// Proxied status is recovered from record annotations, record IDs, comments and tags are not kept by a zonefile.
+func ReadZonefile(name string, content []byte) (zone.Zone, error) {
+	var rows []string
+	var proxied string
+
+	for _, row := range strings.Split(string(content), "\n") {
+		row = strings.TrimSpace(row)
+		if m := commented.FindStringSubmatch(row); m != nil {
+			row = m[1]
+		}
+
+		switch {
+		case strings.HasPrefix(row, "$"):
+			continue
+		case strings.HasPrefix(row, ";"):
+			if m := annotation.FindStringSubmatch(row); m != nil {
+				proxied = m[1]
+			}
+		case row != "" && proxied != "":
+			// annotations precede a record, which has a single value in CloudFlare
+			row = fmt.Sprintf("%s ; cf_tags=cf-proxied:%s", row, proxied)
+			proxied = ""
+		}
+
+		rows = append(rows, row)
+	}
+
+	records, err := parse([]byte(strings.Join(rows, "\n")))
 	if err != nil {
-		return nil, errors.Wrap(err, "CloudFlare: error reading responce body")
+		return zone.Zone{}, errors.Wrap(err, "error parsing zonefile export")
 	}
 
-	return b, nil
+	for i := range records {
+		records[i].AutoTTL = records[i].Type != "SOA" && records[i].TTL == 1
+	}
+
+	return zone.Zone{
+		Name:     name,
+		Provider: "CloudFlare",
+		Records:  records,
+	}, nil
 }
 
-// format returns a formatted zonefile content
-func format(input []byte) (string, error) {
-	// replace SOA record 'serial' field, which is a unixtime of a query
-	// this field should be static in order for operator to be able to track changes through git easily
-	// otherwise, every zone is updated on each run
-	body := strings.Split(
-		string(input),
-		";; SOA Record",
-	)[1]
+// parse returns zone records of a BIND formatted export
+func parse(input []byte) ([]zone.Record, error) {
+	record := regexp.MustCompile(`^(?P<name>\S+)\s+(?P<ttl>\d+)\s+(?P<class>IN)\s+(?P<type>[A-Z0-9]+)\s+(?P<value>.*?)\s*$`)
+	tags := regexp.MustCompile(`\s*;\s*cf_tags=(?P<tags>\S*)$`)
 
-	body = strings.TrimPrefix(body, "\n")
+	var records []zone.Record
+	var soa bool
 
-	rows := strings.Split(body, "\n")
+	for _, row := range strings.Split(string(input), "\n") {
+		row = strings.TrimSpace(row)
+		if row == "" || strings.HasPrefix(row, ";") {
+			continue
+		}
 
-	expression := `^(?P<name>\S*)\s*(?P<ttl>\d*)\s*(?P<class>IN*)\s*(?P<abbrevation>SOA*)\s*(?P<nameserver>\S*)\s*(?P<email>\S*)\s*(?P<serial>\d*)\s*(?P<refresh>\d*)\s*(?P<retry>\d*)\s*(?P<expiry>\d*)\s*(?P<minimum>\d*)\s*$`
-	regex := regexp.MustCompile(expression)
+		m := record.FindStringSubmatch(row)
+		if m == nil {
+			return nil, fmt.Errorf("error matching record '%s'", row)
+		}
+
+		ttl, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error parsing TTL of record '%s'", row))
+		}
+
+		r := zone.Record{
+			Name: m[1],
+			Type: m[4],
+			TTL:  ttl,
+		}
 
-	if regex.MatchString(rows[0]) {
-		var content []string
+		value := m[5]
+		if t := tags.FindStringSubmatch(value); t != nil {
+			value = strings.TrimSuffix(value, t[0])
+			proxied := strings.Contains(t[1], "cf-proxied:true")
+			r.Proxied = &proxied
+		}
 
-		content = append(content, ";; SOA Record")
-		content = append(content, regex.ReplaceAllString(rows[0], "${1}\t${2}\t${3}\t${4}\t${5} ${6} 1 ${8} ${9} ${10} ${11}"))
-		content = append(content, rows[1:]...)
+		// replace SOA record 'serial' field, which is a unixtime of a query
+		// this field should be static in order for operator to be able to track changes through git easily
+		// otherwise, every zone is updated on each run
+		if r.Type == "SOA" {
+			fields := strings.Fields(value)
+			if len(fields) != 7 {
+				return nil, errors.New("error matching SOA record")
+			}
+			fields[2] = "1"
+			value = strings.Join(fields, " ")
+			soa = true
+		}
+
+		r.Value = []string{value}
+		records = append(records, r)
+	}
 
-		return strings.Join(content, "\n"), nil
+	if !soa {
+		return nil, errors.New("error matching SOA record")
 	}
 
-	return "", errors.New("error matching SOA record")
+	return records, nil
 }
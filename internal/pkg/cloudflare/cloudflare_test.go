@@ -1,16 +1,16 @@
 package cf_test
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
 
 	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/internal/pkg/zone"
 
 	"dns-exporter/mocks"
 
@@ -66,7 +66,7 @@ func TestFetch(t *testing.T) {
 func TestExport(t *testing.T) {
 	log.SetLevel(log.ErrorLevel)
 
-	c := mocks.HTTP{}
+	c := mocks.Cloudflare{}
 
 	z := cf.Zones{
 		Public: map[string]string{
@@ -75,121 +75,88 @@ func TestExport(t *testing.T) {
 		},
 	}
 
-	zonefiles := map[string]string{
-		z.Public["domain1.com"]: `;;
-;; Domain:     domain1.com.
-;; Exported:   2019-10-19 18:27:26
-;;
-;; This file is intended for use for informational and archival
-;; purposes ONLY and MUST be edited before use on a production
-;; DNS server.  In particular, you must:
-;;   -- update the SOA record with the correct authoritative name server
-;;   -- update the SOA record with the contact e-mail address information
-;;   -- update the NS record(s) with the authoritative name servers for this domain.
-;;
-;; For further information, please consult the BIND documentation
-;; located on the following website:
-;;
-;; http://www.isc.org/
-;;
-;; And RFC 1035:
-;;
-;; http://www.ietf.org/rfc/rfc1035.txt
-;;
-;; Please note that we do NOT offer technical support for any use
-;; of this zone data, the BIND name server, or any other third-party
-;; DNS software.
-;;
-;; Use at your own risk.
-
-;; SOA Record
-domain1.com.	3600	IN	SOA	domain1.com. root.domain1.com. 2032317624 7200 3600 86400 3600
-
-;; A Records
-*.domain1.com.	1	IN	A	1.2.3.4
-domain1.com.	1	IN	A	1.2.3.4
-
-;; CNAME Records
-www.domain1.com.	1	IN	CNAME	domain1.com.`,
-		z.Public["domain2.com"]: `;;
-;; Domain:     domain2.com.
-;; Exported:   2019-10-19 18:27:26
-;;
-;; This file is intended for use for informational and archival
-;; purposes ONLY and MUST be edited before use on a production
-;; DNS server.  In particular, you must:
-;;   -- update the SOA record with the correct authoritative name server
-;;   -- update the SOA record with the contact e-mail address information
-;;   -- update the NS record(s) with the authoritative name servers for this domain.
-;;
-;; For further information, please consult the BIND documentation
-;; located on the following website:
-;;
-;; http://www.isc.org/
-;;
-;; And RFC 1035:
-;;
-;; http://www.ietf.org/rfc/rfc1035.txt
-;;
-;; Please note that we do NOT offer technical support for any use
-;; of this zone data, the BIND name server, or any other third-party
-;; DNS software.
-;;
-;; Use at your own risk.
-
-;; SOA Record
-domain2.com.	3600	IN	SOA	domain2.com. root.domain2.com. 2032317624 7200 3600 86400 3600
-
-;; A Records
-*.domain2.com.	1	IN	A	1.2.3.4
-domain2.com.	1	IN	A	1.2.3.4
-
-;; CNAME Records
-www.domain2.com.	1	IN	CNAME	domain2.com.`,
-	}
-
 	expected := map[string]string{
 		z.Public["domain1.com"]: `;; SOA Record
-domain1.com.	3600	IN	SOA	domain1.com. root.domain1.com. 1 7200 3600 86400 3600
+domain1.com.	3600	IN	SOA	ada.ns.cloudflare.com. dns.cloudflare.com. 1 10000 2400 604800 1800
 
 ;; A Records
+; proxied=true ttl=auto
 *.domain1.com.	1	IN	A	1.2.3.4
-domain1.com.	1	IN	A	1.2.3.4
+; proxied=false
+domain1.com.	300	IN	A	1.2.3.5
 
 ;; CNAME Records
-www.domain1.com.	1	IN	CNAME	domain1.com.`,
+; web frontend
+; proxied=true ttl=auto tags="env:prod,team:web"
+www.domain1.com.	1	IN	CNAME	domain1.com.
+
+`,
 		z.Public["domain2.com"]: `;; SOA Record
-domain2.com.	3600	IN	SOA	domain2.com. root.domain2.com. 1 7200 3600 86400 3600
+domain2.com.	3600	IN	SOA	ada.ns.cloudflare.com. dns.cloudflare.com. 1 10000 2400 604800 1800
 
 ;; A Records
+; proxied=true ttl=auto
 *.domain2.com.	1	IN	A	1.2.3.4
-domain2.com.	1	IN	A	1.2.3.4
+; proxied=false
+domain2.com.	300	IN	A	1.2.3.5
 
 ;; CNAME Records
-www.domain2.com.	1	IN	CNAME	domain2.com.`,
-	}
+; web frontend
+; proxied=true ttl=auto tags="env:prod,team:web"
+www.domain2.com.	1	IN	CNAME	domain2.com.
 
-	fs := afero.NewMemMapFs()
+`,
+	}
 
-	errs := make(chan error, len(zonefiles))
+	reply := map[string]string{
+		z.Public["domain1.com"]: "domain1.com",
+		z.Public["domain2.com"]: "domain2.com",
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(zonefiles))
+	proxied, direct := true, false
 
-	for i, z := range zonefiles {
-		request, _ := http.NewRequest("GET", fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records/export", i), nil)
-		request.Header.Add("Content-Type", "application/json")
-		request.Header.Add("X-Auth-Email", "")
-		request.Header.Add("X-Auth-Key", "")
+	fs := afero.NewMemMapFs()
 
-		response := http.Response{
-			Body: ioutil.NopCloser(bytes.NewBufferString(z)),
-		}
+	errs := make(chan error, len(reply))
 
-		c.On("Do", request).Return(&response, nil).Once()
+	var wg sync.WaitGroup
+	wg.Add(len(reply))
+
+	for i, domain := range reply {
+		c.On("ZoneDetails", i).Return(cloudflare.Zone{ID: i, Name: domain, NameServers: []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}}, nil).Once()
+		c.On("ListDNSRecords", i).Return([]cloudflare.DNSRecord{
+			{
+				Type:    "A",
+				Name:    fmt.Sprintf("*.%s", domain),
+				Content: "1.2.3.4",
+				TTL:     1,
+				Proxied: &proxied,
+			},
+			{
+				Type:    "A",
+				Name:    domain,
+				Content: "1.2.3.5",
+				TTL:     300,
+				Proxied: &direct,
+			},
+			{
+				Type:    "CNAME",
+				Name:    fmt.Sprintf("www.%s", domain),
+				Content: domain,
+				TTL:     1,
+				Proxied: &proxied,
+				Comment: "web frontend",
+				Tags:    []string{"env:prod", "team:web"},
+			},
+		}, nil).Once()
+
+		c.On("ZoneSettings", i).Return(&cloudflare.ZoneSettingResponse{}, nil).Once()
+		c.On("ZoneDNSSECSetting", i).Return(cloudflare.ZoneDNSSEC{Status: "disabled"}, nil).Once()
+		c.On("ListPageRules", i).Return([]cloudflare.PageRule{}, nil).Once()
+		c.On("GetZoneRulesetPhase", i, "http_request_dynamic_redirect").Return(cloudflare.Ruleset{}, nil).Once()
 	}
 
-	z.Export(&c, 0, errs, &wg, "./", fs)
+	z.Export(&c, 0, zone.Annotated, errs, &wg, "./", "", fs)
 
 	err := <-errs
 	if err != nil {
@@ -208,77 +175,326 @@ www.domain2.com.	1	IN	CNAME	domain2.com.`,
 	}
 }
 
-func TestExportZone(t *testing.T) {
-	c := mocks.HTTP{}
+func TestExportDenied(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Cloudflare{}
+	fs := afero.NewMemMapFs()
+
+	z := cf.Zones{
+		Public: map[string]string{
+			"domain1.com": "1",
+			"domain2.com": "2",
+		},
+	}
+
+	forbidden := cloudflare.NewAuthorizationError(&cloudflare.Error{StatusCode: 403, ErrorMessages: []string{"Unauthorized to access requested resource"}})
+
+	c.On("ZoneDetails", "1").Return(cloudflare.Zone{ID: "1", Name: "domain1.com"}, nil).Once()
+	c.On("ListDNSRecords", "1").Return([]cloudflare.DNSRecord{{Type: "A", Name: "domain1.com", Content: "1.2.3.4", TTL: 300}}, nil).Once()
+	c.On("ZoneSettings", "1").Return(&cloudflare.ZoneSettingResponse{}, nil).Once()
+	c.On("ZoneDNSSECSetting", "1").Return(cloudflare.ZoneDNSSEC{Status: "disabled"}, nil).Once()
+	c.On("ListPageRules", "1").Return([]cloudflare.PageRule{}, nil).Once()
+	c.On("GetZoneRulesetPhase", "1", "http_request_dynamic_redirect").Return(cloudflare.Ruleset{}, nil).Once()
+
+	c.On("ZoneDetails", "2").Return(cloudflare.Zone{ID: "2", Name: "domain2.com"}, nil).Once()
+	c.On("ListDNSRecords", "2").Return([]cloudflare.DNSRecord{}, &forbidden).Once()
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&c, 0, zone.Annotated, errs, &wg, "./", "", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+
+	// zones the token is permitted to read are exported only
+	for path, expected := range map[string]bool{
+		"./CloudFlare/domain1-com.txt": true,
+		"./CloudFlare/domain2-com.txt": false,
+	} {
+		ok, err := afero.Exists(fs, path)
+		if err != nil || ok != expected {
+			t.Errorf("\nEXPECTED path '%s' exists: \n%t\n\nGOT exists: \n%t\n\n", path, expected, ok)
+		}
+	}
+}
+
+func TestGetRecordsError(t *testing.T) {
+	c := mocks.Cloudflare{}
 
-	// email := "user@domain.com"
-	// token := "123abc"
-	id := "123ab14fd747995cccc52a23b4ccc482"
+	forbidden := cloudflare.NewAuthorizationError(&cloudflare.Error{StatusCode: 403, ErrorMessages: []string{"Unauthorized to access requested resource"}})
 
-	request, _ := http.NewRequest("GET", fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records/export", id), nil)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-Auth-Email", "")
-	request.Header.Add("X-Auth-Key", "")
+	c.On("ZoneDetails", "1").Return(cloudflare.Zone{ID: "1", Name: "domain1.com"}, nil).Twice()
+	c.On("ListDNSRecords", "1").Return([]cloudflare.DNSRecord{}, &forbidden).Once()
+	c.On("ListDNSRecords", "1").Return([]cloudflare.DNSRecord{}, errors.New("connection reset")).Once()
+
+	// permission hint is given on authorization errors only
+	for _, expected := range []bool{true, false} {
+		_, err := cf.GetRecords(&c, "1")
+		if err == nil || strings.Contains(err.Error(), "Zone:DNS:Read") != expected {
+			t.Errorf("\nEXPECTED permission hint: \n%t\n\nGOT error: \n%v\n\n", expected, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	proxied, direct := true, false
 
 	text := `;;
 ;; Domain:     domain.com.
 ;; Exported:   2019-10-19 18:27:26
 ;;
-;; This file is intended for use for informational and archival
-;; purposes ONLY and MUST be edited before use on a production
-;; DNS server.  In particular, you must:
-;;   -- update the SOA record with the correct authoritative name server
-;;   -- update the SOA record with the contact e-mail address information
-;;   -- update the NS record(s) with the authoritative name servers for this domain.
-;;
-;; For further information, please consult the BIND documentation
-;; located on the following website:
-;;
-;; http://www.isc.org/
-;;
-;; And RFC 1035:
-;;
-;; http://www.ietf.org/rfc/rfc1035.txt
-;;
-;; Please note that we do NOT offer technical support for any use
-;; of this zone data, the BIND name server, or any other third-party
-;; DNS software.
-;;
-;; Use at your own risk.
 
 ;; SOA Record
 domain.com.	3600	IN	SOA	domain.com. root.domain.com. 2032317624 7200 3600 86400 3600
 
 ;; A Records
-*.domain.com.	1	IN	A	1.2.3.4
-domain.com.	1	IN	A	1.2.3.4
+domain.com.	1	IN	A	1.2.3.4 ; cf_tags=cf-proxied:true
+direct.domain.com.	300	IN	A	1.2.3.5 ; cf_tags=cf-proxied:false
 
-;; CNAME Records
-www.domain.com.	1	IN	CNAME	domain.com.`
+;; TXT Records
+txt.domain.com.	1	IN	TXT	"v=spf1 include:_spf.domain.com ~all"`
+
+	expected := []zone.Record{
+		{
+			Name:  "domain.com.",
+			Type:  "SOA",
+			TTL:   3600,
+			Value: []string{"domain.com. root.domain.com. 1 7200 3600 86400 3600"},
+		},
+		{
+			Name:    "domain.com.",
+			Type:    "A",
+			TTL:     1,
+			Value:   []string{"1.2.3.4"},
+			Proxied: &proxied,
+		},
+		{
+			Name:    "direct.domain.com.",
+			Type:    "A",
+			TTL:     300,
+			Value:   []string{"1.2.3.5"},
+			Proxied: &direct,
+		},
+		{
+			Name:  "txt.domain.com.",
+			Type:  "TXT",
+			TTL:   1,
+			Value: []string{"\"v=spf1 include:_spf.domain.com ~all\""},
+		},
+	}
+
+	records, err := cf.Parse([]byte(text))
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, records) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, records)
+	}
 
-	body := bytes.NewBufferString(text)
+	// missing SOA record
+	_, err = cf.Parse([]byte("domain.com.\t1\tIN\tA\t1.2.3.4"))
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nerror matching SOA record\n\nGOT error: \n<nil>")
+	}
+}
 
-	content := bytes.NewBufferString(text)
+func TestReadZonefile(t *testing.T) {
+	proxied, direct := true, false
+
+	export := zone.Zone{
+		Name:     "domain.com",
+		ID:       "zone-1",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.com. dns.cloudflare.com. 1 10000 2400 604800 1800"}},
+			{ID: "r1", Name: "domain.com.", Type: "A", TTL: 1, AutoTTL: true, Value: []string{"1.2.3.4"}, Proxied: &proxied, Comment: "origin", Tags: []string{"env:prod"}},
+			{ID: "r2", Name: "direct.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}, Proxied: &direct},
+			{ID: "r3", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &direct},
+			{ID: "r4", Name: "domain.com.", Type: "TXT", TTL: 1, AutoTTL: true, Value: []string{`"v=spf1 -all"`}, Proxied: &direct},
+			{ID: "r5", Name: "www.domain.com.", Type: "CNAME", TTL: 1, AutoTTL: true, Value: []string{"domain.com."}, Proxied: &proxied},
+			{ID: "r6", Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"verification"`}, Proxied: &direct},
+		},
+	}
 
-	response := http.Response{
-		Body: ioutil.NopCloser(body),
+	// record IDs, comments and tags are not kept by a zonefile
+	expected := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.com. dns.cloudflare.com. 1 10000 2400 604800 1800"}},
+			{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &direct},
+			{Name: "domain.com.", Type: "A", TTL: 1, AutoTTL: true, Value: []string{"1.2.3.4"}, Proxied: &proxied},
+			{Name: "direct.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}, Proxied: &direct},
+			{Name: "domain.com.", Type: "TXT", TTL: 1, AutoTTL: true, Value: []string{`"v=spf1 -all"`}, Proxied: &direct},
+			{Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"verification"`}, Proxied: &direct},
+			{Name: "www.domain.com.", Type: "CNAME", TTL: 1, AutoTTL: true, Value: []string{"domain.com."}, Proxied: &proxied},
+		},
 	}
 
-	c.On("Do", request).Return(&response, nil).Once()
+	for _, mode := range []zone.Mode{zone.Annotated, zone.Strict} {
+		content, err := export.Zonefile(mode)
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
 
-	responce, err := cf.ExportZone(&c, id)
+		z, err := cf.ReadZonefile("domain.com.", content.Bytes())
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", mode, err)
+		}
 
+		// strict zonefiles list commented out records last
+		sorted(z.Records)
+		sorted(expected.Records)
+
+		if !reflect.DeepEqual(expected, z) {
+			t.Errorf("%s: \nEXPECTED zone: \n%+v\n\nGOT zone: \n%+v\n\n", mode, expected, z)
+		}
+	}
+
+	// apex CNAME, flattened by CloudFlare, is commented out of a strict zonefile and read back with its proxied status
+	apex := zone.Zone{
+		Name:     "domain.org",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{Name: "domain.org.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.org. dns.cloudflare.com. 1 10000 2400 604800 1800"}},
+			{Name: "domain.org.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}, Proxied: &proxied},
+		},
+	}
+
+	content, err := apex.Zonefile(zone.Strict)
 	if err != nil {
 		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
 	}
 
-	expected, err := ioutil.ReadAll(content)
+	if !strings.Contains(content.String(), "; CNAME at zone apex: domain.org.") {
+		t.Fatalf("\nEXPECTED apex CNAME to be commented out\n\nGOT zonefile: \n%s\n\n", content.String())
+	}
 
+	z, err := cf.ReadZonefile("domain.org.", content.Bytes())
 	if err != nil {
-		t.Fatal("error reading responce body into []byte")
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
 	}
 
-	if !reflect.DeepEqual([]byte(expected), responce) {
-		t.Errorf("\nEXPECTED content: \n%+v\n\nGOT content: \n%+v\n\n", string(expected), string(responce))
+	apex.Name = "domain.org."
+	if !reflect.DeepEqual(apex, z) {
+		t.Errorf("\nEXPECTED zone: \n%+v\n\nGOT zone: \n%+v\n\n", apex, z)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	proxied := true
+	priority := uint16(10)
+
+	suite := map[string]struct {
+		input    cloudflare.DNSRecord
+		expected zone.Record
+	}{
+		"proxied": {
+			input: cloudflare.DNSRecord{
+				Type:    "A",
+				Name:    "domain.com",
+				Content: "1.2.3.4",
+				TTL:     1,
+				Proxied: &proxied,
+				Comment: "origin",
+				Tags:    []string{"env:prod"},
+			},
+			expected: zone.Record{
+				Name:    "domain.com.",
+				Type:    "A",
+				TTL:     1,
+				Value:   []string{"1.2.3.4"},
+				Proxied: &proxied,
+				AutoTTL: true,
+				Comment: "origin",
+				Tags:    []string{"env:prod"},
+			},
+		},
+		"mx": {
+			input: cloudflare.DNSRecord{
+				Type:     "MX",
+				Name:     "domain.com",
+				Content:  "mail.domain.com",
+				TTL:      3600,
+				Priority: &priority,
+			},
+			expected: zone.Record{
+				Name:  "domain.com.",
+				Type:  "MX",
+				TTL:   3600,
+				Value: []string{"10 mail.domain.com."},
+			},
+		},
+		"txt": {
+			input: cloudflare.DNSRecord{
+				Type:    "TXT",
+				Name:    "domain.com",
+				Content: "v=spf1 include:_spf.domain.com ~all",
+				TTL:     300,
+			},
+			expected: zone.Record{
+				Name:  "domain.com.",
+				Type:  "TXT",
+				TTL:   300,
+				Value: []string{"\"v=spf1 include:_spf.domain.com ~all\""},
+			},
+		},
+		"srv": {
+			input: cloudflare.DNSRecord{
+				Type:     "SRV",
+				Name:     "_sip._tcp.domain.com",
+				Content:  "10 5060 sip.domain.com",
+				TTL:      300,
+				Priority: &priority,
+			},
+			expected: zone.Record{
+				Name:  "_sip._tcp.domain.com.",
+				Type:  "SRV",
+				TTL:   300,
+				Value: []string{"10 10 5060 sip.domain.com."},
+			},
+		},
+		"txt escapes": {
+			input: cloudflare.DNSRecord{
+				Type:    "TXT",
+				Name:    "domain.com",
+				Content: "caf\u00e9\ttab",
+				TTL:     300,
+			},
+			expected: zone.Record{
+				Name:  "domain.com.",
+				Type:  "TXT",
+				TTL:   300,
+				Value: []string{`"caf\195\169\009tab"`},
+			},
+		},
 	}
+
+	for name, test := range suite {
+		got := cf.Convert(test.input)
+
+		if !reflect.DeepEqual(test.expected, got) {
+			t.Errorf("\n%s\nEXPECTED record: \n%+v\n\nGOT record: \n%+v\n\n", name, test.expected, got)
+		}
+	}
+}
+
+// sorted orders records by their name and type
+func sorted(records []zone.Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+
+		return records[i].Type < records[j].Type
+	})
 }
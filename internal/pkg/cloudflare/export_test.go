@@ -1,4 +1,9 @@
 package cf
 
 // export for testing
-var ExportZone = exportZone
+var Parse = parse
+var GetRecords = getRecords
+var Convert = convert
+var ExportSettings = exportSettings
+var Terraform = terraform
+var Diff = diff
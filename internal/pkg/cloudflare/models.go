@@ -3,7 +3,6 @@ package cf
 import (
 	"context"
 	"github.com/cloudflare/cloudflare-go"
-	"net/http"
 )
 
 // Zones hosted by a DNS provider
@@ -14,9 +13,34 @@ type Zones struct {
 // Client interface
 type Client interface {
 	ListZones(context.Context, ...string) ([]cloudflare.Zone, error)
+	ZoneDetails(context.Context, string) (cloudflare.Zone, error)
+	VerifyAPIToken(context.Context) (cloudflare.APITokenVerifyBody, error)
+	ListDNSRecords(context.Context, *cloudflare.ResourceContainer, cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error)
+	ZoneSettings(context.Context, string) (*cloudflare.ZoneSettingResponse, error)
+	ZoneDNSSECSetting(context.Context, string) (cloudflare.ZoneDNSSEC, error)
+	ListPageRules(context.Context, string) ([]cloudflare.PageRule, error)
+	GetZoneRulesetPhase(context.Context, string, string) (cloudflare.Ruleset, error)
+	CreateDNSRecord(context.Context, *cloudflare.ResourceContainer, cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error)
+	UpdateDNSRecord(context.Context, *cloudflare.ResourceContainer, cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error)
+	DeleteDNSRecord(context.Context, *cloudflare.ResourceContainer, string) error
 }
 
-// HTTPClient interface
-type HTTPClient interface {
-	Do(*http.Request) (*http.Response, error)
+// Credentials of a CloudFlare account
+type Credentials struct {
+	Email string
+	Key   string
+	Token string
+}
+
+// DNSSEC status of a zone, key and DS record are set for a signed zone only
+type DNSSEC struct {
+	Status     string `json:"status"`
+	Flags      int    `json:"flags,omitempty"`
+	Algorithm  string `json:"algorithm,omitempty"`
+	KeyType    string `json:"key_type,omitempty"`
+	KeyTag     int    `json:"key_tag,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+	DigestType string `json:"digest_type,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	DS         string `json:"ds,omitempty"`
 }
@@ -0,0 +1,51 @@
+package cf
+
+import (
+	"sync"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+// Provider binds CloudFlare zones to an authenticated client
+type Provider struct {
+	Client  Client
+	Zones   Zones
+	Account string
+	// Scoped API token is verified before zones are fetched
+	Scoped bool
+}
+
+// NewProvider returns new CloudFlare provider
+func NewProvider(c Client) *Provider {
+	return &Provider{
+		Client: c,
+		Zones: Zones{
+			Public: make(map[string]string),
+		},
+	}
+}
+
+// Name of a provider
+func (p *Provider) Name() string {
+	return "CloudFlare"
+}
+
+// Fetch hosted zones
+func (p *Provider) Fetch(errs chan error, wg *sync.WaitGroup) {
+	if p.Scoped {
+		if err := Verify(p.Client); err != nil {
+			wg.Done()
+			errs <- err
+			return
+		}
+	}
+
+	p.Zones.Fetch(p.Client, errs, wg)
+}
+
+// Export hosted zones
+func (p *Provider) Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	p.Zones.Export(p.Client, delay, mode, errs, wg, root, p.Account, fs)
+}
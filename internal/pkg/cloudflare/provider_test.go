@@ -0,0 +1,90 @@
+package cf_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/cloudflare/cloudflare-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestProviderFetch(t *testing.T) {
+	c := mocks.Cloudflare{}
+
+	p := cf.NewProvider(&c)
+
+	if p.Name() != "CloudFlare" {
+		t.Errorf("\nEXPECTED name: \nCloudFlare\n\nGOT name: \n%s\n\n", p.Name())
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	c.On("ListZones").Return([]cloudflare.Zone{{ID: "1", Name: "domain.com"}}, nil).Once()
+
+	p.Fetch(errs, &wg)
+
+	expected := cf.Zones{
+		Public: map[string]string{
+			"domain.com": "1",
+		},
+	}
+
+	if !reflect.DeepEqual(p.Zones, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, p.Zones)
+	}
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+}
+
+func TestProviderExportAccount(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Cloudflare{}
+
+	p := cf.NewProvider(&c)
+	p.Account = "marketing"
+	p.Zones.Public["domain.com"] = "1"
+
+	fs := afero.NewMemMapFs()
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	c.On("ZoneDetails", "1").Return(cloudflare.Zone{ID: "1", Name: "domain.com", NameServers: []string{"ada.ns.cloudflare.com"}}, nil).Once()
+	c.On("ListDNSRecords", "1").Return([]cloudflare.DNSRecord{{Type: "A", Name: "domain.com", Content: "1.2.3.4", TTL: 300}}, nil).Once()
+	c.On("ZoneSettings", "1").Return(&cloudflare.ZoneSettingResponse{}, nil).Once()
+	c.On("ZoneDNSSECSetting", "1").Return(cloudflare.ZoneDNSSEC{Status: "disabled"}, nil).Once()
+	c.On("ListPageRules", "1").Return([]cloudflare.PageRule{}, nil).Once()
+	c.On("GetZoneRulesetPhase", "1", "http_request_dynamic_redirect").Return(cloudflare.Ruleset{}, nil).Once()
+
+	p.Export(0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	ok, err := afero.Exists(fs, "./CloudFlare/marketing/domain-com.txt")
+	if err != nil || !ok {
+		t.Errorf("\nEXPECTED path: \n./CloudFlare/marketing/domain-com.txt\n\nGOT error: \n%v\n\n", err)
+	}
+
+	ok, err = afero.Exists(fs, "./CloudFlare/marketing/domain.com/settings.json")
+	if err != nil || !ok {
+		t.Errorf("\nEXPECTED path: \n./CloudFlare/marketing/domain.com/settings.json\n\nGOT error: \n%v\n\n", err)
+	}
+}
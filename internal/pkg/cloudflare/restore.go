@@ -0,0 +1,395 @@
+package cf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Update of a live record to its backed up state
+type Update struct {
+	Live   zone.Record
+	Backup zone.Record
+}
+
+// Plan of changes that restore a zone to its backup
+type Plan struct {
+	Zone   string
+	ZoneID string
+	Create []zone.Record
+	Update []Update
+	Delete []zone.Record
+	// Skipped records of a backup that records API cannot recreate
+	Skipped []string
+	// Zonefile backup does not keep record IDs, comments and tags
+	Zonefile bool
+}
+
+// Plan diffs a backup of a zone against the live one, zones have to be fetched beforehand.
+// Records of a zonefile backup keep comments and tags of live records of the same name, type and value, as a zonefile does not have them.
+func (p *Provider) Plan(backup zone.Zone, zonefile bool) (Plan, error) {
+	name := strings.ToLower(strings.TrimSuffix(backup.Name, "."))
+
+	id, ok := p.Zones.Public[name]
+	if !ok {
+		return Plan{}, fmt.Errorf("CloudFlare: zone '%s' does not exist", name)
+	}
+
+	list, _, err := p.Client.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(id), cloudflare.ListDNSRecordsParams{})
+	if err != nil {
+		return Plan{}, errors.Wrap(err, fmt.Sprintf("CloudFlare: error listing zone '%s' records", name))
+	}
+
+	var live []zone.Record
+	for _, r := range list {
+		live = append(live, convert(r))
+	}
+
+	records := backup.Records
+	if zonefile {
+		records = keep(records, live)
+	}
+
+	plan := diff(records, live)
+	plan.Zone = name
+	plan.ZoneID = id
+	plan.Zonefile = zonefile
+
+	return plan, nil
+}
+
+// diff returns changes that turn live records into backed up ones.
+// Records are matched by their ID, records recreated since a backup by their name, type and value.
+func diff(backup, live []zone.Record) Plan {
+	var plan Plan
+
+	remaining := make([]zone.Record, 0, len(live))
+	for _, r := range live {
+		if r.Type != "SOA" {
+			remaining = append(remaining, r)
+		}
+	}
+
+	// match removes a live record of a backed up one from remaining records
+	match := func(r zone.Record) (zone.Record, bool) {
+		for _, identical := range []bool{false, true} {
+			for i, l := range remaining {
+				if (!identical && r.ID != "" && l.ID == r.ID) || (identical && value(l) == value(r)) {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					return l, true
+				}
+			}
+		}
+
+		return zone.Record{}, false
+	}
+
+	for _, r := range backup {
+		if r.Type == "SOA" {
+			continue
+		}
+
+		l, ok := match(r)
+		if ok && same(l, r) {
+			continue
+		}
+
+		if _, err := parameters(r); err != nil {
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s (%s)", describe(r), err))
+			continue
+		}
+
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, r)
+		case replaced(l, r):
+			plan.Delete = append(plan.Delete, l)
+			plan.Create = append(plan.Create, r)
+		default:
+			plan.Update = append(plan.Update, Update{Live: l, Backup: r})
+		}
+	}
+
+	plan.Delete = append(plan.Delete, remaining...)
+
+	return plan
+}
+
+// keep returns backed up records with comments and tags of live records of the same name, type and value
+func keep(backup, live []zone.Record) []zone.Record {
+	records := make([]zone.Record, len(backup))
+
+	for i, r := range backup {
+		for _, l := range live {
+			if value(l) == value(r) {
+				r.Comment = l.Comment
+				r.Tags = l.Tags
+				break
+			}
+		}
+
+		records[i] = r
+	}
+
+	return records
+}
+
+// value identifies a record by its name, type and value
+func value(r zone.Record) string {
+	return fmt.Sprintf("%s %s %s", strings.ToLower(r.Name), r.Type, strings.Join(r.Value, " "))
+}
+
+// same reports whether records are identical apart from their IDs
+func same(a, b zone.Record) bool {
+	return value(a) == value(b) && a.TTL == b.TTL && proxied(a) == proxied(b) && a.Comment == b.Comment && strings.Join(a.Tags, ",") == strings.Join(b.Tags, ",")
+}
+
+// replaced reports whether a live record has to be deleted and recreated rather than updated:
+// records API does not change a type of a record or a priority through an update
+func replaced(l, r zone.Record) bool {
+	if l.Type != r.Type {
+		return true
+	}
+
+	a, err := parameters(l)
+	if err != nil {
+		return true
+	}
+
+	b, _ := parameters(r)
+
+	return (a.Priority == nil) != (b.Priority == nil) || (a.Priority != nil && *a.Priority != *b.Priority)
+}
+
+// proxied returns proxied status of a record, records that cannot be proxied are not
+func proxied(r zone.Record) bool {
+	return r.Proxied != nil && *r.Proxied
+}
+
+// change of a live record, applied by a single request
+type change struct {
+	// description of a change and of its failure
+	description, failure string
+	apply                func() error
+}
+
+// Apply changes of a plan: updates and creations go first, so that a failed restore does not leave a zone without records it had,
+// deletions go last unless they free a name a CNAME record takes or take a CNAME record off a name of other records.
+// Requests are rate limited by the client to CloudFlare API limits, a failed request reports changes applied before it.
+func (p *Provider) Apply(plan Plan) error {
+	ctx := context.Background()
+	rc := cloudflare.ZoneIdentifier(plan.ZoneID)
+
+	log.WithFields(log.Fields{
+		"provider": "CloudFlare",
+		"zone":     plan.Zone,
+		"create":   len(plan.Create),
+		"update":   len(plan.Update),
+		"delete":   len(plan.Delete),
+	}).Info("applying changes")
+
+	first, last := conflicting(plan)
+
+	var changes []change
+
+	deletion := func(r zone.Record) change {
+		return change{
+			description: fmt.Sprintf("DELETE %s", describe(r)),
+			failure:     fmt.Sprintf("CloudFlare: error deleting record '%s'", describe(r)),
+			apply: func() error {
+				return p.Client.DeleteDNSRecord(ctx, rc, r.ID)
+			},
+		}
+	}
+
+	for _, r := range first {
+		changes = append(changes, deletion(r))
+	}
+
+	for _, u := range plan.Update {
+		u := u
+		changes = append(changes, change{
+			description: fmt.Sprintf("UPDATE %s", describe(u.Backup)),
+			failure:     fmt.Sprintf("CloudFlare: error updating record '%s'", describe(u.Backup)),
+			apply: func() error {
+				params, _ := parameters(u.Backup)
+
+				_, err := p.Client.UpdateDNSRecord(ctx, rc, cloudflare.UpdateDNSRecordParams{
+					ID:      u.Live.ID,
+					Type:    u.Backup.Type,
+					Name:    strings.TrimSuffix(u.Backup.Name, "."),
+					Content: params.Content,
+					Data:    data(params.Data),
+					TTL:     int(u.Backup.TTL),
+					Proxied: u.Backup.Proxied,
+					Comment: u.Backup.Comment,
+					Tags:    u.Backup.Tags,
+				})
+
+				return err
+			},
+		})
+	}
+
+	for _, r := range plan.Create {
+		r := r
+		changes = append(changes, change{
+			description: fmt.Sprintf("CREATE %s", describe(r)),
+			failure:     fmt.Sprintf("CloudFlare: error creating record '%s'", describe(r)),
+			apply: func() error {
+				params, _ := parameters(r)
+
+				_, err := p.Client.CreateDNSRecord(ctx, rc, cloudflare.CreateDNSRecordParams{
+					Type:     r.Type,
+					Name:     strings.TrimSuffix(r.Name, "."),
+					Content:  params.Content,
+					Priority: params.Priority,
+					Data:     data(params.Data),
+					TTL:      int(r.TTL),
+					Proxied:  r.Proxied,
+					Comment:  r.Comment,
+					Tags:     r.Tags,
+				})
+
+				return err
+			},
+		})
+	}
+
+	for _, r := range last {
+		changes = append(changes, deletion(r))
+	}
+
+	var applied []string
+	for _, c := range changes {
+		err := c.apply()
+		if err != nil {
+			if len(applied) == 0 {
+				return errors.Wrap(err, fmt.Sprintf("%s, no changes applied", c.failure))
+			}
+
+			return errors.Wrap(err, fmt.Sprintf("%s, %d of %d changes applied: %s", c.failure, len(applied), len(changes), strings.Join(applied, "; ")))
+		}
+
+		applied = append(applied, c.description)
+	}
+
+	return nil
+}
+
+// conflicting splits deletions of a plan into those that have to go before updates and creations and the rest:
+// a CNAME record cannot share its name with any other record (RFC 1034, section 3.6.2)
+func conflicting(plan Plan) ([]zone.Record, []zone.Record) {
+	var names []zone.Record
+	names = append(names, plan.Create...)
+	for _, u := range plan.Update {
+		names = append(names, u.Backup)
+	}
+
+	var first, last []zone.Record
+
+	for _, d := range plan.Delete {
+		conflict := false
+		for _, r := range names {
+			if strings.EqualFold(d.Name, r.Name) && (d.Type == "CNAME" || r.Type == "CNAME") {
+				conflict = true
+				break
+			}
+		}
+
+		if conflict {
+			first = append(first, d)
+		} else {
+			last = append(last, d)
+		}
+	}
+
+	return first, last
+}
+
+// data returns structured data of a records API request, nil for records with a content
+func data(fields []field) interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		m[f.Name] = f.Value
+	}
+
+	return m
+}
+
+// Len returns a number of changes of a plan
+func (p Plan) Len() int {
+	return len(p.Create) + len(p.Update) + len(p.Delete)
+}
+
+// String returns a human readable plan
+func (p Plan) String() string {
+	b := strings.Builder{}
+
+	if p.Len() == 0 {
+		b.WriteString(fmt.Sprintf("zone %s (%s) is up-to-date with the backup\n", p.Zone, p.ZoneID))
+	} else {
+		b.WriteString(fmt.Sprintf("zone %s (%s), %d changes:\n", p.Zone, p.ZoneID, p.Len()))
+	}
+
+	for _, r := range p.Delete {
+		b.WriteString(fmt.Sprintf("  - DELETE %s\n", describe(r)))
+	}
+
+	for _, u := range p.Update {
+		b.WriteString(fmt.Sprintf("  ~ UPDATE %s\n      was %s\n", describe(u.Backup), describe(u.Live)))
+	}
+
+	for _, r := range p.Create {
+		b.WriteString(fmt.Sprintf("  + CREATE %s\n", describe(r)))
+	}
+
+	if len(p.Skipped) > 0 {
+		b.WriteString("records left untouched:\n")
+		for _, s := range p.Skipped {
+			b.WriteString(fmt.Sprintf("  %s\n", s))
+		}
+	}
+
+	if p.Zonefile {
+		b.WriteString("backup is a zonefile, which does not keep record IDs, comments and tags:\n")
+		b.WriteString("  records are matched by their name, type and value, created ones get new IDs\n")
+		b.WriteString("  comments and tags of matching live records are kept, created records have none\n")
+	}
+
+	return b.String()
+}
+
+// describe returns a single line description of a record
+func describe(r zone.Record) string {
+	ttl := fmt.Sprintf("%d", r.TTL)
+	if r.TTL == 1 {
+		ttl = "auto"
+	}
+
+	parts := []string{r.Name, r.Type, ttl, strings.Join(r.Value, " ")}
+
+	if proxied(r) {
+		parts = append(parts, "proxied")
+	}
+
+	if r.Comment != "" {
+		parts = append(parts, fmt.Sprintf("comment=%q", r.Comment))
+	}
+
+	if len(r.Tags) > 0 {
+		parts = append(parts, fmt.Sprintf("tags=%s", strings.Join(r.Tags, ",")))
+	}
+
+	return strings.Join(parts, " ")
+}
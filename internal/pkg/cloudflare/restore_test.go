@@ -0,0 +1,231 @@
+package cf_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/cloudflare/cloudflare-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiff(t *testing.T) {
+	on, off := true, false
+
+	backup := []zone.Record{
+		{Name: "domain.com.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.com. dns.domain.com. 1 10000 2400 604800 3600"}},
+		// unchanged
+		{ID: "r1", Name: "domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.4"}, Proxied: &on},
+		// proxied and comment changed
+		{ID: "r2", Name: "www.domain.com.", Type: "CNAME", TTL: 1, Value: []string{"domain.com."}, Proxied: &on, Comment: "web"},
+		// deleted and recreated with a new ID since the backup
+		{ID: "r3", Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+		// deleted
+		{ID: "r4", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &off},
+		// priority changed
+		{ID: "r5", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"20 backup.domain.com."}, Proxied: &off},
+		// unsupported by records API
+		{ID: "r6", Name: "domain.com.", Type: "LOC", TTL: 300, Value: []string{"52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m"}},
+	}
+
+	live := []zone.Record{
+		{ID: "r1", Name: "domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.4"}, Proxied: &on},
+		{ID: "r2", Name: "www.domain.com.", Type: "CNAME", TTL: 1, Value: []string{"domain.com."}, Proxied: &off},
+		{ID: "r7", Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+		{ID: "r5", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"30 backup.domain.com."}, Proxied: &off},
+		// added after the backup
+		{ID: "r8", Name: "new.domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.5"}, Proxied: &off},
+	}
+
+	plan := cf.Diff(backup, live)
+	plan.Zone, plan.ZoneID = "domain.com", "zone-1"
+
+	expected := `zone domain.com (zone-1), 5 changes:
+  - DELETE domain.com. MX 300 30 backup.domain.com.
+  - DELETE new.domain.com. A auto 1.2.3.5
+  ~ UPDATE www.domain.com. CNAME auto domain.com. proxied comment="web"
+      was www.domain.com. CNAME auto domain.com.
+  + CREATE domain.com. MX 300 10 mail.domain.com.
+  + CREATE domain.com. MX 300 20 backup.domain.com.
+records left untouched:
+  domain.com. LOC 300 52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m (unsupported type)
+`
+
+	if plan.String() != expected {
+		t.Errorf("\nEXPECTED plan: \n%s\n\nGOT plan: \n%s\n\n", expected, plan.String())
+	}
+}
+
+func TestProviderPlanApply(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	on, off := true, false
+	priority := uint16(10)
+
+	c := mocks.Cloudflare{}
+	c.On("ListDNSRecords", "zone-1").Return([]cloudflare.DNSRecord{
+		{ID: "r1", Name: "domain.com", Type: "A", TTL: 1, Content: "1.2.3.4", Proxied: &off},
+		{ID: "r2", Name: "old.domain.com", Type: "A", TTL: 1, Content: "1.2.3.5", Proxied: &off},
+	}, nil).Once()
+
+	p := cf.NewProvider(&c)
+	p.Zones.Public["domain.com"] = "zone-1"
+
+	backup := zone.Zone{
+		Name: "domain.com",
+		Records: []zone.Record{
+			{ID: "r1", Name: "domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.4"}, Proxied: &on, Tags: []string{"team:web"}},
+			{ID: "r3", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &off},
+			{ID: "r4", Name: "_sip._tcp.domain.com.", Type: "SRV", TTL: 300, Value: []string{"10 5 5060 sip.domain.com."}},
+		},
+	}
+
+	plan, err := p.Plan(backup, false)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if plan.Len() != 4 {
+		t.Errorf("\nEXPECTED changes: \n4\n\nGOT plan: \n%s\n\n", plan)
+	}
+
+	c.On("DeleteDNSRecord", "zone-1", "r2").Return(nil).Once()
+	c.On("UpdateDNSRecord", "zone-1", cloudflare.UpdateDNSRecordParams{
+		ID:      "r1",
+		Type:    "A",
+		Name:    "domain.com",
+		Content: "1.2.3.4",
+		TTL:     1,
+		Proxied: &on,
+		Tags:    []string{"team:web"},
+	}).Return(cloudflare.DNSRecord{}, nil).Once()
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{
+		Type:     "MX",
+		Name:     "domain.com",
+		Content:  "mail.domain.com",
+		Priority: &priority,
+		TTL:      300,
+		Proxied:  &off,
+	}).Return(cloudflare.DNSRecord{}, nil).Once()
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{
+		Type: "SRV",
+		Name: "_sip._tcp.domain.com",
+		Data: map[string]interface{}{"priority": uint16(10), "weight": uint16(5), "port": uint16(5060), "target": "sip.domain.com"},
+		TTL:  300,
+	}).Return(cloudflare.DNSRecord{}, nil).Once()
+
+	err = p.Apply(plan)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+}
+
+func TestProviderApplyOrder(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	plan := cf.Plan{
+		Zone:   "domain.com",
+		ZoneID: "zone-1",
+		Create: []zone.Record{
+			{Name: "www.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}},
+			{Name: "new.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.6"}},
+		},
+		Delete: []zone.Record{
+			{ID: "r1", Name: "old.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+			{ID: "r2", Name: "WWW.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}},
+		},
+	}
+
+	var order []string
+
+	c := mocks.Cloudflare{}
+	for _, id := range []string{"r1", "r2"} {
+		id := id
+		c.On("DeleteDNSRecord", "zone-1", id).Return(nil).Run(func(mock.Arguments) { order = append(order, "delete "+id) }).Once()
+	}
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{Type: "CNAME", Name: "www.domain.com", Content: "domain.com", TTL: 300}).
+		Return(cloudflare.DNSRecord{}, nil).Run(func(mock.Arguments) { order = append(order, "create www") }).Once()
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{Type: "A", Name: "new.domain.com", Content: "1.2.3.6", TTL: 300}).
+		Return(cloudflare.DNSRecord{}, nil).Run(func(mock.Arguments) { order = append(order, "create new") }).Once()
+
+	p := cf.NewProvider(&c)
+
+	// test: a deletion that frees a name for a CNAME record goes first, other deletions go last
+	err := p.Apply(plan)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := []string{"delete r2", "create www", "create new", "delete r1"}
+	if !reflect.DeepEqual(expected, order) {
+		t.Errorf("\nEXPECTED order: \n%v\n\nGOT order: \n%v\n\n", expected, order)
+	}
+
+	c.AssertExpectations(t)
+
+	// test: a failed change reports changes applied before it and leaves remaining deletions undone
+	c = mocks.Cloudflare{}
+	c.On("DeleteDNSRecord", "zone-1", "r2").Return(nil).Once()
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{Type: "CNAME", Name: "www.domain.com", Content: "domain.com", TTL: 300}).
+		Return(cloudflare.DNSRecord{}, nil).Once()
+	c.On("CreateDNSRecord", "zone-1", cloudflare.CreateDNSRecordParams{Type: "A", Name: "new.domain.com", Content: "1.2.3.6", TTL: 300}).
+		Return(cloudflare.DNSRecord{}, errors.New("rate limited")).Once()
+
+	p.Client = &c
+
+	err = p.Apply(plan)
+	if err == nil || !strings.Contains(err.Error(), "2 of 4 changes applied: DELETE WWW.domain.com. A 300 1.2.3.5; CREATE www.domain.com. CNAME 300 domain.com.") {
+		t.Errorf("\nEXPECTED error: \nCloudFlare: error creating record 'new.domain.com. A 300 1.2.3.6', 2 of 4 changes applied: ...\n\nGOT error: \n%v\n\n", err)
+	}
+
+	c.AssertNotCalled(t, "DeleteDNSRecord", "zone-1", "r1")
+}
+
+func TestProviderPlanZonefile(t *testing.T) {
+	on, off := true, false
+
+	c := mocks.Cloudflare{}
+	c.On("ListDNSRecords", "zone-1").Return([]cloudflare.DNSRecord{
+		{ID: "r1", Name: "domain.com", Type: "A", TTL: 1, Content: "1.2.3.4", Proxied: &on, Comment: "origin", Tags: []string{"env:prod"}},
+		{ID: "r2", Name: "www.domain.com", Type: "CNAME", TTL: 1, Content: "domain.com", Proxied: &on, Comment: "web"},
+	}, nil).Once()
+
+	p := cf.NewProvider(&c)
+	p.Zones.Public["domain.com"] = "zone-1"
+
+	// zonefile backup has neither record IDs, nor comments and tags
+	backup := zone.Zone{
+		Name: "domain.com.",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.4"}, Proxied: &on},
+			{Name: "www.domain.com.", Type: "CNAME", TTL: 1, Value: []string{"domain.com."}, Proxied: &off},
+		},
+	}
+
+	plan, err := p.Plan(backup, true)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := `zone domain.com (zone-1), 1 changes:
+  ~ UPDATE www.domain.com. CNAME auto domain.com. comment="web"
+      was www.domain.com. CNAME auto domain.com. proxied comment="web"
+backup is a zonefile, which does not keep record IDs, comments and tags:
+  records are matched by their name, type and value, created ones get new IDs
+  comments and tags of matching live records are kept, created records have none
+`
+
+	if plan.String() != expected {
+		t.Errorf("\nEXPECTED plan: \n%s\n\nGOT plan: \n%s\n\n", expected, plan.String())
+	}
+
+	c.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dns-exporter/internal/pkg/utils"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// exportSettings writes zone settings, DNSSEC, page rules and redirect rules into '<dir>/<domain>'
+func exportSettings(c Client, id, domain, dir string, fs afero.Fs) error {
+	settings, err := getSettings(c, id)
+	if err != nil {
+		return err
+	}
+
+	d := fmt.Sprintf("%v/%v", dir, domain)
+	_, err = utils.ValidateDir(d, true, fs)
+	if err != nil {
+		return errors.Wrap(err, "error validating settings directory")
+	}
+
+	for name, content := range settings {
+		b, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error composing %s", name))
+		}
+
+		_, err = utils.WriteToFileAs(name, "json", string(b)+"\n", d, fs)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error writing %s", name))
+		}
+	}
+
+	return nil
+}
+
+// getSettings returns zone configuration keyed by an export filename,
+// configuration a token is not permitted to read is left out with a warning as its permissions are not required for records export
+func getSettings(c Client, id string) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+
+	s, err := c.ZoneSettings(context.Background(), id)
+	if err != nil && !denied(err, id, "settings", "Zone Settings:Read") {
+		return nil, errors.Wrap(err, "error retrieving zone settings")
+	}
+
+	if err == nil {
+		// setting values only, metadata like 'time_remaining' changes on every run
+		values := make(map[string]interface{})
+		for _, setting := range s.Result {
+			values[setting.ID] = setting.Value
+		}
+
+		settings["settings"] = values
+	}
+
+	d, err := c.ZoneDNSSECSetting(context.Background(), id)
+	if err != nil && !denied(err, id, "dnssec", "Zone Settings:Read") {
+		return nil, errors.Wrap(err, "error retrieving DNSSEC status")
+	}
+
+	if err == nil {
+		settings["dnssec"] = DNSSEC{
+			Status:     d.Status,
+			Flags:      d.Flags,
+			Algorithm:  d.Algorithm,
+			KeyType:    d.KeyType,
+			KeyTag:     d.KeyTag,
+			PublicKey:  d.PublicKey,
+			DigestType: d.DigestType,
+			Digest:     d.Digest,
+			DS:         d.DS,
+		}
+	}
+
+	pages, err := c.ListPageRules(context.Background(), id)
+	if err != nil && !denied(err, id, "page-rules", "Page Rules:Read") {
+		return nil, errors.Wrap(err, "error retrieving page rules")
+	}
+
+	if err == nil {
+		if pages == nil {
+			pages = []cloudflare.PageRule{}
+		}
+
+		settings["page-rules"] = pages
+	}
+
+	redirects, err := getRedirectRules(c, id)
+	if err != nil && !denied(err, id, "redirect-rules", "Single Redirect:Read") {
+		return nil, err
+	}
+
+	if err == nil {
+		settings["redirect-rules"] = redirects
+	}
+
+	return settings, nil
+}
+
+// denied reports whether a request failed on a missing token permission, which is logged as a warning
+func denied(err error, id, name, permission string) bool {
+	var forbidden *cloudflare.AuthorizationError
+	if !errors.As(err, &forbidden) {
+		return false
+	}
+
+	log.WithFields(log.Fields{
+		"provider":   "CloudFlare",
+		"zone":       id,
+		"permission": permission,
+	}).Warn(fmt.Sprintf("skipping %s export, API token is not permitted to read it", name))
+
+	return true
+}
+
+// getRedirectRules returns rules of a zone dynamic redirect ruleset, a zone without redirect rules has no such ruleset at all
+func getRedirectRules(c Client, id string) ([]cloudflare.RulesetRule, error) {
+	r, err := c.GetZoneRulesetPhase(context.Background(), id, string(cloudflare.RulesetPhaseHTTPRequestDynamicRedirect))
+	if err != nil {
+		var notFound *cloudflare.NotFoundError
+		if errors.As(err, &notFound) {
+			return []cloudflare.RulesetRule{}, nil
+		}
+
+		return nil, errors.Wrap(err, "error retrieving redirect rules")
+	}
+
+	if r.Rules == nil {
+		return []cloudflare.RulesetRule{}, nil
+	}
+
+	return r.Rules, nil
+}
@@ -0,0 +1,201 @@
+package cf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/mocks"
+
+	"github.com/cloudflare/cloudflare-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestExportSettings(t *testing.T) {
+	c := mocks.Cloudflare{}
+	fs := afero.NewMemMapFs()
+
+	c.On("ZoneSettings", "1").Return(&cloudflare.ZoneSettingResponse{
+		Result: []cloudflare.ZoneSetting{
+			{
+				ID:            "ssl",
+				Value:         "strict",
+				Editable:      true,
+				TimeRemaining: 0,
+			},
+			{
+				ID:    "always_use_https",
+				Value: "on",
+			},
+			{
+				ID:    "min_tls_version",
+				Value: "1.2",
+			},
+		},
+	}, nil).Once()
+
+	c.On("ZoneDNSSECSetting", "1").Return(cloudflare.ZoneDNSSEC{
+		Status:     "active",
+		Flags:      257,
+		Algorithm:  "13",
+		KeyType:    "ECDSAP256SHA256",
+		KeyTag:     2371,
+		DigestType: "2",
+		Digest:     "48E939042E82C22542CB377B580DFDC52A361CEFDC72E7F9107E2B6BD9306A45",
+		DS:         "domain.com. 3600 IN DS 2371 13 2 48E939042E82C22542CB377B580DFDC52A361CEFDC72E7F9107E2B6BD9306A45",
+	}, nil).Once()
+
+	c.On("ListPageRules", "1").Return([]cloudflare.PageRule{
+		{
+			ID: "p1",
+			Targets: []cloudflare.PageRuleTarget{
+				{
+					Target: "url",
+					Constraint: struct {
+						Operator string `json:"operator"`
+						Value    string `json:"value"`
+					}{
+						Operator: "matches",
+						Value:    "domain.com/old/*",
+					},
+				},
+			},
+			Actions: []cloudflare.PageRuleAction{
+				{
+					ID: "forwarding_url",
+					Value: map[string]interface{}{
+						"url":         "https://domain.com/new/$1",
+						"status_code": 301,
+					},
+				},
+			},
+			Priority: 1,
+			Status:   "active",
+		},
+	}, nil).Once()
+
+	c.On("GetZoneRulesetPhase", "1", "http_request_dynamic_redirect").Return(cloudflare.Ruleset{}, &cloudflare.NotFoundError{}).Once()
+
+	err := cf.ExportSettings(&c, "1", "domain.com", "./CloudFlare", fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+
+	// test: setting values
+	content, err := afero.ReadFile(fs, "./CloudFlare/domain.com/settings.json")
+	if err != nil {
+		t.Fatal("error reading exported settings:", err)
+	}
+
+	var settings map[string]interface{}
+	err = json.Unmarshal(content, &settings)
+	if err != nil {
+		t.Fatal("error parsing exported settings:", err)
+	}
+
+	expected := map[string]interface{}{
+		"ssl":              "strict",
+		"always_use_https": "on",
+		"min_tls_version":  "1.2",
+	}
+
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("\nEXPECTED settings: \n%+v\n\nGOT settings: \n%+v\n\n", expected, settings)
+	}
+
+	// test: DNSSEC status and DS record
+	content, err = afero.ReadFile(fs, "./CloudFlare/domain.com/dnssec.json")
+	if err != nil {
+		t.Fatal("error reading exported DNSSEC:", err)
+	}
+
+	var dnssec cf.DNSSEC
+	err = json.Unmarshal(content, &dnssec)
+	if err != nil {
+		t.Fatal("error parsing exported DNSSEC:", err)
+	}
+
+	if dnssec.Status != "active" || dnssec.KeyTag != 2371 || dnssec.DS == "" {
+		t.Errorf("\nEXPECTED DNSSEC: \nactive with a DS record\n\nGOT DNSSEC: \n%+v\n\n", dnssec)
+	}
+
+	// test: page rules
+	content, err = afero.ReadFile(fs, "./CloudFlare/domain.com/page-rules.json")
+	if err != nil {
+		t.Fatal("error reading exported page rules:", err)
+	}
+
+	var pages []cloudflare.PageRule
+	err = json.Unmarshal(content, &pages)
+	if err != nil {
+		t.Fatal("error parsing exported page rules:", err)
+	}
+
+	if len(pages) != 1 || pages[0].Actions[0].ID != "forwarding_url" {
+		t.Errorf("\nEXPECTED page rules: \na single forwarding rule\n\nGOT page rules: \n%+v\n\n", pages)
+	}
+
+	// test: missing redirect ruleset
+	content, err = afero.ReadFile(fs, "./CloudFlare/domain.com/redirect-rules.json")
+	if err != nil {
+		t.Fatal("error reading exported redirect rules:", err)
+	}
+
+	if string(content) != "[]\n" {
+		t.Errorf("\nEXPECTED redirect rules: \n[]\n\nGOT redirect rules: \n%s\n\n", content)
+	}
+}
+
+func TestExportSettingsError(t *testing.T) {
+	c := mocks.Cloudflare{}
+	fs := afero.NewMemMapFs()
+
+	c.On("ZoneSettings", "1").Return(&cloudflare.ZoneSettingResponse{}, errors.New("reason")).Once()
+
+	err := cf.ExportSettings(&c, "1", "domain.com", "./CloudFlare", fs)
+	if err == nil || err.Error() != "error retrieving zone settings: reason" {
+		t.Errorf("\nEXPECTED error: \nerror retrieving zone settings: reason\n\nGOT error: \n%v\n\n", err)
+	}
+}
+
+func TestExportSettingsDenied(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Cloudflare{}
+	fs := afero.NewMemMapFs()
+
+	forbidden := func() error {
+		e := cloudflare.NewAuthorizationError(&cloudflare.Error{StatusCode: 403, ErrorMessages: []string{"Unauthorized to access requested resource"}})
+		return &e
+	}
+
+	c.On("ZoneSettings", "1").Return(&cloudflare.ZoneSettingResponse{}, forbidden()).Once()
+	c.On("ZoneDNSSECSetting", "1").Return(cloudflare.ZoneDNSSEC{Status: "disabled"}, nil).Once()
+	c.On("ListPageRules", "1").Return([]cloudflare.PageRule{}, forbidden()).Once()
+	c.On("GetZoneRulesetPhase", "1", "http_request_dynamic_redirect").Return(cloudflare.Ruleset{}, forbidden()).Once()
+
+	err := cf.ExportSettings(&c, "1", "domain.com", "./CloudFlare", fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+
+	// configuration the token is permitted to read is exported only
+	for path, expected := range map[string]bool{
+		"./CloudFlare/domain.com/settings.json":       false,
+		"./CloudFlare/domain.com/dnssec.json":         true,
+		"./CloudFlare/domain.com/page-rules.json":     false,
+		"./CloudFlare/domain.com/redirect-rules.json": false,
+	} {
+		ok, err := afero.Exists(fs, path)
+		if err != nil || ok != expected {
+			t.Errorf("\nEXPECTED path '%s' exists: \n%t\n\nGOT exists: \n%t\n\n", path, expected, ok)
+		}
+	}
+}
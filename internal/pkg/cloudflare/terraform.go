@@ -0,0 +1,104 @@
+package cf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
+)
+
+// terraform returns Terraform config of zone records, with import blocks of the existing records.
+// Records that cannot be represented (unsupported types or values) are listed as comments.
+func terraform(id string, records []zone.Record) string {
+	names := zone.Resources{}
+
+	b := strings.Builder{}
+
+	var skipped []string
+
+	for _, r := range records {
+		// SOA record is managed by CloudFlare
+		if r.Type == "SOA" || r.ID == "" || len(r.Value) == 0 {
+			continue
+		}
+
+		attributes, blocks, err := resource(id, r)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("# %s %s (%s)", r.Name, r.Type, err))
+			continue
+		}
+
+		n := names.Name(strings.TrimSuffix(r.Name, "."), r.Type)
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(fmt.Sprintf("resource \"cloudflare_record\" %q {\n", n))
+		b.WriteString(zone.Attributes(attributes, "  "))
+		for _, block := range blocks {
+			b.WriteString("\n" + block)
+		}
+		b.WriteString("}\n\n")
+		b.WriteString(zone.Import("cloudflare_record."+n, fmt.Sprintf("%s/%s", id, r.ID)))
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("\n# records not represented in this config:\n" + strings.Join(skipped, "\n") + "\n")
+	}
+
+	return b.String()
+}
+
+// resource returns attributes and nested blocks of a 'cloudflare_record' resource
+func resource(id string, r zone.Record) ([][2]string, []string, error) {
+	attributes := [][2]string{
+		{"zone_id", zone.HCL(id)},
+		{"name", zone.HCL(strings.TrimSuffix(r.Name, "."))},
+		{"type", zone.HCL(r.Type)},
+	}
+
+	p, err := parameters(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.Data == nil {
+		attributes = append(attributes, [2]string{"value", zone.HCL(p.Content)})
+	}
+
+	if p.Priority != nil {
+		attributes = append(attributes, [2]string{"priority", strconv.Itoa(int(*p.Priority))})
+	}
+
+	attributes = append(attributes, [2]string{"ttl", strconv.FormatInt(r.TTL, 10)})
+
+	if r.Proxied != nil {
+		attributes = append(attributes, [2]string{"proxied", strconv.FormatBool(*r.Proxied)})
+	}
+
+	if r.Comment != "" {
+		attributes = append(attributes, [2]string{"comment", zone.HCL(r.Comment)})
+	}
+
+	if len(r.Tags) > 0 {
+		attributes = append(attributes, [2]string{"tags", zone.HCLList(r.Tags)})
+	}
+
+	var blocks []string
+	if p.Data != nil {
+		var data [][2]string
+		for _, f := range p.Data {
+			if s, ok := f.Value.(string); ok {
+				data = append(data, [2]string{f.Name, zone.HCL(s)})
+			} else {
+				data = append(data, [2]string{f.Name, fmt.Sprintf("%v", f.Value)})
+			}
+		}
+
+		blocks = append(blocks, zone.Block("data", data))
+	}
+
+	return attributes, blocks, nil
+}
@@ -0,0 +1,108 @@
+package cf_test
+
+import (
+	"testing"
+
+	cf "dns-exporter/internal/pkg/cloudflare"
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestTerraform(t *testing.T) {
+	on, off := true, false
+
+	records := []zone.Record{
+		{Name: "domain.com.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.com. dns.domain.com. 1 10000 2400 604800 3600"}},
+		{ID: "r1", Name: "domain.com.", Type: "A", TTL: 1, Value: []string{"1.2.3.4"}, Proxied: &on, Comment: "web ${env}", Tags: []string{"team:web"}},
+		{ID: "r2", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &off},
+		{ID: "r3", Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 include:_spf.domain.com -all"`}},
+		{ID: "r4", Name: "_sip._tcp.domain.com.", Type: "SRV", TTL: 300, Value: []string{"10 5 5060 sip.domain.com."}},
+		{ID: "r5", Name: "*.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}, Proxied: &off},
+		{ID: "r6", Name: "domain.com.", Type: "LOC", TTL: 300, Value: []string{"52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m"}},
+	}
+
+	expected := `resource "cloudflare_record" "domain_com_a" {
+  zone_id = "zone-1"
+  name    = "domain.com"
+  type    = "A"
+  value   = "1.2.3.4"
+  ttl     = 1
+  proxied = true
+  comment = "web $${env}"
+  tags    = ["team:web"]
+}
+
+import {
+  to = cloudflare_record.domain_com_a
+  id = "zone-1/r1"
+}
+
+resource "cloudflare_record" "domain_com_mx" {
+  zone_id  = "zone-1"
+  name     = "domain.com"
+  type     = "MX"
+  value    = "mail.domain.com"
+  priority = 10
+  ttl      = 300
+  proxied  = false
+}
+
+import {
+  to = cloudflare_record.domain_com_mx
+  id = "zone-1/r2"
+}
+
+resource "cloudflare_record" "domain_com_txt" {
+  zone_id = "zone-1"
+  name    = "domain.com"
+  type    = "TXT"
+  value   = "v=spf1 include:_spf.domain.com -all"
+  ttl     = 300
+}
+
+import {
+  to = cloudflare_record.domain_com_txt
+  id = "zone-1/r3"
+}
+
+resource "cloudflare_record" "sip__tcp_domain_com_srv" {
+  zone_id = "zone-1"
+  name    = "_sip._tcp.domain.com"
+  type    = "SRV"
+  ttl     = 300
+
+  data {
+    priority = 10
+    weight   = 5
+    port     = 5060
+    target   = "sip.domain.com"
+  }
+}
+
+import {
+  to = cloudflare_record.sip__tcp_domain_com_srv
+  id = "zone-1/r4"
+}
+
+resource "cloudflare_record" "wildcard_domain_com_cname" {
+  zone_id = "zone-1"
+  name    = "*.domain.com"
+  type    = "CNAME"
+  value   = "domain.com"
+  ttl     = 300
+  proxied = false
+}
+
+import {
+  to = cloudflare_record.wildcard_domain_com_cname
+  id = "zone-1/r5"
+}
+
+# records not represented in this config:
+# domain.com. LOC (unsupported type)
+`
+
+	config := cf.Terraform("zone-1", records)
+	if config != expected {
+		t.Errorf("\nEXPECTED config: \n%s\n\nGOT config: \n%s\n\n", expected, config)
+	}
+}
@@ -0,0 +1,159 @@
+package do
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch hosted zones
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for page := 1; ; page++ {
+		o, err := c.ListDomains(page)
+		if err != nil {
+			errs <- errors.Wrap(err, "DigitalOcean: error fetching zones")
+			return
+		}
+
+		for _, domain := range o.Domains {
+			z.Public[domain.Name] = domain.Name
+		}
+
+		if o.Links.Pages.Next == "" {
+			break
+		}
+	}
+
+	errs <- nil
+}
+
+// Export hosted zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	// validate provider export dir
+	dir := fmt.Sprintf("%v/DigitalOcean", root)
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "DigitalOcean: error exporting zones")
+		return
+	}
+
+	// export zonefiles
+	for domain, id := range z.Public {
+		log.WithFields(log.Fields{
+			"provider": "DigitalOcean",
+			"zone":     domain,
+		}).Info("exporting zone")
+
+		r, err := getRecords(id, c)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("DigitalOcean: error retrieving zone '%s' records", domain))
+			return
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "DigitalOcean",
+			Records:  r,
+		}
+
+		// write zone exports
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("DigitalOcean: error exporting zone: '%s'", domain))
+			return
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	errs <- nil
+}
+
+// getRecords returns all records of a domain
+func getRecords(domain string, c Client) ([]zone.Record, error) {
+	var r []zone.Record
+
+	for page := 1; ; page++ {
+		o, err := c.ListRecords(domain, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving zone records")
+		}
+
+		for _, record := range o.Records {
+			r = append(r, convert(domain, record))
+		}
+
+		if o.Links.Pages.Next == "" {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+// convert a DigitalOcean domain record into a zone record
+func convert(domain string, record Record) zone.Record {
+	r := zone.Record{
+		Name: fqdn(record.Name, domain),
+		Type: record.Type,
+		TTL:  record.TTL,
+	}
+
+	value := record.Data
+	switch record.Type {
+	case "CNAME", "NS", "PTR":
+		value = target(record.Data, domain)
+	case "MX":
+		value = fmt.Sprintf("%v %s", number(record.Priority), target(record.Data, domain))
+	case "SRV":
+		value = fmt.Sprintf("%v %v %v %s", number(record.Priority), number(record.Weight), number(record.Port), target(record.Data, domain))
+	case "CAA":
+		value = fmt.Sprintf("%v %s %s", number(record.Flags), record.Tag, zone.Quote(record.Data))
+	case "TXT":
+		value = zone.QuoteTXT(record.Data)
+	}
+
+	r.Value = []string{value}
+
+	return r
+}
+
+// fqdn returns a fully qualified name of a record relative to a domain
+func fqdn(name, domain string) string {
+	if name == "@" || name == "" {
+		return domain + "."
+	}
+
+	return fmt.Sprintf("%s.%s.", name, domain)
+}
+
+// target returns a fully qualified hostname of record data, where "@" is the domain itself
+func target(data, domain string) string {
+	if data == "@" {
+		return domain + "."
+	}
+
+	return dns.Fqdn(data)
+}
+
+// number returns a value of an optional integer field
+func number(i *int) int {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
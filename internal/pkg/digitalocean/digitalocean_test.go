@@ -0,0 +1,218 @@
+package do_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	do "dns-exporter/internal/pkg/digitalocean"
+	"dns-exporter/internal/pkg/zone"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// fake serves a DigitalOcean REST API of a single account
+func fake(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/domains?page=1&per_page=200": `{
+			"domains": [{"name": "domain.com", "ttl": 1800}],
+			"links": {"pages": {"next": "https://api.digitalocean.com/v2/domains?page=2&per_page=200"}}
+		}`,
+		"/domains?page=2&per_page=200": `{
+			"domains": [{"name": "domain.net", "ttl": 1800}],
+			"links": {}
+		}`,
+		"/domains/domain.com/records?page=1&per_page=200": `{
+			"domain_records": [
+				{"id": 1, "type": "A", "name": "@", "data": "192.168.1.51", "ttl": 300},
+				{"id": 2, "type": "MX", "name": "@", "data": "mail.domain.com.", "priority": 10, "ttl": 300}
+			],
+			"links": {"pages": {"next": "https://api.digitalocean.com/v2/domains/domain.com/records?page=2&per_page=200"}}
+		}`,
+		"/domains/domain.com/records?page=2&per_page=200": `{
+			"domain_records": [
+				{"id": 3, "type": "SRV", "name": "_sip._tcp", "data": "sip.domain.com.", "priority": 1, "weight": 10, "port": 5060, "ttl": 300},
+				{"id": 4, "type": "CAA", "name": "@", "data": "letsencrypt.org", "flags": 0, "tag": "issue", "ttl": 3600},
+				{"id": 5, "type": "TXT", "name": "www", "data": "v=spf1 -all", "ttl": 300}
+			],
+			"links": {}
+		}`,
+		"/domains/domain.net/records?page=1&per_page=200": `{"domain_records": [], "links": {}}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			t.Logf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	z := do.Zones{
+		Public: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(&do.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := do.Zones{
+		Public: map[string]string{
+			"domain.com": "domain.com",
+			"domain.net": "domain.net",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	server := fake(t)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+
+	z := do.Zones{
+		Public: map[string]string{
+			"domain.com": "domain.com",
+		},
+	}
+
+	expected := `;; MX Records
+domain.com.	300	IN	MX	10 mail.domain.com.
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+
+;; TXT Records
+www.domain.com.	300	IN	TXT	"v=spf1 -all"
+
+;; SRV Records
+_sip._tcp.domain.com.	300	IN	SRV	1 10 5060 sip.domain.com.
+
+;; CAA Records
+domain.com.	3600	IN	CAA	0 issue "letsencrypt.org"
+
+`
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&do.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	content, err := afero.ReadFile(fs, "./DigitalOcean/domain-com.txt")
+	if err != nil {
+		t.Fatal("error reading exported zonefile:", err)
+	}
+
+	if !reflect.DeepEqual(expected, string(content)) {
+		t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", expected, string(content))
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	r, err := do.GetRecords("domain.com", &do.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := zone.Record{
+		Name:  "_sip._tcp.domain.com.",
+		Type:  "SRV",
+		TTL:   300,
+		Value: []string{"1 10 5060 sip.domain.com."},
+	}
+
+	if len(r) != 5 || !reflect.DeepEqual(expected, r[2]) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// domain without records
+	r, err = do.GetRecords("domain.net", &do.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil || len(r) != 0 {
+		t.Errorf("\nEXPECTED records: \n[]\n\nGOT records: \n%+v (%v)\n\n", r, err)
+	}
+
+	// invalid token
+	_, err = do.GetRecords("domain.com", &do.API{HTTP: server.Client(), URL: server.URL, Token: "invalid"})
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nunexpected status code 401\n\nGOT error: \n<nil>")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   do.Record
+		expected zone.Record
+	}{
+		{
+			name:     "apex target",
+			record:   do.Record{Type: "CNAME", Name: "www", Data: "@", TTL: 300},
+			expected: zone.Record{Name: "www.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}},
+		},
+		{
+			name:     "unqualified target",
+			record:   do.Record{Type: "NS", Name: "sub", Data: "ns1.digitalocean.com", TTL: 1800},
+			expected: zone.Record{Name: "sub.domain.com.", Type: "NS", TTL: 1800, Value: []string{"ns1.digitalocean.com."}},
+		},
+		{
+			name:     "missing priority",
+			record:   do.Record{Type: "MX", Name: "@", Data: "@", TTL: 300},
+			expected: zone.Record{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"0 domain.com."}},
+		},
+		{
+			name:     "long TXT",
+			record:   do.Record{Type: "TXT", Name: "@", Data: strings.Repeat("a", 300), TTL: 300},
+			expected: zone.Record{Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`}},
+		},
+	}
+
+	for _, test := range tests {
+		r := do.Convert("domain.com", test.record)
+		if !reflect.DeepEqual(test.expected, r) {
+			t.Errorf("%s\nEXPECTED record: \n%+v\n\nGOT record: \n%+v\n\n", test.name, test.expected, r)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package do
+
+var (
+	GetRecords = getRecords
+	Convert    = convert
+)
@@ -0,0 +1,67 @@
+package do
+
+import (
+	"net/http"
+)
+
+// Zones hosted by a DNS provider
+type Zones struct {
+	Public map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListDomains(page int) (*DomainsResponse, error)
+	ListRecords(domain string, page int) (*RecordsResponse, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is a DigitalOcean REST API client
+type API struct {
+	HTTP  HTTPClient
+	URL   string
+	Token string
+}
+
+// Links of a paginated DigitalOcean response
+type Links struct {
+	Pages struct {
+		Next string `json:"next"`
+	} `json:"pages"`
+}
+
+// DomainsResponse is a single page of '/v2/domains'
+type DomainsResponse struct {
+	Domains []Domain `json:"domains"`
+	Links   Links    `json:"links"`
+}
+
+// Domain is a DigitalOcean hosted zone
+type Domain struct {
+	Name string `json:"name"`
+	TTL  int64  `json:"ttl"`
+}
+
+// RecordsResponse is a single page of '/v2/domains/{domain}/records'
+type RecordsResponse struct {
+	Records []Record `json:"domain_records"`
+	Links   Links    `json:"links"`
+}
+
+// Record is a DigitalOcean domain record
+type Record struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Data     string `json:"data"`
+	Priority *int   `json:"priority"`
+	Port     *int   `json:"port"`
+	Weight   *int   `json:"weight"`
+	Flags    *int   `json:"flags"`
+	Tag      string `json:"tag"`
+	TTL      int64  `json:"ttl"`
+}
@@ -1,6 +1,7 @@
 package vcs
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -143,6 +144,39 @@ func (p Project) Commit(timestamp time.Time, meta, data billy.Filesystem) error
 	return nil
 }
 
+// Read returns content of a file at a revision (a commit hash, branch, tag or an expression such as 'HEAD~2')
+func (p Project) Read(revision, path string, meta, data billy.Filesystem) ([]byte, error) {
+	repo, err := git.Open(
+		filesystem.NewStorage(meta, cache.NewObjectLRU(cache.DefaultMaxSize)),
+		data,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening repository")
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error resolving revision '%s'", revision))
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error retreiving commit '%s'", hash))
+	}
+
+	file, err := commit.File(path)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error retreiving '%s' at revision '%s'", path, revision))
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading '%s' at revision '%s'", path, revision))
+	}
+
+	return []byte(content), nil
+}
+
 // Push to origin
 func (p Project) Push(meta billy.Filesystem) error {
 	r := GitNewRemote(
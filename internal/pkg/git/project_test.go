@@ -2,11 +2,13 @@ package vcs_test
 
 import (
 	"testing"
+	"time"
 
 	vcs "dns-exporter/internal/pkg/git"
 
 	"gopkg.in/src-d/go-billy.v4"
 	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/storage"
 )
@@ -35,3 +37,50 @@ func TestClone(t *testing.T) {
 		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
 	}
 }
+
+func TestRead(t *testing.T) {
+	meta, data := memfs.New(), memfs.New()
+	m := vcs.Project{
+		AuthorName:  "DNS-EXPORTER",
+		AuthorEmail: "no-email@dns-exporter.com",
+	}
+
+	err := vcs.Init(meta, data)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// two revisions of the same export
+	for i, content := range []string{"first", "second"} {
+		err = util.WriteFile(data, "Route53/Public/domain-com.json", []byte(content), 0644)
+		if err != nil {
+			t.Fatal("error writing export:", err)
+		}
+
+		err = m.Commit(time.Unix(int64(i), 0), meta, data)
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
+	}
+
+	suite := map[string]string{
+		"HEAD":   "second",
+		"HEAD~1": "first",
+	}
+
+	for revision, expected := range suite {
+		content, err := m.Read(revision, "Route53/Public/domain-com.json", meta, data)
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
+
+		if string(content) != expected {
+			t.Errorf("\nEXPECTED content: \n%s\n\nGOT content: \n%s\n\n", expected, string(content))
+		}
+	}
+
+	_, err = m.Read("HEAD", "Route53/Public/missing-com.json", meta, data)
+	if err == nil {
+		t.Error("\nEXPECTED error: \nfile not found\n\nGOT error: \n<nil>")
+	}
+}
@@ -0,0 +1,88 @@
+package hz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL of a Hetzner DNS REST API
+const DefaultURL string = "https://dns.hetzner.com/api/v1"
+
+// pageSize is the maximum amount of items per page
+const pageSize int = 100
+
+// ListZones returns a single page of account zones
+func (a *API) ListZones(page int) (*ZonesResponse, error) {
+	var r ZonesResponse
+
+	err := a.get(fmt.Sprintf("/zones?page=%v&per_page=%v", page, pageSize), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// GetZone returns a single zone
+func (a *API) GetZone(id string) (*Zone, error) {
+	var r struct {
+		Zone Zone `json:"zone"`
+	}
+
+	err := a.get(fmt.Sprintf("/zones/%s", url.PathEscape(id)), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r.Zone, nil
+}
+
+// ListRecords returns a single page of zone records
+func (a *API) ListRecords(zone string, page int) (*RecordsResponse, error) {
+	var r RecordsResponse
+
+	err := a.get(fmt.Sprintf("/records?zone_id=%s&page=%v&per_page=%v", url.QueryEscape(zone), page, pageSize), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// get decodes a JSON response of an API path into v
+func (a *API) get(path string, v interface{}) error {
+	request, err := http.NewRequest("GET", a.URL+path, nil)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error constructing HTTP request '%s'", path))
+	}
+
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Auth-API-Token", a.Token)
+
+	response, err := a.HTTP.Do(request)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error consuming '%s'", path))
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading responce body")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v consuming '%s': %s", response.StatusCode, path, string(b))
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+package hz
+
+var GetRecords = getRecords
@@ -0,0 +1,153 @@
+package hz
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch hosted zones
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for page := 1; ; page++ {
+		o, err := c.ListZones(page)
+		if err != nil {
+			errs <- errors.Wrap(err, "Hetzner: error fetching zones")
+			return
+		}
+
+		for _, zone := range o.Zones {
+			z.Public[zone.Name] = zone.ID
+		}
+
+		if page >= o.Meta.Pagination.LastPage {
+			break
+		}
+	}
+
+	errs <- nil
+}
+
+// Export hosted zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	// validate provider export dir
+	dir := fmt.Sprintf("%v/Hetzner", root)
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "Hetzner: error exporting zones")
+		return
+	}
+
+	// export zonefiles
+	for domain, id := range z.Public {
+		log.WithFields(log.Fields{
+			"provider": "Hetzner",
+			"zone":     domain,
+		}).Info("exporting zone")
+
+		r, err := getRecords(id, c)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("Hetzner: error retrieving zone '%s' records", domain))
+			return
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "Hetzner",
+			Records:  r,
+		}
+
+		// write zone exports
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("Hetzner: error exporting zone: '%s'", domain))
+			return
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	errs <- nil
+}
+
+// getRecords returns all records of a zone
+func getRecords(id string, c Client) ([]zone.Record, error) {
+	z, err := c.GetZone(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving zone")
+	}
+
+	var r []zone.Record
+
+	for page := 1; ; page++ {
+		o, err := c.ListRecords(id, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving zone records")
+		}
+
+		for _, record := range o.Records {
+			rec := zone.Record{
+				Name:  fqdn(record.Name, z.Name),
+				Type:  record.Type,
+				TTL:   z.TTL,
+				Value: []string{value(record.Type, record.Value, z.Name)},
+			}
+
+			if record.TTL != nil {
+				rec.TTL = *record.TTL
+			}
+
+			r = append(r, rec)
+		}
+
+		if page >= o.Meta.Pagination.LastPage {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+// fqdn returns a fully qualified name of a record relative to a zone
+func fqdn(name, zone string) string {
+	if name == "@" || name == "" {
+		return zone + "."
+	}
+
+	return fmt.Sprintf("%s.%s.", name, zone)
+}
+
+// value returns record data with its target qualified, the API returns targets as written in the console, where "@" is the zone
+// and names without a trailing dot are relative to it
+func value(kind, data, zone string) string {
+	switch kind {
+	case "CNAME", "NS", "PTR", "MX", "SRV":
+	default:
+		return data
+	}
+
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return data
+	}
+
+	target := fields[len(fields)-1]
+	if !strings.HasSuffix(target, ".") {
+		target = fqdn(target, zone)
+	}
+	fields[len(fields)-1] = target
+
+	return strings.Join(fields, " ")
+}
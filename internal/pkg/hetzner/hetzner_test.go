@@ -0,0 +1,202 @@
+package hz_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	hz "dns-exporter/internal/pkg/hetzner"
+	"dns-exporter/internal/pkg/zone"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// fake serves a Hetzner DNS REST API of a single account
+func fake(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/zones?page=1&per_page=100": `{
+			"zones": [{"id": "z1", "name": "domain.com", "ttl": 86400}],
+			"meta": {"pagination": {"page": 1, "per_page": 100, "last_page": 2, "total_entries": 2}}
+		}`,
+		"/zones?page=2&per_page=100": `{
+			"zones": [{"id": "z2", "name": "domain.de", "ttl": 86400}],
+			"meta": {"pagination": {"page": 2, "per_page": 100, "last_page": 2, "total_entries": 2}}
+		}`,
+		"/zones/z1": `{"zone": {"id": "z1", "name": "domain.com", "ttl": 86400}}`,
+		"/records?zone_id=z1&page=1&per_page=100": `{
+			"records": [
+				{"id": "r1", "type": "SOA", "name": "@", "value": "hydrogen.ns.hetzner.com. dns.hetzner.com. 2023010101 86400 10800 3600000 3600", "zone_id": "z1"},
+				{"id": "r2", "type": "A", "name": "@", "value": "192.168.1.51", "ttl": 300, "zone_id": "z1"}
+			],
+			"meta": {"pagination": {"page": 1, "per_page": 100, "last_page": 2, "total_entries": 3}}
+		}`,
+		"/records?zone_id=z1&page=2&per_page=100": `{
+			"records": [
+				{"id": "r3", "type": "TXT", "name": "www", "value": "\"v=spf1 -all\"", "zone_id": "z1"}
+			],
+			"meta": {"pagination": {"page": 2, "per_page": 100, "last_page": 2, "total_entries": 3}}
+		}`,
+		"/zones/z2": `{"zone": {"id": "z2", "name": "domain.de", "ttl": 3600}}`,
+		"/records?zone_id=z2&page=1&per_page=100": `{
+			"records": [
+				{"id": "r4", "type": "MX", "name": "@", "value": "10 mail", "zone_id": "z2"},
+				{"id": "r5", "type": "CNAME", "name": "www", "value": "@", "ttl": 300, "zone_id": "z2"},
+				{"id": "r6", "type": "CNAME", "name": "ftp", "value": "files.domain.com.", "zone_id": "z2"},
+				{"id": "r7", "type": "SRV", "name": "_sip._tcp", "value": "1 10 5060 sip", "zone_id": "z2"}
+			],
+			"meta": {"pagination": {"page": 1, "per_page": 100, "last_page": 1, "total_entries": 4}}
+		}`,
+		"/zones/z4": `{"zone": {"id": "z4", "name": "domain.net", "ttl": 3600}}`,
+		"/records?zone_id=z4&page=1&per_page=100": `{"records": [], "meta": {"pagination": {"page": 1, "per_page": 100, "last_page": 1, "total_entries": 0}}}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Auth-API-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			t.Logf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	z := hz.Zones{
+		Public: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(&hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := hz.Zones{
+		Public: map[string]string{
+			"domain.com": "z1",
+			"domain.de":  "z2",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	server := fake(t)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+
+	z := hz.Zones{
+		Public: map[string]string{
+			"domain.com": "z1",
+		},
+	}
+
+	expected := `;; SOA Record
+domain.com.	86400	IN	SOA	hydrogen.ns.hetzner.com. dns.hetzner.com. 2023010101 86400 10800 3600000 3600
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+
+;; TXT Records
+www.domain.com.	86400	IN	TXT	"v=spf1 -all"
+
+`
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	content, err := afero.ReadFile(fs, "./Hetzner/domain-com.txt")
+	if err != nil {
+		t.Fatal("error reading exported zonefile:", err)
+	}
+
+	if !reflect.DeepEqual(expected, string(content)) {
+		t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", expected, string(content))
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	r, err := hz.GetRecords("z1", &hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := zone.Record{
+		Name:  "www.domain.com.",
+		Type:  "TXT",
+		TTL:   86400,
+		Value: []string{`"v=spf1 -all"`},
+	}
+
+	if len(r) != 3 || !reflect.DeepEqual(expected, r[2]) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// relative targets
+	r, err = hz.GetRecords("z2", &hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	targets := []zone.Record{
+		{Name: "domain.de.", Type: "MX", TTL: 3600, Value: []string{"10 mail.domain.de."}},
+		{Name: "www.domain.de.", Type: "CNAME", TTL: 300, Value: []string{"domain.de."}},
+		{Name: "ftp.domain.de.", Type: "CNAME", TTL: 3600, Value: []string{"files.domain.com."}},
+		{Name: "_sip._tcp.domain.de.", Type: "SRV", TTL: 3600, Value: []string{"1 10 5060 sip.domain.de."}},
+	}
+
+	if !reflect.DeepEqual(targets, r) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", targets, r)
+	}
+
+	// zone without records
+	r, err = hz.GetRecords("z4", &hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil || len(r) != 0 {
+		t.Errorf("\nEXPECTED records: \n[]\n\nGOT records: \n%+v (%v)\n\n", r, err)
+	}
+
+	// unknown zone
+	_, err = hz.GetRecords("z3", &hz.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nunexpected status code 404\n\nGOT error: \n<nil>")
+	}
+}
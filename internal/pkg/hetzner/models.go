@@ -0,0 +1,65 @@
+package hz
+
+import (
+	"net/http"
+)
+
+// Zones hosted by a DNS provider
+type Zones struct {
+	Public map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListZones(page int) (*ZonesResponse, error)
+	GetZone(id string) (*Zone, error)
+	ListRecords(zone string, page int) (*RecordsResponse, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is a Hetzner DNS REST API client
+type API struct {
+	HTTP  HTTPClient
+	URL   string
+	Token string
+}
+
+// Meta of a paginated Hetzner DNS response
+type Meta struct {
+	Pagination struct {
+		Page     int `json:"page"`
+		LastPage int `json:"last_page"`
+	} `json:"pagination"`
+}
+
+// ZonesResponse is a single page of '/zones'
+type ZonesResponse struct {
+	Zones []Zone `json:"zones"`
+	Meta  Meta   `json:"meta"`
+}
+
+// Zone is a Hetzner DNS hosted zone
+type Zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	TTL  int64  `json:"ttl"`
+}
+
+// RecordsResponse is a single page of '/records'
+type RecordsResponse struct {
+	Records []Record `json:"records"`
+	Meta    Meta     `json:"meta"`
+}
+
+// Record is a Hetzner DNS record, omitted TTL falls back to the zone default
+type Record struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	TTL   *int64 `json:"ttl"`
+}
@@ -0,0 +1,40 @@
+package hz
+
+import (
+	"sync"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+// Provider binds Hetzner DNS zones to an authenticated client
+type Provider struct {
+	Client Client
+	Zones  Zones
+}
+
+// NewProvider returns new Hetzner DNS provider
+func NewProvider(c Client) *Provider {
+	return &Provider{
+		Client: c,
+		Zones: Zones{
+			Public: make(map[string]string),
+		},
+	}
+}
+
+// Name of a provider
+func (p *Provider) Name() string {
+	return "Hetzner"
+}
+
+// Fetch hosted zones
+func (p *Provider) Fetch(errs chan error, wg *sync.WaitGroup) {
+	p.Zones.Fetch(p.Client, errs, wg)
+}
+
+// Export hosted zones
+func (p *Provider) Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	p.Zones.Export(p.Client, delay, mode, errs, wg, root, fs)
+}
@@ -0,0 +1,86 @@
+package ln
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL of a Linode REST API
+const DefaultURL string = "https://api.linode.com/v4"
+
+// pageSize is the maximum amount of items per page
+const pageSize int = 500
+
+// ListDomains returns a single page of account domains
+func (a *API) ListDomains(page int) (*DomainsResponse, error) {
+	var r DomainsResponse
+
+	err := a.get(fmt.Sprintf("/domains?page=%v&page_size=%v", page, pageSize), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// GetDomain returns a single domain
+func (a *API) GetDomain(id string) (*Domain, error) {
+	var r Domain
+
+	err := a.get(fmt.Sprintf("/domains/%s", url.PathEscape(id)), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// ListRecords returns a single page of domain records
+func (a *API) ListRecords(domain string, page int) (*RecordsResponse, error) {
+	var r RecordsResponse
+
+	err := a.get(fmt.Sprintf("/domains/%s/records?page=%v&page_size=%v", url.PathEscape(domain), page, pageSize), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// get decodes a JSON response of an API path into v
+func (a *API) get(path string, v interface{}) error {
+	request, err := http.NewRequest("GET", a.URL+path, nil)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error constructing HTTP request '%s'", path))
+	}
+
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", a.Token))
+
+	response, err := a.HTTP.Do(request)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error consuming '%s'", path))
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading responce body")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v consuming '%s': %s", response.StatusCode, path, string(b))
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+package ln
+
+var (
+	GetRecords = getRecords
+	Convert    = convert
+)
@@ -0,0 +1,155 @@
+package ln
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// defaultTTL is applied by Linode when neither a record nor a domain specify a TTL
+const defaultTTL int64 = 86400
+
+// Fetch hosted zones
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for page := 1; ; page++ {
+		o, err := c.ListDomains(page)
+		if err != nil {
+			errs <- errors.Wrap(err, "Linode: error fetching zones")
+			return
+		}
+
+		for _, domain := range o.Data {
+			z.Public[domain.Domain] = strconv.FormatInt(domain.ID, 10)
+		}
+
+		if page >= o.Pages {
+			break
+		}
+	}
+
+	errs <- nil
+}
+
+// Export hosted zones
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	// validate provider export dir
+	dir := fmt.Sprintf("%v/Linode", root)
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "Linode: error exporting zones")
+		return
+	}
+
+	// export zonefiles
+	for domain, id := range z.Public {
+		log.WithFields(log.Fields{
+			"provider": "Linode",
+			"zone":     domain,
+		}).Info("exporting zone")
+
+		r, err := getRecords(id, c)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("Linode: error retrieving zone '%s' records", domain))
+			return
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "Linode",
+			Records:  r,
+		}
+
+		// write zone exports
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("Linode: error exporting zone: '%s'", domain))
+			return
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	errs <- nil
+}
+
+// getRecords returns all records of a domain
+func getRecords(id string, c Client) ([]zone.Record, error) {
+	d, err := c.GetDomain(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving domain")
+	}
+
+	ttl := d.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	var r []zone.Record
+
+	for page := 1; ; page++ {
+		o, err := c.ListRecords(id, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving zone records")
+		}
+
+		for _, record := range o.Data {
+			r = append(r, convert(d.Domain, ttl, record))
+		}
+
+		if page >= o.Pages {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+// convert a Linode domain record into a zone record
+func convert(domain string, ttl int64, record Record) zone.Record {
+	r := zone.Record{
+		Name: domain + ".",
+		Type: record.Type,
+		TTL:  ttl,
+	}
+
+	if record.Name != "" {
+		r.Name = fmt.Sprintf("%s.%s.", record.Name, domain)
+	}
+
+	if record.TTL != 0 {
+		r.TTL = record.TTL
+	}
+
+	// targets are returned without a trailing dot, which would make them relative to a zonefile origin
+	value := record.Target
+	switch record.Type {
+	case "CNAME", "NS", "PTR":
+		value = dns.Fqdn(record.Target)
+	case "MX":
+		value = fmt.Sprintf("%v %s", record.Priority, dns.Fqdn(record.Target))
+	case "SRV":
+		value = fmt.Sprintf("%v %v %v %s", record.Priority, record.Weight, record.Port, dns.Fqdn(record.Target))
+	case "CAA":
+		value = fmt.Sprintf("0 %s %s", record.Tag, zone.Quote(record.Target))
+	case "TXT":
+		value = zone.QuoteTXT(record.Target)
+	}
+
+	r.Value = []string{value}
+
+	return r
+}
@@ -0,0 +1,220 @@
+package ln_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	ln "dns-exporter/internal/pkg/linode"
+	"dns-exporter/internal/pkg/zone"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// fake serves a Linode REST API of a single account
+func fake(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/domains?page=1&page_size=500": `{
+			"data": [{"id": 1, "domain": "domain.com", "type": "master", "ttl_sec": 0}],
+			"page": 1, "pages": 2, "results": 2
+		}`,
+		"/domains?page=2&page_size=500": `{
+			"data": [{"id": 2, "domain": "domain.org", "type": "master", "ttl_sec": 3600}],
+			"page": 2, "pages": 2, "results": 2
+		}`,
+		"/domains/1": `{"id": 1, "domain": "domain.com", "type": "master", "ttl_sec": 0}`,
+		"/domains/1/records?page=1&page_size=500": `{
+			"data": [
+				{"id": 11, "type": "A", "name": "", "target": "192.168.1.51", "ttl_sec": 300},
+				{"id": 12, "type": "MX", "name": "", "target": "mail.domain.com", "priority": 10, "ttl_sec": 0}
+			],
+			"page": 1, "pages": 2, "results": 4
+		}`,
+		"/domains/1/records?page=2&page_size=500": `{
+			"data": [
+				{"id": 13, "type": "CAA", "name": "", "target": "letsencrypt.org", "tag": "issue", "ttl_sec": 0},
+				{"id": 14, "type": "TXT", "name": "www", "target": "v=spf1 -all", "ttl_sec": 0}
+			],
+			"page": 2, "pages": 2, "results": 4
+		}`,
+		"/domains/3": `{"id": 3, "domain": "domain.net", "type": "master", "ttl_sec": 3600}`,
+		"/domains/3/records?page=1&page_size=500": `{"data": [], "page": 1, "pages": 1, "results": 0}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			t.Logf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	z := ln.Zones{
+		Public: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(&ln.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := ln.Zones{
+		Public: map[string]string{
+			"domain.com": "1",
+			"domain.org": "2",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	server := fake(t)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+
+	z := ln.Zones{
+		Public: map[string]string{
+			"domain.com": "1",
+		},
+	}
+
+	expected := `;; MX Records
+domain.com.	86400	IN	MX	10 mail.domain.com.
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+
+;; TXT Records
+www.domain.com.	86400	IN	TXT	"v=spf1 -all"
+
+;; CAA Records
+domain.com.	86400	IN	CAA	0 issue "letsencrypt.org"
+
+`
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&ln.API{HTTP: server.Client(), URL: server.URL, Token: "token"}, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	content, err := afero.ReadFile(fs, "./Linode/domain-com.txt")
+	if err != nil {
+		t.Fatal("error reading exported zonefile:", err)
+	}
+
+	if !reflect.DeepEqual(expected, string(content)) {
+		t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", expected, string(content))
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	r, err := ln.GetRecords("1", &ln.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := zone.Record{
+		Name:  "domain.com.",
+		Type:  "A",
+		TTL:   300,
+		Value: []string{"192.168.1.51"},
+	}
+
+	if len(r) != 4 || !reflect.DeepEqual(expected, r[0]) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// domain without records
+	r, err = ln.GetRecords("3", &ln.API{HTTP: server.Client(), URL: server.URL, Token: "token"})
+	if err != nil || len(r) != 0 {
+		t.Errorf("\nEXPECTED records: \n[]\n\nGOT records: \n%+v (%v)\n\n", r, err)
+	}
+
+	// invalid token
+	_, err = ln.GetRecords("1", &ln.API{HTTP: server.Client(), URL: server.URL, Token: "invalid"})
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nunexpected status code 401\n\nGOT error: \n<nil>")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   ln.Record
+		expected zone.Record
+	}{
+		{
+			name:     "domain TTL",
+			record:   ln.Record{Type: "A", Name: "www", Target: "192.168.1.51"},
+			expected: zone.Record{Name: "www.domain.com.", Type: "A", TTL: 3600, Value: []string{"192.168.1.51"}},
+		},
+		{
+			name:     "CNAME target",
+			record:   ln.Record{Type: "CNAME", Name: "www", Target: "domain.com", TTL: 300},
+			expected: zone.Record{Name: "www.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}},
+		},
+		{
+			name:     "NS target",
+			record:   ln.Record{Type: "NS", Name: "sub", Target: "ns1.linode.com", TTL: 300},
+			expected: zone.Record{Name: "sub.domain.com.", Type: "NS", TTL: 300, Value: []string{"ns1.linode.com."}},
+		},
+		{
+			name:     "SRV target",
+			record:   ln.Record{Type: "SRV", Name: "_sip._tcp", Target: "sip.domain.com", Priority: 1, Weight: 10, Port: 5060, TTL: 300},
+			expected: zone.Record{Name: "_sip._tcp.domain.com.", Type: "SRV", TTL: 300, Value: []string{"1 10 5060 sip.domain.com."}},
+		},
+		{
+			name:     "TXT escapes",
+			record:   ln.Record{Type: "TXT", Name: "", Target: "say \"hi\"\tcafé", TTL: 300},
+			expected: zone.Record{Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"say \"hi\"\009caf\195\169"`}},
+		},
+	}
+
+	for _, test := range tests {
+		r := ln.Convert("domain.com", 3600, test.record)
+		if !reflect.DeepEqual(test.expected, r) {
+			t.Errorf("%s\nEXPECTED record: \n%+v\n\nGOT record: \n%+v\n\n", test.name, test.expected, r)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ln
+
+import (
+	"net/http"
+)
+
+// Zones hosted by a DNS provider
+type Zones struct {
+	Public map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListDomains(page int) (*DomainsResponse, error)
+	GetDomain(id string) (*Domain, error)
+	ListRecords(domain string, page int) (*RecordsResponse, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is a Linode REST API client
+type API struct {
+	HTTP  HTTPClient
+	URL   string
+	Token string
+}
+
+// DomainsResponse is a single page of '/domains'
+type DomainsResponse struct {
+	Data  []Domain `json:"data"`
+	Page  int      `json:"page"`
+	Pages int      `json:"pages"`
+}
+
+// Domain is a Linode hosted zone
+type Domain struct {
+	ID     int64  `json:"id"`
+	Domain string `json:"domain"`
+	Type   string `json:"type"`
+	TTL    int64  `json:"ttl_sec"`
+}
+
+// RecordsResponse is a single page of '/domains/{id}/records'
+type RecordsResponse struct {
+	Data  []Record `json:"data"`
+	Page  int      `json:"page"`
+	Pages int      `json:"pages"`
+}
+
+// Record is a Linode domain record, zero TTL falls back to the domain default
+type Record struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Target   string `json:"target"`
+	Priority int    `json:"priority"`
+	Weight   int    `json:"weight"`
+	Port     int    `json:"port"`
+	Tag      string `json:"tag"`
+	TTL      int64  `json:"ttl_sec"`
+}
@@ -0,0 +1,85 @@
+package pdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultServer is a PowerDNS server ID of a standalone installation
+const DefaultServer string = "localhost"
+
+// ListZones returns all zones of a server
+func (a *API) ListZones() ([]Zone, error) {
+	var r []Zone
+
+	err := a.get("/zones", &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetZone returns a zone including its record sets and comments
+func (a *API) GetZone(id string) (*Zone, error) {
+	var r Zone
+
+	err := a.get(fmt.Sprintf("/zones/%s", url.PathEscape(id)), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// ListCryptokeys returns DNSSEC keys of a zone
+func (a *API) ListCryptokeys(id string) ([]Cryptokey, error) {
+	var r []Cryptokey
+
+	err := a.get(fmt.Sprintf("/zones/%s/cryptokeys", url.PathEscape(id)), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// get decodes a JSON response of a server API path into v
+func (a *API) get(path string, v interface{}) error {
+	u := fmt.Sprintf("%s/api/v1/servers/%s%s", a.URL, url.PathEscape(a.Server), path)
+
+	request, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error constructing HTTP request '%s'", path))
+	}
+
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("X-API-Key", a.Key)
+
+	response, err := a.HTTP.Do(request)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error consuming '%s'", path))
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading responce body")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v consuming '%s': %s", response.StatusCode, path, string(b))
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error decoding '%s' responce", path))
+	}
+
+	return nil
+}
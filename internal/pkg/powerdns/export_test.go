@@ -0,0 +1,3 @@
+package pdns
+
+var GetZone = getZone
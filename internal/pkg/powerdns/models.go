@@ -0,0 +1,87 @@
+package pdns
+
+import (
+	"net/http"
+)
+
+// Zones hosted by a DNS provider
+type Zones struct {
+	Public map[string]string
+}
+
+// Client interface
+type Client interface {
+	ListZones() ([]Zone, error)
+	GetZone(id string) (*Zone, error)
+	ListCryptokeys(id string) ([]Cryptokey, error)
+}
+
+// HTTPClient interface
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API is a PowerDNS Authoritative HTTP API client
+type API struct {
+	HTTP   HTTPClient
+	URL    string
+	Server string
+	Key    string
+}
+
+// Zone is a PowerDNS zone, record sets are returned only for a single zone request
+type Zone struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	DNSSEC  bool     `json:"dnssec"`
+	Serial  int64    `json:"serial"`
+	Masters []string `json:"masters"`
+	Account string   `json:"account"`
+	RRSets  []RRSet  `json:"rrsets"`
+}
+
+// RRSet is a PowerDNS resource record set
+type RRSet struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	TTL      int64     `json:"ttl"`
+	Records  []Record  `json:"records"`
+	Comments []Comment `json:"comments"`
+}
+
+// Record is a single PowerDNS record of a record set
+type Record struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Comment of a PowerDNS record set
+type Comment struct {
+	Content    string `json:"content"`
+	Account    string `json:"account"`
+	ModifiedAt int64  `json:"modified_at"`
+}
+
+// Cryptokey is a DNSSEC key of a zone
+type Cryptokey struct {
+	ID        int      `json:"id"`
+	KeyType   string   `json:"keytype"`
+	Active    bool     `json:"active"`
+	Published bool     `json:"published"`
+	Algorithm string   `json:"algorithm"`
+	Bits      int      `json:"bits"`
+	DNSKey    string   `json:"dnskey"`
+	DS        []string `json:"ds,omitempty"`
+}
+
+// Metadata of a PowerDNS zone, exported next to a zonefile
+type Metadata struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Kind       string      `json:"kind"`
+	DNSSEC     bool        `json:"dnssec"`
+	Masters    []string    `json:"masters,omitempty"`
+	Account    string      `json:"account,omitempty"`
+	Cryptokeys []Cryptokey `json:"cryptokeys,omitempty"`
+}
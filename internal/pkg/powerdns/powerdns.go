@@ -0,0 +1,153 @@
+package pdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Fetch hosted zones
+func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	r, err := c.ListZones()
+	if err != nil {
+		errs <- errors.Wrap(err, "PowerDNS: error fetching zones")
+		return
+	}
+
+	for _, zone := range r {
+		z.Public[zone.Name] = zone.ID
+	}
+
+	errs <- nil
+}
+
+// Export hosted zones and their metadata
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	defer wg.Done()
+
+	// validate provider export dir
+	dir := fmt.Sprintf("%v/PowerDNS", root)
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "PowerDNS: error exporting zones")
+		return
+	}
+
+	// export zonefiles
+	for domain, id := range z.Public {
+		log.WithFields(log.Fields{
+			"provider": "PowerDNS",
+			"zone":     strings.TrimSuffix(domain, "."),
+		}).Info("exporting zone")
+
+		r, m, err := getZone(id, c)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("PowerDNS: error retrieving zone '%s' records", domain))
+			return
+		}
+
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "PowerDNS",
+			Records:  r,
+		}
+
+		metadata, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("PowerDNS: error composing metadata for '%s' zone", domain))
+			return
+		}
+
+		// write zone exports and metadata
+		err = records.Write(dir, mode, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("PowerDNS: error exporting zone: '%s'", domain))
+			return
+		}
+
+		_, err = utils.WriteToFileAs(domain, "meta.json", string(metadata)+"\n", dir, fs)
+		if err != nil {
+			errs <- errors.Wrap(err, fmt.Sprintf("PowerDNS: error exporting zone metadata: '%s'", domain))
+			return
+		}
+
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	errs <- nil
+}
+
+// getZone returns zone records and metadata, including DNSSEC keys of signed zones
+func getZone(id string, c Client) ([]zone.Record, Metadata, error) {
+	z, err := c.GetZone(id)
+	if err != nil {
+		return nil, Metadata{}, errors.Wrap(err, "error retrieving zone")
+	}
+
+	m := Metadata{
+		ID:      z.ID,
+		Name:    z.Name,
+		Kind:    z.Kind,
+		DNSSEC:  z.DNSSEC,
+		Masters: z.Masters,
+		Account: z.Account,
+	}
+
+	if z.DNSSEC {
+		m.Cryptokeys, err = c.ListCryptokeys(id)
+		if err != nil {
+			return nil, Metadata{}, errors.Wrap(err, "error retrieving zone DNSSEC keys")
+		}
+	}
+
+	var r []zone.Record
+	for _, set := range z.RRSets {
+		var comments []string
+		for _, comment := range set.Comments {
+			comments = append(comments, comment.Content)
+		}
+
+		enabled := zone.Record{
+			Name:    set.Name,
+			Type:    set.Type,
+			TTL:     set.TTL,
+			Comment: strings.Join(comments, "; "),
+		}
+		disabled := enabled
+		disabled.Disabled = true
+
+		for _, record := range set.Records {
+			if record.Disabled {
+				disabled.Value = append(disabled.Value, record.Content)
+			} else {
+				enabled.Value = append(enabled.Value, record.Content)
+			}
+		}
+
+		// keep comments of record sets without records
+		if len(enabled.Value) > 0 || len(disabled.Value) == 0 {
+			r = append(r, enabled)
+		}
+
+		if len(disabled.Value) > 0 {
+			if len(enabled.Value) > 0 {
+				disabled.Comment = ""
+			}
+			r = append(r, disabled)
+		}
+	}
+
+	return r, m, nil
+}
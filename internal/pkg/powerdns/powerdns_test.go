@@ -0,0 +1,263 @@
+package pdns_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	pdns "dns-exporter/internal/pkg/powerdns"
+	"dns-exporter/internal/pkg/zone"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// fake serves a PowerDNS Authoritative HTTP API of a single server
+func fake(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/api/v1/servers/localhost/zones": `[
+			{"id": "domain.com.", "name": "domain.com.", "kind": "Native", "dnssec": true, "serial": 2023010101},
+			{"id": "domain.org.", "name": "domain.org.", "kind": "Slave", "dnssec": false, "masters": ["192.168.1.1"]}
+		]`,
+		"/api/v1/servers/localhost/zones/domain.com.": `{
+			"id": "domain.com.", "name": "domain.com.", "kind": "Native", "dnssec": true, "serial": 2023010101, "masters": [],
+			"rrsets": [
+				{
+					"name": "domain.com.", "type": "SOA", "ttl": 3600,
+					"records": [{"content": "ns1.domain.com. hostmaster.domain.com. 2023010101 10800 3600 604800 3600", "disabled": false}],
+					"comments": []
+				},
+				{
+					"name": "www.domain.com.", "type": "A", "ttl": 300,
+					"records": [
+						{"content": "192.168.1.51", "disabled": false},
+						{"content": "192.168.1.52", "disabled": true}
+					],
+					"comments": [{"content": "web servers", "account": "ops", "modified_at": 1672531200}]
+				}
+			]
+		}`,
+		"/api/v1/servers/localhost/zones/domain.org.": `{
+			"id": "domain.org.", "name": "domain.org.", "kind": "Slave", "dnssec": false, "masters": ["192.168.1.1"],
+			"rrsets": [
+				{
+					"name": "old.domain.org.", "type": "CNAME", "ttl": 60,
+					"records": [{"content": "legacy.domain.org.", "disabled": true}],
+					"comments": [{"content": "retired", "account": "ops", "modified_at": 1672531200}]
+				},
+				{
+					"name": "new.domain.org.", "type": "A", "ttl": 300,
+					"records": [],
+					"comments": [{"content": "reserved", "account": "ops", "modified_at": 1672531200}]
+				}
+			]
+		}`,
+		"/api/v1/servers/localhost/zones/domain.net.": `{"id": "domain.net.", "name": "domain.net.", "kind": "Native", "dnssec": false, "masters": [], "rrsets": []}`,
+		"/api/v1/servers/localhost/zones/domain.com./cryptokeys": `[
+			{"id": 1, "keytype": "csk", "active": true, "published": true, "algorithm": "ECDSAP256SHA256", "bits": 256, "dnskey": "257 3 13 aGVsbG8=", "ds": ["12345 13 2 abcdef"]}
+		]`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			t.Logf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	z := pdns.Zones{
+		Public: make(map[string]string),
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Fetch(&pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "key"}, errs, &wg)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := pdns.Zones{
+		Public: map[string]string{
+			"domain.com.": "domain.com.",
+			"domain.org.": "domain.org.",
+		},
+	}
+
+	if !reflect.DeepEqual(z, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, z)
+	}
+}
+
+func TestExport(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	server := fake(t)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+
+	z := pdns.Zones{
+		Public: map[string]string{
+			"domain.com.": "domain.com.",
+		},
+	}
+
+	expected := map[string]string{
+		"./PowerDNS/domain-com.txt": `;; SOA Record
+domain.com.	3600	IN	SOA	ns1.domain.com. hostmaster.domain.com. 2023010101 10800 3600 604800 3600
+
+;; A Records
+; web servers
+www.domain.com.	300	IN	A	192.168.1.51
+; disabled: www.domain.com.	300	IN	A	192.168.1.52
+
+`,
+		"./PowerDNS/domain-com.meta.json": `{
+  "id": "domain.com.",
+  "name": "domain.com.",
+  "kind": "Native",
+  "dnssec": true,
+  "cryptokeys": [
+    {
+      "id": 1,
+      "keytype": "csk",
+      "active": true,
+      "published": true,
+      "algorithm": "ECDSAP256SHA256",
+      "bits": 256,
+      "dnskey": "257 3 13 aGVsbG8=",
+      "ds": [
+        "12345 13 2 abcdef"
+      ]
+    }
+  ]
+}
+`,
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	z.Export(&pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "key"}, 0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for file, body := range expected {
+		content, err := afero.ReadFile(fs, file)
+		if err != nil {
+			t.Fatal("error reading exported file:", err)
+		}
+
+		if !reflect.DeepEqual(body, string(content)) {
+			t.Errorf("\nEXPECTED content: \n'%+v'\n\nGOT content: \n'%+v'\n\n", body, string(content))
+		}
+	}
+}
+
+func TestGetZone(t *testing.T) {
+	server := fake(t)
+	defer server.Close()
+
+	r, m, err := pdns.GetZone("domain.com.", &pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "key"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := []zone.Record{
+		{
+			Name:  "domain.com.",
+			Type:  "SOA",
+			TTL:   3600,
+			Value: []string{"ns1.domain.com. hostmaster.domain.com. 2023010101 10800 3600 604800 3600"},
+		},
+		{
+			Name:    "www.domain.com.",
+			Type:    "A",
+			TTL:     300,
+			Value:   []string{"192.168.1.51"},
+			Comment: "web servers",
+		},
+		{
+			Name:     "www.domain.com.",
+			Type:     "A",
+			TTL:      300,
+			Value:    []string{"192.168.1.52"},
+			Disabled: true,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, r) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	if m.Kind != "Native" || !m.DNSSEC || len(m.Cryptokeys) != 1 {
+		t.Errorf("\nEXPECTED metadata: \nNative zone with a single DNSSEC key\n\nGOT metadata: \n%+v\n\n", m)
+	}
+
+	// disabled record sets and comments without records
+	r, _, err = pdns.GetZone("domain.org.", &pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "key"})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected = []zone.Record{
+		{
+			Name:     "old.domain.org.",
+			Type:     "CNAME",
+			TTL:      60,
+			Value:    []string{"legacy.domain.org."},
+			Comment:  "retired",
+			Disabled: true,
+		},
+		{
+			Name:    "new.domain.org.",
+			Type:    "A",
+			TTL:     300,
+			Comment: "reserved",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, r) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
+	}
+
+	// zone without record sets
+	r, m, err = pdns.GetZone("domain.net.", &pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "key"})
+	if err != nil || len(r) != 0 || len(m.Cryptokeys) != 0 {
+		t.Errorf("\nEXPECTED records: \n[]\n\nGOT records: \n%+v (%v)\n\n", r, err)
+	}
+
+	// invalid API key
+	_, _, err = pdns.GetZone("domain.com.", &pdns.API{HTTP: server.Client(), URL: server.URL, Server: pdns.DefaultServer, Key: "invalid"})
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nunexpected status code 401\n\nGOT error: \n<nil>")
+	}
+}
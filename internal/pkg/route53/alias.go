@@ -0,0 +1,66 @@
+package r53
+
+import (
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// cloudFrontZoneID is a hosted zone of every CloudFront distribution
+const cloudFrontZoneID = "Z2FDTNDATAQYW2"
+
+// target returns alias target of a record set
+func target(a *route53.AliasTarget) *zone.AliasTarget {
+	t := zone.AliasTarget{}
+
+	if a.HostedZoneId != nil {
+		t.HostedZoneID = *a.HostedZoneId
+	}
+
+	if a.EvaluateTargetHealth != nil {
+		t.EvaluateTargetHealth = *a.EvaluateTargetHealth
+	}
+
+	var name string
+	if a.DNSName != nil {
+		name = strings.TrimSuffix(strings.ToLower(*a.DNSName), ".")
+	}
+
+	t.Service = service(name, t.HostedZoneID)
+
+	return &t
+}
+
+// service returns name of an AWS service an alias points at, empty when unknown
+func service(name, zoneID string) string {
+	switch {
+	case zoneID == cloudFrontZoneID || strings.HasSuffix(name, ".cloudfront.net"):
+		return "CloudFront"
+	case strings.HasSuffix(name, ".amazonaws.com") && strings.Contains(name, ".elb."):
+		return "ELB"
+	case strings.HasPrefix(name, "s3-website") || strings.Contains(name, ".s3-website"):
+		return "S3 website"
+	case strings.Contains(name, ".execute-api."):
+		return "API Gateway"
+	case strings.HasSuffix(name, ".awsglobalaccelerator.com"):
+		return "Global Accelerator"
+	}
+
+	return ""
+}
+
+// resolveLocalAliases marks aliases to other records of the same hosted zone
+func (r *Records) resolveLocalAliases(id string) {
+	id = strings.TrimPrefix(id, "/hostedzone/")
+
+	for _, b := range r.buckets() {
+		for i := range *b {
+			t := (*b)[i].Target
+			if t != nil && t.Service == "" && t.HostedZoneID == id {
+				t.Service = "Route53"
+			}
+		}
+	}
+}
@@ -1,4 +1,13 @@
 package r53
 
 var GetRecords = getRecords
-var Format = format
+var GetMetadata = getMetadata
+var ExportPolicies = exportPolicies
+var Terraform = terraform
+var Diff = diff
+var Batches = batches
+var WaitInterval = &waitInterval
+
+func (r *Records) Reference(refs references, zoneID string) {
+	r.reference(refs, zoneID)
+}
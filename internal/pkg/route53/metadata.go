@@ -0,0 +1,125 @@
+package r53
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+)
+
+// getMetadata returns hosted zone metadata, DNSSEC and query logging are retrieved for public zones only.
+// Every part the IAM policy does not permit reading is skipped with a warning.
+func getMetadata(id string, private bool, c Client) (Metadata, error) {
+	m := Metadata{
+		ID:      id,
+		Private: private,
+	}
+
+	z, err := c.GetHostedZone(&route53.GetHostedZoneInput{
+		Id: aws.String(id),
+	})
+	switch {
+	case denied(err, id, "hosted zone details", "route53:GetHostedZone"):
+	case err != nil:
+		return Metadata{}, errors.Wrap(err, "error retrieving hosted zone")
+	default:
+		m.ID = aws.StringValue(z.HostedZone.Id)
+		m.Name = aws.StringValue(z.HostedZone.Name)
+
+		if z.HostedZone.Config != nil {
+			m.Comment = aws.StringValue(z.HostedZone.Config.Comment)
+		}
+
+		if z.DelegationSet != nil {
+			m.DelegationSet = &DelegationSet{
+				ID:          aws.StringValue(z.DelegationSet.Id),
+				Reusable:    z.DelegationSet.Id != nil,
+				NameServers: aws.StringValueSlice(z.DelegationSet.NameServers),
+			}
+		}
+
+		for _, vpc := range z.VPCs {
+			m.VPCs = append(m.VPCs, VPC{
+				ID:     aws.StringValue(vpc.VPCId),
+				Region: aws.StringValue(vpc.VPCRegion),
+			})
+		}
+	}
+
+	t, err := c.ListTagsForResource(&route53.ListTagsForResourceInput{
+		ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		ResourceId:   aws.String(strings.TrimPrefix(id, "/hostedzone/")),
+	})
+	switch {
+	case denied(err, id, "hosted zone tags", "route53:ListTagsForResource"):
+	case err != nil:
+		return Metadata{}, errors.Wrap(err, "error retrieving hosted zone tags")
+	case t.ResourceTagSet != nil && len(t.ResourceTagSet.Tags) > 0:
+		m.Tags = make(map[string]string)
+		for _, tag := range t.ResourceTagSet.Tags {
+			m.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+
+	if private {
+		return m, nil
+	}
+
+	d, err := c.GetDNSSEC(&route53.GetDNSSECInput{
+		HostedZoneId: aws.String(id),
+	})
+	switch {
+	case denied(err, id, "hosted zone DNSSEC", "route53:GetDNSSEC"):
+	case err != nil:
+		return Metadata{}, errors.Wrap(err, "error retrieving hosted zone DNSSEC")
+	default:
+		m.DNSSEC = &DNSSEC{}
+		if d.Status != nil {
+			m.DNSSEC.Status = aws.StringValue(d.Status.ServeSignature)
+			m.DNSSEC.Message = aws.StringValue(d.Status.StatusMessage)
+		}
+
+		for _, k := range d.KeySigningKeys {
+			m.DNSSEC.KeySigningKeys = append(m.DNSSEC.KeySigningKeys, KeySigningKey{
+				Name:      aws.StringValue(k.Name),
+				Status:    aws.StringValue(k.Status),
+				KMSKey:    aws.StringValue(k.KmsArn),
+				KeyTag:    aws.Int64Value(k.KeyTag),
+				Algorithm: aws.StringValue(k.SigningAlgorithmMnemonic),
+				DNSKey:    aws.StringValue(k.DNSKEYRecord),
+				DS:        aws.StringValue(k.DSRecord),
+			})
+		}
+	}
+
+	var token *string
+	for {
+		q, err := c.ListQueryLoggingConfigs(&route53.ListQueryLoggingConfigsInput{
+			HostedZoneId: aws.String(strings.TrimPrefix(id, "/hostedzone/")),
+			NextToken:    token,
+		})
+		if denied(err, id, "hosted zone query logging", "route53:ListQueryLoggingConfigs") {
+			m.QueryLogging = nil
+			break
+		}
+		if err != nil {
+			return Metadata{}, errors.Wrap(err, "error retrieving hosted zone query logging configs")
+		}
+
+		for _, config := range q.QueryLoggingConfigs {
+			m.QueryLogging = append(m.QueryLogging, QueryLogging{
+				ID:       aws.StringValue(config.Id),
+				LogGroup: aws.StringValue(config.CloudWatchLogsLogGroupArn),
+			})
+		}
+
+		if q.NextToken == nil {
+			break
+		}
+
+		token = q.NextToken
+	}
+
+	return m, nil
+}
@@ -1,6 +1,10 @@
 package r53
 
 import (
+	"encoding/json"
+
+	"dns-exporter/internal/pkg/zone"
+
 	"github.com/aws/aws-sdk-go/service/route53"
 )
 
@@ -14,28 +18,134 @@ type Zones struct {
 type Client interface {
 	ListHostedZones(*route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error)
 	ListResourceRecordSets(*route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error)
+	GetHostedZone(*route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error)
+	ListTagsForResource(*route53.ListTagsForResourceInput) (*route53.ListTagsForResourceOutput, error)
+	GetDNSSEC(*route53.GetDNSSECInput) (*route53.GetDNSSECOutput, error)
+	ListQueryLoggingConfigs(*route53.ListQueryLoggingConfigsInput) (*route53.ListQueryLoggingConfigsOutput, error)
+	ListHealthChecks(*route53.ListHealthChecksInput) (*route53.ListHealthChecksOutput, error)
+	ListTagsForResources(*route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error)
+	ListTrafficPolicies(*route53.ListTrafficPoliciesInput) (*route53.ListTrafficPoliciesOutput, error)
+	ListTrafficPolicyVersions(*route53.ListTrafficPolicyVersionsInput) (*route53.ListTrafficPolicyVersionsOutput, error)
+	ListTrafficPolicyInstances(*route53.ListTrafficPolicyInstancesInput) (*route53.ListTrafficPolicyInstancesOutput, error)
+	ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error)
+	GetChange(*route53.GetChangeInput) (*route53.GetChangeOutput, error)
 }
 
 // Records represents a zonefile content
 type Records struct {
-	SOA   Record
-	NS    []Record
-	MX    []Record
-	A     []Record
-	AAAA  []Record
-	CNAME []Record
-	TXT   []Record
-	SRV   []Record
-	PTR   []Record
-	SPF   []Record
-	NAPTR []Record
-	CAA   []Record
-}
-
-// Record is a single DNS record
-type Record struct {
-	Name  string
-	Value []string
-	TTL   int64
-	Alias bool
+	SOA   zone.Record
+	NS    []zone.Record
+	MX    []zone.Record
+	A     []zone.Record
+	AAAA  []zone.Record
+	CNAME []zone.Record
+	TXT   []zone.Record
+	SRV   []zone.Record
+	PTR   []zone.Record
+	SPF   []zone.Record
+	NAPTR []zone.Record
+	CAA   []zone.Record
+	DS    []zone.Record
+	HTTPS []zone.Record
+	SVCB  []zone.Record
+	TLSA  []zone.Record
+	SSHFP []zone.Record
+	Other []zone.Record
+}
+
+// Metadata of a hosted zone, exported next to a zonefile
+type Metadata struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Private       bool              `json:"private"`
+	Comment       string            `json:"comment,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	DelegationSet *DelegationSet    `json:"delegation_set,omitempty"`
+	VPCs          []VPC             `json:"vpcs,omitempty"`
+	DNSSEC        *DNSSEC           `json:"dnssec,omitempty"`
+	QueryLogging  []QueryLogging    `json:"query_logging,omitempty"`
+}
+
+// DelegationSet of a hosted zone, ID is set for a reusable delegation set only
+type DelegationSet struct {
+	ID          string   `json:"id,omitempty"`
+	Reusable    bool     `json:"reusable"`
+	NameServers []string `json:"name_servers"`
+}
+
+// VPC associated with a private hosted zone
+type VPC struct {
+	ID     string `json:"id"`
+	Region string `json:"region"`
+}
+
+// DNSSEC signing status of a hosted zone
+type DNSSEC struct {
+	Status         string          `json:"status"`
+	Message        string          `json:"message,omitempty"`
+	KeySigningKeys []KeySigningKey `json:"key_signing_keys,omitempty"`
+}
+
+// KeySigningKey of a DNSSEC signed hosted zone
+type KeySigningKey struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	KMSKey    string `json:"kms_key"`
+	KeyTag    int64  `json:"key_tag"`
+	Algorithm string `json:"algorithm"`
+	DNSKey    string `json:"dnskey"`
+	DS        string `json:"ds"`
+}
+
+// QueryLogging config of a hosted zone
+type QueryLogging struct {
+	ID       string `json:"id"`
+	LogGroup string `json:"log_group"`
+}
+
+// HealthCheck with its configuration and tags, exported into 'health-checks.json'
+type HealthCheck struct {
+	ID            string                                `json:"id"`
+	Name          string                                `json:"name"`
+	Version       int64                                 `json:"version"`
+	Config        *route53.HealthCheckConfig            `json:"config"`
+	Alarm         *route53.CloudWatchAlarmConfiguration `json:"cloudwatch_alarm,omitempty"`
+	LinkedService *route53.LinkedService                `json:"linked_service,omitempty"`
+	Tags          map[string]string                     `json:"tags,omitempty"`
+}
+
+// TrafficPolicy with its versions and instances, exported into 'traffic-policies.json'
+type TrafficPolicy struct {
+	ID            string                  `json:"id"`
+	Name          string                  `json:"name"`
+	Type          string                  `json:"type"`
+	LatestVersion int64                   `json:"latest_version"`
+	Versions      []TrafficPolicyVersion  `json:"versions"`
+	Instances     []TrafficPolicyInstance `json:"instances,omitempty"`
+}
+
+// TrafficPolicyVersion holds a traffic policy document
+type TrafficPolicyVersion struct {
+	Version  int64           `json:"version"`
+	Comment  string          `json:"comment,omitempty"`
+	Document json.RawMessage `json:"document"`
+}
+
+// TrafficPolicyInstance is a record set managed by a traffic policy
+type TrafficPolicyInstance struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	HostedZoneID string `json:"hosted_zone_id"`
+	TTL          int64  `json:"ttl"`
+	Version      int64  `json:"version"`
+	State        string `json:"state"`
+}
+
+// references of an account resolved while annotating records
+type references struct {
+	// checks maps health check id to its readable name
+	checks map[string]string
+	// instances maps an instance key to a traffic policy instance description
+	instances map[string]string
 }
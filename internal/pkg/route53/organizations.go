@@ -0,0 +1,146 @@
+package r53
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Organizations interface
+type Organizations interface {
+	ListAccounts(*organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error)
+}
+
+// STS interface
+type STS interface {
+	stscreds.AssumeRoler
+}
+
+// Account is a member of an AWS Organization
+type Account struct {
+	ID        string
+	Name      string
+	Partition string
+}
+
+var unsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Dir returns name of a directory the account is exported into
+func (a Account) Dir() string {
+	name := strings.Trim(unsafe.ReplaceAllString(strings.ToLower(a.Name), "-"), "-")
+	if name == "" {
+		return a.ID
+	}
+
+	return fmt.Sprintf("%s-%s", a.ID, name)
+}
+
+// ListAccounts returns active accounts of an organization
+func ListAccounts(c Organizations) ([]Account, error) {
+	var accounts []Account
+
+	var token *string
+	for {
+		o, err := c.ListAccounts(&organizations.ListAccountsInput{
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing organization accounts")
+		}
+
+		for _, a := range o.Accounts {
+			if a.Status == nil || *a.Status != organizations.AccountStatusActive {
+				continue
+			}
+
+			account := Account{
+				ID:        *a.Id,
+				Partition: "aws",
+			}
+
+			if a.Name != nil {
+				account.Name = *a.Name
+			}
+
+			if a.Arn != nil {
+				if parsed, err := arn.Parse(*a.Arn); err == nil {
+					account.Partition = parsed.Partition
+				}
+			}
+
+			accounts = append(accounts, account)
+		}
+
+		if o.NextToken == nil || *o.NextToken == "" {
+			break
+		}
+
+		token = o.NextToken
+	}
+
+	return accounts, nil
+}
+
+// Discover returns a provider for every active account of an organization, authenticated by assuming a role in it.
+// A role that cannot be assumed fails a discovery, accounts listed in 'skip' (such as a management account without the role) are not assumed
+// and their providers only mark an export directory as skipped.
+func Discover(o Organizations, s STS, role string, skip []string, clients func(*credentials.Credentials) (Client, map[string]Resolver)) ([]*Provider, error) {
+	accounts, err := ListAccounts(o)
+	if err != nil {
+		return nil, errors.Wrap(err, "Route53: error discovering organization accounts")
+	}
+
+	var providers []*Provider
+	for _, a := range accounts {
+		if skipped(skip, a.ID) {
+			log.WithFields(log.Fields{
+				"provider": "Route53",
+				"account":  a.ID,
+			}).Warn("skipping organization account")
+
+			p := NewProvider(nil)
+			p.Account = a.Dir()
+			p.Skipped = true
+
+			providers = append(providers, p)
+			continue
+		}
+
+		c := stscreds.NewCredentialsWithClient(s, fmt.Sprintf("arn:%s:iam::%s:role/%s", a.Partition, a.ID, role), func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = "dns-exporter"
+		})
+
+		_, err := c.Get()
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Route53: error assuming role '%s' in account '%s', list the account in 'ROUTE53_ORGANIZATION_SKIP' to skip it", role, a.ID))
+		}
+
+		client, resolvers := clients(c)
+
+		p := NewProvider(client)
+		p.Account = a.Dir()
+		p.Resolvers = resolvers
+
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
+
+// skipped reports whether an account is listed to be skipped
+func skipped(skip []string, id string) bool {
+	for _, s := range skip {
+		if s == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,153 @@
+package r53_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/sts"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestListAccounts(t *testing.T) {
+	c := mocks.Organizations{}
+
+	reply1 := &organizations.ListAccountsOutput{
+		NextToken: aws.String("next"),
+		Accounts: []*organizations.Account{
+			{
+				Arn:    aws.String("arn:aws:organizations::111111111111:account/o-abc/111111111111"),
+				Id:     aws.String("111111111111"),
+				Name:   aws.String("Production"),
+				Status: aws.String("ACTIVE"),
+			},
+			{
+				Arn:    aws.String("arn:aws:organizations::111111111111:account/o-abc/222222222222"),
+				Id:     aws.String("222222222222"),
+				Name:   aws.String("Closed"),
+				Status: aws.String("SUSPENDED"),
+			},
+		},
+	}
+
+	reply2 := &organizations.ListAccountsOutput{
+		Accounts: []*organizations.Account{
+			{
+				Arn:    aws.String("arn:aws-cn:organizations::111111111111:account/o-abc/333333333333"),
+				Id:     aws.String("333333333333"),
+				Name:   aws.String("Dev Team (China)"),
+				Status: aws.String("ACTIVE"),
+			},
+		},
+	}
+
+	c.On("ListAccounts").Return(reply1, nil).Once()
+	c.On("ListAccounts").Return(reply2, nil).Once()
+
+	accounts, err := r53.ListAccounts(&c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := []r53.Account{
+		{
+			ID:        "111111111111",
+			Name:      "Production",
+			Partition: "aws",
+		},
+		{
+			ID:        "333333333333",
+			Name:      "Dev Team (China)",
+			Partition: "aws-cn",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, accounts) {
+		t.Errorf("\nEXPECTED accounts: \n%+v\n\nGOT accounts: \n%+v\n\n", expected, accounts)
+	}
+
+	dirs := []string{"111111111111-production", "333333333333-dev-team-china"}
+	for i, a := range accounts {
+		if a.Dir() != dirs[i] {
+			t.Errorf("\nEXPECTED directory: \n%s\n\nGOT directory: \n%s\n\n", dirs[i], a.Dir())
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	o := mocks.Organizations{}
+	s := mocks.STS{}
+
+	o.On("ListAccounts").Return(&organizations.ListAccountsOutput{
+		Accounts: []*organizations.Account{
+			{
+				Arn:    aws.String("arn:aws:organizations::111111111111:account/o-abc/111111111111"),
+				Id:     aws.String("111111111111"),
+				Name:   aws.String("production"),
+				Status: aws.String("ACTIVE"),
+			},
+			{
+				Arn:    aws.String("arn:aws:organizations::111111111111:account/o-abc/222222222222"),
+				Id:     aws.String("222222222222"),
+				Name:   aws.String("sandbox"),
+				Status: aws.String("ACTIVE"),
+			},
+		},
+	}, nil).Twice()
+
+	s.On("AssumeRole", "arn:aws:iam::111111111111:role/dns-exporter").Return(&sts.AssumeRoleOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("AKIA"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("token"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil).Twice()
+
+	s.On("AssumeRole", "arn:aws:iam::222222222222:role/dns-exporter").Return(&sts.AssumeRoleOutput{}, errors.New("AccessDenied")).Once()
+
+	var assumed []string
+	clients := func(c *credentials.Credentials) (r53.Client, map[string]r53.Resolver) {
+		v, _ := c.Get()
+		assumed = append(assumed, v.AccessKeyID)
+
+		return &mocks.Route53{}, nil
+	}
+
+	// a role that cannot be assumed fails a discovery
+	_, err := r53.Discover(&o, &s, "dns-exporter", nil, clients)
+	if err == nil || !strings.Contains(err.Error(), "'222222222222'") {
+		t.Fatalf("\nEXPECTED error: \nRoute53: error assuming role 'dns-exporter' in account '222222222222'\n\nGOT error: \n%v\n\n", err)
+	}
+
+	// a skipped account is not assumed
+	providers, err := r53.Discover(&o, &s, "dns-exporter", []string{"222222222222"}, clients)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(providers) != 2 || providers[0].Account != "111111111111-production" || providers[1].Account != "222222222222-sandbox" {
+		t.Fatalf("\nEXPECTED providers: \n[111111111111-production 222222222222-sandbox]\n\nGOT providers: \n%+v\n\n", providers)
+	}
+
+	if providers[0].Skipped || !providers[1].Skipped {
+		t.Errorf("\nEXPECTED skipped: \n[false true]\n\nGOT skipped: \n[%t %t]\n\n", providers[0].Skipped, providers[1].Skipped)
+	}
+
+	if !reflect.DeepEqual([]string{"AKIA", "AKIA"}, assumed) {
+		t.Errorf("\nEXPECTED assumed credentials: \n[AKIA AKIA]\n\nGOT assumed credentials: \n%+v\n\n", assumed)
+	}
+
+	o.AssertExpectations(t)
+	s.AssertExpectations(t)
+}
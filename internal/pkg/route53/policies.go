@@ -0,0 +1,323 @@
+package r53
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// exportPolicies writes health checks and traffic policies of an account, returns references used to annotate records
+func exportPolicies(c Client, dir string, fs afero.Fs) (references, error) {
+	exports := make(map[string]interface{})
+
+	checks, err := getHealthChecks(c)
+	switch {
+	case denied(err, "", "health checks", "route53:ListHealthChecks"):
+	case err != nil:
+		return references{}, errors.Wrap(err, "error retrieving health checks")
+	default:
+		exports["health-checks"] = checks
+	}
+
+	policies, err := getTrafficPolicies(c)
+	switch {
+	case denied(err, "", "traffic policies", "route53:ListTrafficPolicies"):
+	case err != nil:
+		return references{}, errors.Wrap(err, "error retrieving traffic policies")
+	default:
+		exports["traffic-policies"] = policies
+	}
+
+	for name, content := range exports {
+		b, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return references{}, errors.Wrap(err, fmt.Sprintf("error composing %s", name))
+		}
+
+		_, err = utils.WriteToFileAs(name, "json", string(b)+"\n", dir, fs)
+		if err != nil {
+			return references{}, errors.Wrap(err, fmt.Sprintf("error exporting %s", name))
+		}
+	}
+
+	return referencesOf(checks, policies), nil
+}
+
+// referencesOf returns names of health checks and traffic policy instances records are annotated with
+func referencesOf(checks []HealthCheck, policies []TrafficPolicy) references {
+	r := references{
+		checks:    make(map[string]string),
+		instances: make(map[string]string),
+	}
+
+	for _, check := range checks {
+		r.checks[check.ID] = check.Name
+	}
+
+	for _, policy := range policies {
+		for _, instance := range policy.Instances {
+			r.instances[instanceKey(instance.HostedZoneID, instance.Name, instance.Type)] = fmt.Sprintf("%s (%s v%v)", instance.ID, policy.Name, instance.Version)
+		}
+	}
+
+	return r
+}
+
+// getHealthChecks returns health checks with their tags
+func getHealthChecks(c Client) ([]HealthCheck, error) {
+	checks := []HealthCheck{}
+
+	var marker *string
+	for {
+		o, err := c.ListHealthChecks(&route53.ListHealthChecksInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range o.HealthChecks {
+			checks = append(checks, HealthCheck{
+				ID:            aws.StringValue(h.Id),
+				Version:       aws.Int64Value(h.HealthCheckVersion),
+				Config:        h.HealthCheckConfig,
+				Alarm:         h.CloudWatchAlarmConfiguration,
+				LinkedService: h.LinkedService,
+			})
+		}
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+
+		marker = o.NextMarker
+	}
+
+	// tags are listed in batches of up to 10 resources
+	for i := 0; i < len(checks); i += 10 {
+		end := i + 10
+		if end > len(checks) {
+			end = len(checks)
+		}
+		batch := checks[i:end]
+
+		var ids []*string
+		for _, h := range batch {
+			ids = append(ids, aws.String(h.ID))
+		}
+
+		o, err := c.ListTagsForResources(&route53.ListTagsForResourcesInput{
+			ResourceType: aws.String(route53.TagResourceTypeHealthcheck),
+			ResourceIds:  ids,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving health check tags")
+		}
+
+		for _, set := range o.ResourceTagSets {
+			for j := range batch {
+				if batch[j].ID != aws.StringValue(set.ResourceId) || len(set.Tags) == 0 {
+					continue
+				}
+
+				batch[j].Tags = make(map[string]string)
+				for _, tag := range set.Tags {
+					batch[j].Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+			}
+		}
+	}
+
+	for i := range checks {
+		checks[i].Name = checks[i].name()
+	}
+
+	return checks, nil
+}
+
+// name returns a readable name of a health check: its 'Name' tag or a checked endpoint
+func (h *HealthCheck) name() string {
+	if n, ok := h.Tags["Name"]; ok && n != "" {
+		return n
+	}
+
+	if h.Config == nil {
+		return h.ID
+	}
+
+	cfg := h.Config
+	target := aws.StringValue(cfg.FullyQualifiedDomainName)
+	if target == "" {
+		target = aws.StringValue(cfg.IPAddress)
+	}
+
+	switch {
+	case target != "":
+		if cfg.Port != nil {
+			target = fmt.Sprintf("%s:%v", target, *cfg.Port)
+		}
+		return fmt.Sprintf("%s %s%s", aws.StringValue(cfg.Type), target, aws.StringValue(cfg.ResourcePath))
+	case cfg.AlarmIdentifier != nil:
+		return fmt.Sprintf("%s %s", aws.StringValue(cfg.Type), aws.StringValue(cfg.AlarmIdentifier.Name))
+	}
+
+	return fmt.Sprintf("%s %s", aws.StringValue(cfg.Type), h.ID)
+}
+
+// getTrafficPolicies returns traffic policies with all their versions and instances
+func getTrafficPolicies(c Client) ([]TrafficPolicy, error) {
+	policies := []TrafficPolicy{}
+
+	var marker *string
+	for {
+		o, err := c.ListTrafficPolicies(&route53.ListTrafficPoliciesInput{
+			TrafficPolicyIdMarker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range o.TrafficPolicySummaries {
+			policies = append(policies, TrafficPolicy{
+				ID:            aws.StringValue(p.Id),
+				Name:          aws.StringValue(p.Name),
+				Type:          aws.StringValue(p.Type),
+				LatestVersion: aws.Int64Value(p.LatestVersion),
+			})
+		}
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+
+		marker = o.TrafficPolicyIdMarker
+	}
+
+	index := make(map[string]int)
+	for i := range policies {
+		index[policies[i].ID] = i
+
+		versions, err := getTrafficPolicyVersions(policies[i].ID, c)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error retrieving traffic policy '%s' versions", policies[i].ID))
+		}
+
+		policies[i].Versions = versions
+	}
+
+	var zoneID, name, t *string
+	for {
+		o, err := c.ListTrafficPolicyInstances(&route53.ListTrafficPolicyInstancesInput{
+			HostedZoneIdMarker:              zoneID,
+			TrafficPolicyInstanceNameMarker: name,
+			TrafficPolicyInstanceTypeMarker: t,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieving traffic policy instances")
+		}
+
+		for _, i := range o.TrafficPolicyInstances {
+			p, ok := index[aws.StringValue(i.TrafficPolicyId)]
+			if !ok {
+				continue
+			}
+
+			policies[p].Instances = append(policies[p].Instances, TrafficPolicyInstance{
+				ID:           aws.StringValue(i.Id),
+				Name:         aws.StringValue(i.Name),
+				Type:         aws.StringValue(i.TrafficPolicyType),
+				HostedZoneID: aws.StringValue(i.HostedZoneId),
+				TTL:          aws.Int64Value(i.TTL),
+				Version:      aws.Int64Value(i.TrafficPolicyVersion),
+				State:        aws.StringValue(i.State),
+			})
+		}
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+
+		zoneID, name, t = o.HostedZoneIdMarker, o.TrafficPolicyInstanceNameMarker, o.TrafficPolicyInstanceTypeMarker
+	}
+
+	return policies, nil
+}
+
+// getTrafficPolicyVersions returns all versions of a traffic policy
+func getTrafficPolicyVersions(id string, c Client) ([]TrafficPolicyVersion, error) {
+	var versions []TrafficPolicyVersion
+
+	var marker *string
+	for {
+		o, err := c.ListTrafficPolicyVersions(&route53.ListTrafficPolicyVersionsInput{
+			Id:                         aws.String(id),
+			TrafficPolicyVersionMarker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range o.TrafficPolicies {
+			version := TrafficPolicyVersion{
+				Version: aws.Int64Value(v.Version),
+				Comment: aws.StringValue(v.Comment),
+			}
+
+			// documents are JSON, kept as is otherwise
+			document := aws.StringValue(v.Document)
+			if json.Valid([]byte(document)) {
+				version.Document = json.RawMessage(document)
+			} else {
+				b, _ := json.Marshal(document)
+				version.Document = b
+			}
+
+			versions = append(versions, version)
+		}
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+
+		marker = o.TrafficPolicyVersionMarker
+	}
+
+	return versions, nil
+}
+
+// instanceKey identifies record sets created by a traffic policy instance
+func instanceKey(zoneID, name, t string) string {
+	return fmt.Sprintf("%s|%s|%s", strings.TrimPrefix(zoneID, "/hostedzone/"), strings.ToLower(strings.TrimSuffix(name, ".")), t)
+}
+
+// reference annotates routing of records with readable health check names and traffic policy instances
+func (r *Records) reference(refs references, zoneID string) {
+	for _, b := range r.buckets() {
+		for i := range *b {
+			record := &(*b)[i]
+
+			if instance, ok := refs.instances[instanceKey(zoneID, record.Name, record.Type)]; ok {
+				if record.Routing == nil {
+					record.Routing = &zone.RoutingPolicy{}
+				}
+				record.Routing.TrafficPolicyInstance = instance
+			}
+
+			if record.Routing == nil || record.Routing.HealthCheckID == "" {
+				continue
+			}
+
+			if name, ok := refs.checks[record.Routing.HealthCheckID]; ok {
+				record.Routing.HealthCheckName = name
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package r53_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestExportPolicies(t *testing.T) {
+	c := mocks.Route53{}
+	fs := afero.NewMemMapFs()
+
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{
+		IsTruncated: aws.Bool(true),
+		NextMarker:  aws.String("next"),
+		HealthChecks: []*route53.HealthCheck{
+			{
+				Id:                 aws.String("abcdef11-2222-3333-4444-555555fedcba"),
+				HealthCheckVersion: aws.Int64(1),
+				HealthCheckConfig: &route53.HealthCheckConfig{
+					Type:                     aws.String("HTTPS"),
+					FullyQualifiedDomainName: aws.String("api.domain.com"),
+					Port:                     aws.Int64(443),
+					ResourcePath:             aws.String("/health"),
+				},
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{
+		IsTruncated: aws.Bool(false),
+		HealthChecks: []*route53.HealthCheck{
+			{
+				Id:                 aws.String("11111111-2222-3333-4444-555555555555"),
+				HealthCheckVersion: aws.Int64(3),
+				HealthCheckConfig: &route53.HealthCheckConfig{
+					Type:      aws.String("TCP"),
+					IPAddress: aws.String("192.168.1.51"),
+					Port:      aws.Int64(22),
+				},
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListTagsForResources").Return(&route53.ListTagsForResourcesOutput{
+		ResourceTagSets: []*route53.ResourceTagSet{
+			{
+				ResourceId: aws.String("11111111-2222-3333-4444-555555555555"),
+				Tags: []*route53.Tag{
+					{
+						Key:   aws.String("Name"),
+						Value: aws.String("bastion"),
+					},
+				},
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{
+		IsTruncated: aws.Bool(false),
+		TrafficPolicySummaries: []*route53.TrafficPolicySummary{
+			{
+				Id:            aws.String("12345678-abcd-abcd-abcd-123456789012"),
+				Name:          aws.String("geo-web"),
+				Type:          aws.String("A"),
+				LatestVersion: aws.Int64(2),
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListTrafficPolicyVersions").Return(&route53.ListTrafficPolicyVersionsOutput{
+		IsTruncated: aws.Bool(false),
+		TrafficPolicies: []*route53.TrafficPolicy{
+			{
+				Version:  aws.Int64(1),
+				Document: aws.String(`{"AWSPolicyFormatVersion":"2015-10-01","RecordType":"A"}`),
+			},
+			{
+				Version:  aws.Int64(2),
+				Comment:  aws.String("failover to eu"),
+				Document: aws.String(`{"AWSPolicyFormatVersion":"2015-10-01","RecordType":"A","StartRule":"eu"}`),
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{
+		IsTruncated: aws.Bool(false),
+		TrafficPolicyInstances: []*route53.TrafficPolicyInstance{
+			{
+				Id:                   aws.String("instance-1"),
+				HostedZoneId:         aws.String("A1M9OJ3HY2SUQY"),
+				Name:                 aws.String("www.domain.com."),
+				TTL:                  aws.Int64(60),
+				State:                aws.String("Applied"),
+				TrafficPolicyId:      aws.String("12345678-abcd-abcd-abcd-123456789012"),
+				TrafficPolicyVersion: aws.Int64(2),
+				TrafficPolicyType:    aws.String("A"),
+			},
+		},
+	}, nil).Once()
+
+	refs, err := r53.ExportPolicies(&c, "./Route53", fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// health checks
+	content, err := afero.ReadFile(fs, "./Route53/health-checks.json")
+	if err != nil {
+		t.Fatal("error reading exported health checks:", err)
+	}
+
+	var checks []r53.HealthCheck
+	err = json.Unmarshal(content, &checks)
+	if err != nil {
+		t.Fatal("error parsing exported health checks:", err)
+	}
+
+	names := []string{"HTTPS api.domain.com:443/health", "bastion"}
+	if len(checks) != len(names) {
+		t.Fatalf("\nEXPECTED health checks: \n%v\n\nGOT health checks: \n%+v\n\n", names, checks)
+	}
+
+	for i, check := range checks {
+		if check.Name != names[i] {
+			t.Errorf("\nEXPECTED health check name: \n%s\n\nGOT health check name: \n%s\n\n", names[i], check.Name)
+		}
+	}
+
+	// traffic policies
+	content, err = afero.ReadFile(fs, "./Route53/traffic-policies.json")
+	if err != nil {
+		t.Fatal("error reading exported traffic policies:", err)
+	}
+
+	var policies []r53.TrafficPolicy
+	err = json.Unmarshal(content, &policies)
+	if err != nil {
+		t.Fatal("error parsing exported traffic policies:", err)
+	}
+
+	if len(policies) != 1 || len(policies[0].Versions) != 2 || len(policies[0].Instances) != 1 {
+		t.Fatalf("\nEXPECTED traffic policies: \n1 policy, 2 versions, 1 instance\n\nGOT traffic policies: \n%+v\n\n", policies)
+	}
+
+	var document map[string]string
+	err = json.Unmarshal(policies[0].Versions[1].Document, &document)
+	if err != nil || document["StartRule"] != "eu" {
+		t.Errorf("\nEXPECTED traffic policy document to be exported as JSON\n\nGOT document: \n%s\n\n", policies[0].Versions[1].Document)
+	}
+
+	// references
+	records := r53.Records{
+		A: []zone.Record{
+			{
+				Name:  "api.domain.com.",
+				Type:  "A",
+				TTL:   60,
+				Value: []string{"192.168.1.51"},
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier: "primary",
+					Failover:      "PRIMARY",
+					HealthCheckID: "abcdef11-2222-3333-4444-555555fedcba",
+				},
+			},
+			{
+				Name:  "www.domain.com.",
+				Type:  "A",
+				TTL:   60,
+				Value: []string{"192.168.1.52"},
+			},
+		},
+	}
+
+	records.Reference(refs, "/hostedzone/A1M9OJ3HY2SUQY")
+
+	expected := []*zone.RoutingPolicy{
+		{
+			SetIdentifier:   "primary",
+			Failover:        "PRIMARY",
+			HealthCheckID:   "abcdef11-2222-3333-4444-555555fedcba",
+			HealthCheckName: "HTTPS api.domain.com:443/health",
+		},
+		{
+			TrafficPolicyInstance: "instance-1 (geo-web v2)",
+		},
+	}
+
+	for i, record := range records.A {
+		if !reflect.DeepEqual(expected[i], record.Routing) {
+			t.Errorf("\nEXPECTED routing: \n%+v\n\nGOT routing: \n%+v\n\n", expected[i], record.Routing)
+		}
+	}
+}
+
+func TestExportPoliciesDenied(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Route53{}
+	fs := afero.NewMemMapFs()
+
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{}, awserr.New("AccessDenied", "User is not authorized to perform this action", nil)).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{
+		IsTruncated:            aws.Bool(false),
+		TrafficPolicySummaries: []*route53.TrafficPolicySummary{},
+	}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	_, err := r53.ExportPolicies(&c, "./Route53", fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+
+	// exports the policy permits reading are written only
+	for path, expected := range map[string]bool{
+		"./Route53/health-checks.json":    false,
+		"./Route53/traffic-policies.json": true,
+	} {
+		ok, err := afero.Exists(fs, path)
+		if err != nil || ok != expected {
+			t.Errorf("\nEXPECTED path '%s' exists: \n%t\n\nGOT exists: \n%t\n\n", path, expected, ok)
+		}
+	}
+
+	// other errors fail the export
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{}, awserr.New("Throttling", "Rate exceeded", nil)).Once()
+
+	_, err = r53.ExportPolicies(&c, "./Route53", fs)
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nThrottling: Rate exceeded\n\nGOT error: \n<nil>")
+	}
+}
@@ -0,0 +1,109 @@
+package r53
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Provider binds Route53 hosted zones to an authenticated client
+type Provider struct {
+	Client    Client
+	Zones     Zones
+	Account   string
+	Resolvers map[string]Resolver
+	// Skipped organization account is neither fetched nor exported, its export directory is marked instead
+	Skipped bool
+}
+
+// skippedMarker is a file that marks an export directory of a skipped account, which zones are not up-to-date
+const skippedMarker = "SKIPPED"
+
+// NewProvider returns new Route53 provider
+func NewProvider(c Client) *Provider {
+	return &Provider{
+		Client: c,
+		Zones: Zones{
+			Public:  make(map[string]string),
+			Private: make(map[string]string),
+		},
+	}
+}
+
+// Name of a provider
+func (p *Provider) Name() string {
+	return "Route53"
+}
+
+// Fetch hosted zones
+func (p *Provider) Fetch(errs chan error, wg *sync.WaitGroup) {
+	if p.Skipped {
+		wg.Done()
+		errs <- nil
+		return
+	}
+
+	p.Zones.Fetch(p.Client, errs, wg)
+}
+
+// Export hosted zones and Resolver resources of configured regions
+func (p *Provider) Export(delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+	if p.Skipped {
+		defer wg.Done()
+		errs <- mark(dir(root, p.Account), fs)
+		return
+	}
+
+	// an account that is no longer skipped is up-to-date again
+	err := fs.Remove(fmt.Sprintf("%s/%s", dir(root, p.Account), skippedMarker))
+	if err != nil && !os.IsNotExist(err) {
+		wg.Done()
+		errs <- errors.Wrap(err, "Route53: error removing skipped account marker")
+		return
+	}
+
+	if len(p.Resolvers) == 0 {
+		p.Zones.Export(p.Client, delay, mode, errs, wg, root, p.Account, fs)
+		return
+	}
+
+	defer wg.Done()
+
+	zerrs := make(chan error, 1)
+
+	var zwg sync.WaitGroup
+	zwg.Add(1)
+
+	p.Zones.Export(p.Client, delay, mode, zerrs, &zwg, root, p.Account, fs)
+
+	err = <-zerrs
+	if err != nil {
+		errs <- err
+		return
+	}
+
+	errs <- exportResolvers(p.Resolvers, dir(root, p.Account), fs)
+}
+
+// mark an export directory of a skipped account, zones exported into it before are kept as they are
+func mark(dir string, fs afero.Fs) error {
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		return errors.Wrap(err, "Route53: error marking skipped account")
+	}
+
+	content := "This organization account is listed in 'ROUTE53_ORGANIZATION_SKIP', zones exported into this directory are not up-to-date.\n"
+
+	err = afero.WriteFile(fs, fmt.Sprintf("%s/%s", dir, skippedMarker), []byte(content), 0644)
+	if err != nil {
+		return errors.Wrap(err, "Route53: error marking skipped account")
+	}
+
+	return nil
+}
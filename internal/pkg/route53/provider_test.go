@@ -0,0 +1,172 @@
+package r53_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestProviderFetch(t *testing.T) {
+	c := mocks.Route53{}
+
+	p := r53.NewProvider(&c)
+
+	if p.Name() != "Route53" {
+		t.Errorf("\nEXPECTED name: \nRoute53\n\nGOT name: \n%s\n\n", p.Name())
+	}
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	reply := &route53.ListHostedZonesOutput{
+		IsTruncated: aws.Bool(false),
+		HostedZones: []*route53.HostedZone{
+			{
+				Config: &route53.HostedZoneConfig{
+					PrivateZone: aws.Bool(true),
+				},
+				Id:   aws.String("/hostedzone/A1M9OJ3HY2SUQY"),
+				Name: aws.String("domain.local"),
+			},
+		},
+	}
+
+	c.On("ListHostedZones").Return(reply, nil).Once()
+
+	p.Fetch(errs, &wg)
+
+	expected := r53.Zones{
+		Public: map[string]string{},
+		Private: map[string]string{
+			"domain.local": "/hostedzone/A1M9OJ3HY2SUQY",
+		},
+	}
+
+	if !reflect.DeepEqual(p.Zones, expected) {
+		t.Errorf("\nEXPECTED provider zones: \n%+v\n\nGOT provider zones: \n%+v\n\n", expected, p.Zones)
+	}
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+}
+
+func TestProviderExportAccount(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Route53{}
+
+	p := r53.NewProvider(&c)
+	p.Account = "production"
+	p.Zones.Public["domain.com."] = "1"
+
+	fs := afero.NewMemMapFs()
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	reply := &route53.ListResourceRecordSetsOutput{
+		IsTruncated: aws.Bool(false),
+		ResourceRecordSets: []*route53.ResourceRecordSet{
+			{
+				Name: aws.String("domain.com."),
+				Type: aws.String("A"),
+				ResourceRecords: []*route53.ResourceRecord{
+					{
+						Value: aws.String("192.168.1.51"),
+					},
+				},
+				TTL: aws.Int64(300),
+			},
+		},
+	}
+
+	c.On("ListResourceRecordSets").Return(reply, nil).Once()
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{HostedZone: &route53.HostedZone{Id: aws.String("1"), Name: aws.String("domain.com.")}}, nil).Once()
+	c.On("ListTagsForResource").Return(&route53.ListTagsForResourceOutput{}, nil).Once()
+	c.On("GetDNSSEC").Return(&route53.GetDNSSECOutput{}, nil).Once()
+	c.On("ListQueryLoggingConfigs").Return(&route53.ListQueryLoggingConfigsOutput{}, nil).Once()
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+
+	p.Export(0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for _, path := range []string{"./Route53/production/Public/domain-com.txt", "./Route53/production/Public/domain-com.meta.json", "./Route53/production/Private", "./Route53/production/health-checks.json", "./Route53/production/traffic-policies.json"} {
+		ok, err := afero.Exists(fs, path)
+		if err != nil || !ok {
+			t.Errorf("\nEXPECTED path: \n%s\n\nGOT error: \n%v\n\n", path, err)
+		}
+	}
+}
+
+func TestProviderExportSkipped(t *testing.T) {
+	c := mocks.Route53{}
+
+	p := r53.NewProvider(&c)
+	p.Account = "222222222222-sandbox"
+	p.Skipped = true
+
+	fs := afero.NewMemMapFs()
+
+	errs := make(chan error, 2)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	p.Fetch(errs, &wg)
+	p.Export(0, zone.Annotated, errs, &wg, ".", fs)
+
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
+	}
+
+	ok, err := afero.Exists(fs, "./Route53/222222222222-sandbox/SKIPPED")
+	if err != nil || !ok {
+		t.Fatalf("\nEXPECTED path: \n./Route53/222222222222-sandbox/SKIPPED\n\nGOT error: \n%v\n\n", err)
+	}
+
+	// an account that is no longer skipped removes its marker
+	p.Skipped = false
+
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+
+	wg.Add(1)
+	p.Export(0, zone.Annotated, errs, &wg, ".", fs)
+
+	err = <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	ok, _ = afero.Exists(fs, "./Route53/222222222222-sandbox/SKIPPED")
+	if ok {
+		t.Error("\nEXPECTED path: \n<removed>\n\nGOT path: \n./Route53/222222222222-sandbox/SKIPPED")
+	}
+
+	c.AssertExpectations(t)
+}
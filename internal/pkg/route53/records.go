@@ -2,10 +2,12 @@ package r53
 
 import (
 	"bytes"
-	"fmt"
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
 
 	"github.com/aws/aws-sdk-go/service/route53"
-	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 // Append Record Sets to the Zone
@@ -13,7 +15,9 @@ func (r *Records) Append(set *route53.ListResourceRecordSetsOutput) error {
 	s := set
 	for _, record := range s.ResourceRecordSets {
 		var val []string
-		var rec Record
+		rec := zone.Record{
+			Type: *record.Type,
+		}
 		if record.AliasTarget == nil {
 			for _, v := range record.ResourceRecords {
 				val = append(val, *v.Value)
@@ -26,8 +30,11 @@ func (r *Records) Append(set *route53.ListResourceRecordSetsOutput) error {
 			rec.Name = *record.Name
 			rec.Value = []string{*record.AliasTarget.DNSName}
 			rec.Alias = true
+			rec.Target = target(record.AliasTarget)
 		}
 
+		rec.Routing = routing(record)
+
 		// add record
 		switch *record.Type {
 		case "SOA":
@@ -54,170 +61,105 @@ func (r *Records) Append(set *route53.ListResourceRecordSetsOutput) error {
 			r.NAPTR = append(r.NAPTR, rec)
 		case "CAA":
 			r.CAA = append(r.CAA, rec)
+		case "DS":
+			r.DS = append(r.DS, rec)
+		case "HTTPS":
+			r.HTTPS = append(r.HTTPS, rec)
+		case "SVCB":
+			r.SVCB = append(r.SVCB, rec)
+		case "TLSA":
+			r.TLSA = append(r.TLSA, rec)
+		case "SSHFP":
+			r.SSHFP = append(r.SSHFP, rec)
 		default:
-			return fmt.Errorf("not supported record type: %s", *record.Type)
+			log.WithFields(log.Fields{
+				"provider": "Route53",
+				"record":   strings.TrimSuffix(*record.Name, "."),
+				"type":     *record.Type,
+			}).Warn("unknown record type, exporting as is")
+
+			r.Other = append(r.Other, rec)
 		}
 	}
 
 	return nil
 }
 
-// ConvertToZonefile returns formatted zonefile
-func (r *Records) ConvertToZonefile() (bytes.Buffer, error) {
-	b := bytes.Buffer{}
-	p := bytes.Buffer{}
-
-	var aliasHeaderMarker bool
-
-	// SOA
-	_, err := b.WriteString(fmt.Sprintf(";; SOA Record\n%s\t%v\tIN\tSOA\t%s\n\n", r.SOA.Name, r.SOA.TTL, r.SOA.Value[0]))
-	if err != nil {
-		return bytes.Buffer{}, errors.Wrap(err, "error formatting records of type 'SOA'")
-	}
-
-	// NS
-	if len(r.NS) > 0 {
-		t := "NS"
-		err := format(&aliasHeaderMarker, &r.NS, &b, &p, t)
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+// routing returns a routing policy of a record set, nil for a simple routing
+func routing(record *route53.ResourceRecordSet) *zone.RoutingPolicy {
+	if record.SetIdentifier == nil && record.HealthCheckId == nil {
+		return nil
 	}
 
-	// MX
-	if len(r.MX) > 0 {
-		t := "MX"
-		err := format(&aliasHeaderMarker, &r.MX, &b, &p, t)
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	p := zone.RoutingPolicy{
+		Weight: record.Weight,
 	}
 
-	// A
-	if len(r.A) > 0 {
-		t := "A"
-		err := format(&aliasHeaderMarker, &r.A, &b, &p, t)
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	if record.SetIdentifier != nil {
+		p.SetIdentifier = *record.SetIdentifier
 	}
 
-	// AAAA
-	if len(r.AAAA) > 0 {
-		t := "AAAA"
-		err := format(&aliasHeaderMarker, &r.AAAA, &b, &p, t)
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	if record.Region != nil {
+		p.Region = *record.Region
 	}
 
-	// CNAME
-	if len(r.CNAME) > 0 {
-		t := "CNAME"
-		err := format(&aliasHeaderMarker, &r.CNAME, &b, &p, "CNAME")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
-	}
+	if record.GeoLocation != nil {
+		p.GeoLocation = &zone.GeoLocation{}
 
-	// TXT
-	if len(r.TXT) > 0 {
-		t := "TXT"
-		err := format(&aliasHeaderMarker, &r.TXT, &b, &p, "TXT")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
+		if record.GeoLocation.ContinentCode != nil {
+			p.GeoLocation.ContinentCode = *record.GeoLocation.ContinentCode
 		}
-	}
 
-	// SRV
-	if len(r.SRV) > 0 {
-		t := "SRV"
-		err := format(&aliasHeaderMarker, &r.SRV, &b, &p, "SRV")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
+		if record.GeoLocation.CountryCode != nil {
+			p.GeoLocation.CountryCode = *record.GeoLocation.CountryCode
 		}
-	}
 
-	// PTR
-	if len(r.PTR) > 0 {
-		t := "PTR"
-		err := format(&aliasHeaderMarker, &r.PTR, &b, &p, "PTR")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
+		if record.GeoLocation.SubdivisionCode != nil {
+			p.GeoLocation.SubdivisionCode = *record.GeoLocation.SubdivisionCode
 		}
 	}
 
-	// SPF
-	if len(r.SPF) > 0 {
-		t := "SPF"
-		err := format(&aliasHeaderMarker, &r.SPF, &b, &p, "SPF")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	if record.Failover != nil {
+		p.Failover = *record.Failover
 	}
 
-	// NAPTR
-	if len(r.NAPTR) > 0 {
-		t := "NAPTR"
-		err := format(&aliasHeaderMarker, &r.NAPTR, &b, &p, "NAPTR")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	if record.MultiValueAnswer != nil {
+		p.MultiValueAnswer = *record.MultiValueAnswer
 	}
 
-	// CAA
-	if len(r.CAA) > 0 {
-		t := "CAA"
-		err := format(&aliasHeaderMarker, &r.CAA, &b, &p, "CAA")
-		if err != nil {
-			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
-		}
+	if record.HealthCheckId != nil {
+		p.HealthCheckID = *record.HealthCheckId
 	}
 
-	_, err = b.WriteString(p.String())
-	if err != nil {
-		return bytes.Buffer{}, errors.Wrap(err, "error appending Route53 Aliases")
-	}
+	return &p
+}
 
-	return b, nil
+// buckets returns record lists of all types except SOA in a zonefile order
+func (r *Records) buckets() []*[]zone.Record {
+	return []*[]zone.Record{&r.NS, &r.MX, &r.A, &r.AAAA, &r.CNAME, &r.TXT, &r.SRV, &r.PTR, &r.SPF, &r.NAPTR, &r.CAA, &r.DS, &r.HTTPS, &r.SVCB, &r.TLSA, &r.SSHFP, &r.Other}
 }
 
-// format zonefile content
-func format(marker *bool, r *[]Record, b, p *bytes.Buffer, t string) error {
-	_, err := b.WriteString(fmt.Sprintf(";; %s Records\n", t))
-	if err != nil {
-		return errors.New("error formatting zonefile")
-	}
+// List returns all records in a zonefile order
+func (r *Records) List() []zone.Record {
+	var l []zone.Record
 
-	for _, record := range *r {
-		if !record.Alias {
-			for _, rec := range record.Value {
-				_, err := b.WriteString(fmt.Sprintf("%s\t%v\tIN\t%s\t%s\n", record.Name, record.TTL, t, rec))
-				if err != nil {
-					return fmt.Errorf("error formatting records of type: %s", t)
-				}
-			}
-		} else {
-			if !*marker {
-				_, err := p.WriteString(";; Route53 Alias Records\n")
-				if err != nil {
-					return errors.New("error adding alias title")
-				}
-				*marker = true
-			}
+	if len(r.SOA.Value) > 0 {
+		l = append(l, r.SOA)
+	}
 
-			for _, rec := range record.Value {
-				_, err := p.WriteString(fmt.Sprintf("%s\t%v\tIN\t%s\t%s\n", record.Name, record.TTL, t, rec))
-				if err != nil {
-					return fmt.Errorf("error formatting records of type: %s", t)
-				}
-			}
-		}
+	for _, t := range r.buckets() {
+		l = append(l, *t...)
 	}
 
-	_, err = b.WriteString("\n")
-	if err != nil {
-		return fmt.Errorf("error formatting records of type: %s", t)
+	return l
+}
+
+// ConvertToZonefile returns formatted zonefile
+func (r *Records) ConvertToZonefile() (bytes.Buffer, error) {
+	z := zone.Zone{
+		Provider: "Route53",
+		Records:  r.List(),
 	}
-	return nil
+
+	return z.ConvertToZonefile()
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
@@ -30,8 +31,9 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			SOA: r53.Record{
+			SOA: zone.Record{
 				Name:  "domain.com.",
+				Type:  "SOA",
 				Value: []string{"ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"},
 				Alias: false,
 				TTL:   900,
@@ -65,15 +67,17 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			NS: []r53.Record{
-				r53.Record{
+			NS: []zone.Record{
+				zone.Record{
 					Name:  "domain.com.",
+					Type:  "NS",
 					Value: []string{"ns-1800.awsdns-33.co.uk."},
 					Alias: false,
 					TTL:   172800,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "domain.com.",
+					Type:  "NS",
 					Value: []string{"ns3.amazon.net"},
 					Alias: false,
 					TTL:   300,
@@ -108,15 +112,17 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			MX: []r53.Record{
-				r53.Record{
+			MX: []zone.Record{
+				zone.Record{
 					Name:  "mail1.domain.com.",
+					Type:  "MX",
 					Value: []string{"10 mailserver1.google.com"},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "mail2.domain.com.",
+					Type:  "MX",
 					Value: []string{"20 mailserver2.google.com"},
 					Alias: false,
 					TTL:   300,
@@ -134,7 +140,9 @@ func TestAppend(t *testing.T) {
 					Name: aws.String("a-alias.domain.com."),
 					Type: aws.String("A"),
 					AliasTarget: &route53.AliasTarget{
-						DNSName: aws.String("a0123456789abcdef.awsglobalaccelerator.com."),
+						DNSName:              aws.String("a0123456789abcdef.awsglobalaccelerator.com."),
+						HostedZoneId:         aws.String("Z2BJ6XQ5FK7U4H"),
+						EvaluateTargetHealth: aws.Bool(false),
 					},
 					TTL: aws.Int64(0),
 				},
@@ -173,27 +181,35 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			A: []r53.Record{
-				r53.Record{
+			A: []zone.Record{
+				zone.Record{
 					Name:  "a-alias.domain.com.",
+					Type:  "A",
 					Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
 					Alias: true,
-					TTL:   0,
+					Target: &zone.AliasTarget{
+						HostedZoneID: "Z2BJ6XQ5FK7U4H",
+						Service:      "Global Accelerator",
+					},
+					TTL: 0,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "a-multiple.domain.com.",
+					Type:  "A",
 					Value: []string{"192.168.1.51"},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "a-multiple.domain.com.",
+					Type:  "A",
 					Value: []string{"192.168.1.52"},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "low-ttl.domain.com.",
+					Type:  "A",
 					Value: []string{"192.168.1.53"},
 					Alias: false,
 					TTL:   60,
@@ -218,9 +234,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			AAAA: []r53.Record{
-				r53.Record{
+			AAAA: []zone.Record{
+				zone.Record{
 					Name:  "ipv6.domain.com.",
+					Type:  "AAAA",
 					Value: []string{"fe80:0:0:0:202:b3ff:fe1e:8329"},
 					Alias: false,
 					TTL:   300,
@@ -248,24 +265,32 @@ func TestAppend(t *testing.T) {
 					Name: aws.String("www.domain.com."),
 					Type: aws.String("CNAME"),
 					AliasTarget: &route53.AliasTarget{
-						DNSName: aws.String("c0123456789abcdef.awsglobalaccelerator.com."),
+						DNSName:              aws.String("c0123456789abcdef.awsglobalaccelerator.com."),
+						HostedZoneId:         aws.String("Z2BJ6XQ5FK7U4H"),
+						EvaluateTargetHealth: aws.Bool(false),
 					},
 					TTL: aws.Int64(0),
 				},
 			},
 		}: &r53.Records{
-			CNAME: []r53.Record{
-				r53.Record{
+			CNAME: []zone.Record{
+				zone.Record{
 					Name:  "website.domain.com.",
+					Type:  "CNAME",
 					Value: []string{"www.domain.com"},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "www.domain.com.",
+					Type:  "CNAME",
 					Value: []string{"c0123456789abcdef.awsglobalaccelerator.com."},
 					Alias: true,
-					TTL:   0,
+					Target: &zone.AliasTarget{
+						HostedZoneID: "Z2BJ6XQ5FK7U4H",
+						Service:      "Global Accelerator",
+					},
+					TTL: 0,
 				},
 			},
 		},
@@ -287,9 +312,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			TXT: []r53.Record{
-				r53.Record{
+			TXT: []zone.Record{
+				zone.Record{
 					Name:  "txt.domain.com.",
+					Type:  "TXT",
 					Value: []string{"some string here"},
 					Alias: false,
 					TTL:   300,
@@ -314,9 +340,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			SRV: []r53.Record{
-				r53.Record{
+			SRV: []zone.Record{
+				zone.Record{
 					Name:  "dc.domain.com.",
+					Type:  "SRV",
 					Value: []string{"1 10 5269 controller.domain.com."},
 					Alias: false,
 					TTL:   300,
@@ -341,9 +368,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			PTR: []r53.Record{
-				r53.Record{
+			PTR: []zone.Record{
+				zone.Record{
 					Name:  "pointer.domain.com.",
+					Type:  "PTR",
 					Value: []string{"www.domain.com"},
 					Alias: false,
 					TTL:   300,
@@ -368,9 +396,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			SPF: []r53.Record{
-				r53.Record{
+			SPF: []zone.Record{
+				zone.Record{
 					Name:  "policy.domain.com.",
+					Type:  "SPF",
 					Value: []string{"v=spf1 ip4:192.168.0.0/16-all"},
 					Alias: false,
 					TTL:   300,
@@ -395,9 +424,10 @@ func TestAppend(t *testing.T) {
 				},
 			},
 		}: &r53.Records{
-			NAPTR: []r53.Record{
-				r53.Record{
+			NAPTR: []zone.Record{
+				zone.Record{
 					Name:  "name-auth.domain.com.",
+					Type:  "NAPTR",
 					Value: []string{"100 100 \"U\" \"\" \"!^.*$!sip:info@bar.example.com!\" ."},
 					Alias: false,
 					TTL:   300,
@@ -435,30 +465,347 @@ func TestAppend(t *testing.T) {
 					Name: aws.String("ca3.domain.com."),
 					Type: aws.String("CAA"),
 					AliasTarget: &route53.AliasTarget{
-						DNSName: aws.String("b0123456789abcdef.awsglobalaccelerator.com."),
+						DNSName:              aws.String("b0123456789abcdef.awsglobalaccelerator.com."),
+						HostedZoneId:         aws.String("Z2BJ6XQ5FK7U4H"),
+						EvaluateTargetHealth: aws.Bool(false),
 					},
 					TTL: aws.Int64(0),
 				},
 			},
 		}: &r53.Records{
-			CAA: []r53.Record{
-				r53.Record{
+			CAA: []zone.Record{
+				zone.Record{
 					Name:  "ca1.domain.com.",
+					Type:  "CAA",
 					Value: []string{"0 issue \"caa.example.com\""},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "ca2.domain.com.",
+					Type:  "CAA",
 					Value: []string{"issuewild \";\""},
 					Alias: false,
 					TTL:   300,
 				},
-				r53.Record{
+				zone.Record{
 					Name:  "ca3.domain.com.",
+					Type:  "CAA",
 					Value: []string{"b0123456789abcdef.awsglobalaccelerator.com."},
 					Alias: true,
-					TTL:   0,
+					Target: &zone.AliasTarget{
+						HostedZoneID: "Z2BJ6XQ5FK7U4H",
+						Service:      "Global Accelerator",
+					},
+					TTL: 0,
+				},
+			},
+		},
+	})
+
+	// Routing Policies
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name:          aws.String("weighted.domain.com."),
+					Type:          aws.String("A"),
+					SetIdentifier: aws.String("blue"),
+					Weight:        aws.Int64(10),
+					HealthCheckId: aws.String("abcdef11-2222-3333-4444-555555fedcba"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("192.168.1.51"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+				&route53.ResourceRecordSet{
+					Name:          aws.String("weighted.domain.com."),
+					Type:          aws.String("A"),
+					SetIdentifier: aws.String("green"),
+					Weight:        aws.Int64(0),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("192.168.1.52"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+				&route53.ResourceRecordSet{
+					Name:          aws.String("geo.domain.com."),
+					Type:          aws.String("CNAME"),
+					SetIdentifier: aws.String("us-ca"),
+					GeoLocation: &route53.GeoLocation{
+						CountryCode:     aws.String("US"),
+						SubdivisionCode: aws.String("CA"),
+					},
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("us.domain.com"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+				&route53.ResourceRecordSet{
+					Name:          aws.String("latency.domain.com."),
+					Type:          aws.String("AAAA"),
+					SetIdentifier: aws.String("eu"),
+					Region:        aws.String("eu-west-1"),
+					Failover:      aws.String("PRIMARY"),
+					AliasTarget: &route53.AliasTarget{
+						DNSName:              aws.String("a0123456789abcdef.awsglobalaccelerator.com."),
+						HostedZoneId:         aws.String("Z2BJ6XQ5FK7U4H"),
+						EvaluateTargetHealth: aws.Bool(false),
+					},
+				},
+				&route53.ResourceRecordSet{
+					Name:             aws.String("multi.domain.com."),
+					Type:             aws.String("TXT"),
+					SetIdentifier:    aws.String("one"),
+					MultiValueAnswer: aws.Bool(true),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("\"one\""),
+						},
+					},
+					TTL: aws.Int64(60),
+				},
+			},
+		}: &r53.Records{
+			A: []zone.Record{
+				zone.Record{
+					Name:  "weighted.domain.com.",
+					Type:  "A",
+					Value: []string{"192.168.1.51"},
+					TTL:   300,
+					Routing: &zone.RoutingPolicy{
+						SetIdentifier: "blue",
+						Weight:        aws.Int64(10),
+						HealthCheckID: "abcdef11-2222-3333-4444-555555fedcba",
+					},
+				},
+				zone.Record{
+					Name:  "weighted.domain.com.",
+					Type:  "A",
+					Value: []string{"192.168.1.52"},
+					TTL:   300,
+					Routing: &zone.RoutingPolicy{
+						SetIdentifier: "green",
+						Weight:        aws.Int64(0),
+					},
+				},
+			},
+			CNAME: []zone.Record{
+				zone.Record{
+					Name:  "geo.domain.com.",
+					Type:  "CNAME",
+					Value: []string{"us.domain.com"},
+					TTL:   300,
+					Routing: &zone.RoutingPolicy{
+						SetIdentifier: "us-ca",
+						GeoLocation: &zone.GeoLocation{
+							CountryCode:     "US",
+							SubdivisionCode: "CA",
+						},
+					},
+				},
+			},
+			AAAA: []zone.Record{
+				zone.Record{
+					Name:  "latency.domain.com.",
+					Type:  "AAAA",
+					Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
+					Alias: true,
+					Target: &zone.AliasTarget{
+						HostedZoneID: "Z2BJ6XQ5FK7U4H",
+						Service:      "Global Accelerator",
+					},
+					Routing: &zone.RoutingPolicy{
+						SetIdentifier: "eu",
+						Region:        "eu-west-1",
+						Failover:      "PRIMARY",
+					},
+				},
+			},
+			TXT: []zone.Record{
+				zone.Record{
+					Name:  "multi.domain.com.",
+					Type:  "TXT",
+					Value: []string{"\"one\""},
+					TTL:   60,
+					Routing: &zone.RoutingPolicy{
+						SetIdentifier:    "one",
+						MultiValueAnswer: true,
+					},
+				},
+			},
+		},
+	})
+
+	// DS
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("domain.com."),
+					Type: aws.String("DS"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("12345 13 2 0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			DS: []zone.Record{
+				zone.Record{
+					Name:  "domain.com.",
+					Type:  "DS",
+					Value: []string{"12345 13 2 0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"},
+					Alias: false,
+					TTL:   300,
+				},
+			},
+		},
+	})
+
+	// HTTPS
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("domain.com."),
+					Type: aws.String("HTTPS"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("1 . alpn=\"h2,h3\""),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			HTTPS: []zone.Record{
+				zone.Record{
+					Name:  "domain.com.",
+					Type:  "HTTPS",
+					Value: []string{"1 . alpn=\"h2,h3\""},
+					Alias: false,
+					TTL:   300,
+				},
+			},
+		},
+	})
+
+	// SVCB
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("_8443._svc.domain.com."),
+					Type: aws.String("SVCB"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("1 svc.domain.com. port=8443"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			SVCB: []zone.Record{
+				zone.Record{
+					Name:  "_8443._svc.domain.com.",
+					Type:  "SVCB",
+					Value: []string{"1 svc.domain.com. port=8443"},
+					Alias: false,
+					TTL:   300,
+				},
+			},
+		},
+	})
+
+	// TLSA
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("_443._tcp.domain.com."),
+					Type: aws.String("TLSA"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("3 1 1 0123456789abcdef"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			TLSA: []zone.Record{
+				zone.Record{
+					Name:  "_443._tcp.domain.com.",
+					Type:  "TLSA",
+					Value: []string{"3 1 1 0123456789abcdef"},
+					Alias: false,
+					TTL:   300,
+				},
+			},
+		},
+	})
+
+	// SSHFP
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("host.domain.com."),
+					Type: aws.String("SSHFP"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("4 2 0123456789abcdef"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			SSHFP: []zone.Record{
+				zone.Record{
+					Name:  "host.domain.com.",
+					Type:  "SSHFP",
+					Value: []string{"4 2 0123456789abcdef"},
+					Alias: false,
+					TTL:   300,
+				},
+			},
+		},
+	})
+
+	// Unknown
+	suite = append(suite, map[*route53.ListResourceRecordSetsOutput]*r53.Records{
+		&route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []*route53.ResourceRecordSet{
+				&route53.ResourceRecordSet{
+					Name: aws.String("unknown.domain.com."),
+					Type: aws.String("TYPE65534"),
+					ResourceRecords: []*route53.ResourceRecord{
+						&route53.ResourceRecord{
+							Value: aws.String("\\# 4 0a000001"),
+						},
+					},
+					TTL: aws.Int64(300),
+				},
+			},
+		}: &r53.Records{
+			Other: []zone.Record{
+				zone.Record{
+					Name:  "unknown.domain.com.",
+					Type:  "TYPE65534",
+					Value: []string{"\\# 4 0a000001"},
+					Alias: false,
+					TTL:   300,
 				},
 			},
 		},
@@ -484,143 +831,163 @@ func TestAppend(t *testing.T) {
 func TestConvertToZonefile(t *testing.T) {
 
 	records := r53.Records{
-		SOA: r53.Record{
+		SOA: zone.Record{
 			Name:  "domain.com.",
+			Type:  "SOA",
 			Value: []string{"ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"},
 			Alias: false,
 			TTL:   900,
 		},
-		NS: []r53.Record{
-			r53.Record{
+		NS: []zone.Record{
+			zone.Record{
 				Name:  "domain.com.",
+				Type:  "NS",
 				Value: []string{"ns-1800.awsdns-33.co.uk."},
 				Alias: false,
 				TTL:   172800,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "domain.com.",
+				Type:  "NS",
 				Value: []string{"ns3.amazon.net"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		MX: []r53.Record{
-			r53.Record{
+		MX: []zone.Record{
+			zone.Record{
 				Name:  "mail1.domain.com.",
+				Type:  "MX",
 				Value: []string{"10 mailserver1.google.com"},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "mail2.domain.com.",
+				Type:  "MX",
 				Value: []string{"20 mailserver2.google.com"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		A: []r53.Record{
-			r53.Record{
+		A: []zone.Record{
+			zone.Record{
 				Name:  "a-alias.domain.com.",
+				Type:  "A",
 				Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
 				Alias: true,
 				TTL:   0,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "a-multiple.domain.com.",
+				Type:  "A",
 				Value: []string{"192.168.1.51"},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "a-multiple.domain.com.",
+				Type:  "A",
 				Value: []string{"192.168.1.52"},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "low-ttl.domain.com.",
+				Type:  "A",
 				Value: []string{"192.168.1.53"},
 				Alias: false,
 				TTL:   60,
 			},
 		},
-		AAAA: []r53.Record{
-			r53.Record{
+		AAAA: []zone.Record{
+			zone.Record{
 				Name:  "ipv6.domain.com.",
+				Type:  "AAAA",
 				Value: []string{"fe80:0:0:0:202:b3ff:fe1e:8329"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		CNAME: []r53.Record{
-			r53.Record{
+		CNAME: []zone.Record{
+			zone.Record{
 				Name:  "website.domain.com.",
+				Type:  "CNAME",
 				Value: []string{"www.domain.com"},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "www.domain.com.",
+				Type:  "CNAME",
 				Value: []string{"c0123456789abcdef.awsglobalaccelerator.com."},
 				Alias: true,
 				TTL:   0,
 			},
 		},
-		TXT: []r53.Record{
-			r53.Record{
+		TXT: []zone.Record{
+			zone.Record{
 				Name:  "txt.domain.com.",
+				Type:  "TXT",
 				Value: []string{"some string here"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		SRV: []r53.Record{
-			r53.Record{
+		SRV: []zone.Record{
+			zone.Record{
 				Name:  "dc.domain.com.",
+				Type:  "SRV",
 				Value: []string{"1 10 5269 controller.domain.com."},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		PTR: []r53.Record{
-			r53.Record{
+		PTR: []zone.Record{
+			zone.Record{
 				Name:  "pointer.domain.com.",
+				Type:  "PTR",
 				Value: []string{"www.domain.com"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		SPF: []r53.Record{
-			r53.Record{
+		SPF: []zone.Record{
+			zone.Record{
 				Name:  "policy.domain.com.",
+				Type:  "SPF",
 				Value: []string{"v=spf1 ip4:192.168.0.0/16-all"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		NAPTR: []r53.Record{
-			r53.Record{
+		NAPTR: []zone.Record{
+			zone.Record{
 				Name:  "name-auth.domain.com.",
+				Type:  "NAPTR",
 				Value: []string{"100 100 \"U\" \"\" \"!^.*$!sip:info@bar.example.com!\" ."},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		CAA: []r53.Record{
-			r53.Record{
+		CAA: []zone.Record{
+			zone.Record{
 				Name:  "ca1.domain.com.",
+				Type:  "CAA",
 				Value: []string{"0 issue \"caa.example.com\""},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "ca2.domain.com.",
+				Type:  "CAA",
 				Value: []string{"issuewild \";\""},
 				Alias: false,
 				TTL:   300,
 			},
-			r53.Record{
+			zone.Record{
 				Name:  "ca3.domain.com.",
+				Type:  "CAA",
 				Value: []string{"b0123456789abcdef.awsglobalaccelerator.com."},
 				Alias: true,
 				TTL:   0,
@@ -670,9 +1037,9 @@ ca1.domain.com.	300	IN	CAA	0 issue "caa.example.com"
 ca2.domain.com.	300	IN	CAA	issuewild ";"
 
 ;; Route53 Alias Records
-a-alias.domain.com.	0	IN	A	a0123456789abcdef.awsglobalaccelerator.com.
-www.domain.com.	0	IN	CNAME	c0123456789abcdef.awsglobalaccelerator.com.
-ca3.domain.com.	0	IN	CAA	b0123456789abcdef.awsglobalaccelerator.com.
+a-alias.domain.com.	IN	A	a0123456789abcdef.awsglobalaccelerator.com.
+www.domain.com.	IN	CNAME	c0123456789abcdef.awsglobalaccelerator.com.
+ca3.domain.com.	IN	CAA	b0123456789abcdef.awsglobalaccelerator.com.
 `))
 
 	zonefile, err := records.ConvertToZonefile()
@@ -685,304 +1052,3 @@ ca3.domain.com.	0	IN	CAA	b0123456789abcdef.awsglobalaccelerator.com.
 		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected.String(), zonefile.String())
 	}
 }
-
-func TestFormat(t *testing.T) {
-	suite := []map[*[]r53.Record]map[string]*bytes.Buffer{}
-
-	// SOA
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "domain.com.",
-				Value: []string{"ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"},
-				Alias: false,
-				TTL:   900,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("SOA")),
-			"records": bytes.NewBuffer([]byte(`;; SOA Records
-domain.com.	900	IN	SOA	ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// NS
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "domain.com.",
-				Value: []string{"ns-1800.awsdns-33.co.uk."},
-				Alias: false,
-				TTL:   172800,
-			},
-			r53.Record{
-				Name:  "domain.com.",
-				Value: []string{"ns3.amazon.net"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("NS")),
-			"records": bytes.NewBuffer([]byte(`;; NS Records
-domain.com.	172800	IN	NS	ns-1800.awsdns-33.co.uk.
-domain.com.	300	IN	NS	ns3.amazon.net
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// MX
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "mail1.domain.com.",
-				Value: []string{"10 mailserver1.google.com"},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "mail2.domain.com.",
-				Value: []string{"20 mailserver2.google.com"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("MX")),
-			"records": bytes.NewBuffer([]byte(`;; MX Records
-mail1.domain.com.	300	IN	MX	10 mailserver1.google.com
-mail2.domain.com.	300	IN	MX	20 mailserver2.google.com
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// A
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "a-alias.domain.com.",
-				Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
-				Alias: true,
-				TTL:   0,
-			},
-			r53.Record{
-				Name:  "a-multiple.domain.com.",
-				Value: []string{"192.168.1.51"},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "a-multiple.domain.com.",
-				Value: []string{"192.168.1.52"},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "low-ttl.domain.com.",
-				Value: []string{"192.168.1.53"},
-				Alias: false,
-				TTL:   60,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("A")),
-			"records": bytes.NewBuffer([]byte(`;; A Records
-a-multiple.domain.com.	300	IN	A	192.168.1.51
-a-multiple.domain.com.	300	IN	A	192.168.1.52
-low-ttl.domain.com.	60	IN	A	192.168.1.53
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
-a-alias.domain.com.	0	IN	A	a0123456789abcdef.awsglobalaccelerator.com.
-`)),
-		}})
-
-	// AAAA
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "ipv6.domain.com.",
-				Value: []string{"fe80:0:0:0:202:b3ff:fe1e:8329"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("AAAA")),
-			"records": bytes.NewBuffer([]byte(`;; AAAA Records
-ipv6.domain.com.	300	IN	AAAA	fe80:0:0:0:202:b3ff:fe1e:8329
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// CNAME
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "website.domain.com.",
-				Value: []string{"www.domain.com"},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "www.domain.com.",
-				Value: []string{"c0123456789abcdef.awsglobalaccelerator.com."},
-				Alias: true,
-				TTL:   0,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("CNAME")),
-			"records": bytes.NewBuffer([]byte(`;; CNAME Records
-website.domain.com.	300	IN	CNAME	www.domain.com
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
-www.domain.com.	0	IN	CNAME	c0123456789abcdef.awsglobalaccelerator.com.
-`)),
-		}})
-
-	// TXT
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "txt.domain.com.",
-				Value: []string{"some string here"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("TXT")),
-			"records": bytes.NewBuffer([]byte(`;; TXT Records
-txt.domain.com.	300	IN	TXT	some string here
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// SRV
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "dc.domain.com.",
-				Value: []string{"1 10 5269 controller.domain.com."},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("SRV")),
-			"records": bytes.NewBuffer([]byte(`;; SRV Records
-dc.domain.com.	300	IN	SRV	1 10 5269 controller.domain.com.
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// PTRC
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "pointer.domain.com.",
-				Value: []string{"www.domain.com"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("PTR")),
-			"records": bytes.NewBuffer([]byte(`;; PTR Records
-pointer.domain.com.	300	IN	PTR	www.domain.com
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// SPF
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "policy.domain.com.",
-				Value: []string{"v=spf1 ip4:192.168.0.0/16-all"},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("SPF")),
-			"records": bytes.NewBuffer([]byte(`;; SPF Records
-policy.domain.com.	300	IN	SPF	v=spf1 ip4:192.168.0.0/16-all
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// NAPTR
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "name-auth.domain.com.",
-				Value: []string{"100 100 \"U\" \"\" \"!^.*$!sip:info@bar.example.com!\" ."},
-				Alias: false,
-				TTL:   300,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("NAPTR")),
-			"records": bytes.NewBuffer([]byte(`;; NAPTR Records
-name-auth.domain.com.	300	IN	NAPTR	100 100 "U" "" "!^.*$!sip:info@bar.example.com!" .
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(``)),
-		}})
-
-	// CAA
-	suite = append(suite, map[*[]r53.Record]map[string]*bytes.Buffer{
-		&[]r53.Record{
-			r53.Record{
-				Name:  "ca1.domain.com.",
-				Value: []string{"0 issue \"caa.example.com\""},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "ca2.domain.com.",
-				Value: []string{"issuewild \";\""},
-				Alias: false,
-				TTL:   300,
-			},
-			r53.Record{
-				Name:  "ca3.domain.com.",
-				Value: []string{"b0123456789abcdef.awsglobalaccelerator.com."},
-				Alias: true,
-				TTL:   0,
-			},
-		}: map[string]*bytes.Buffer{
-			"type": bytes.NewBuffer([]byte("CAA")),
-			"records": bytes.NewBuffer([]byte(`;; CAA Records
-ca1.domain.com.	300	IN	CAA	0 issue "caa.example.com"
-ca2.domain.com.	300	IN	CAA	issuewild ";"
-
-`)),
-			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
-ca3.domain.com.	0	IN	CAA	b0123456789abcdef.awsglobalaccelerator.com.
-`)),
-		}})
-
-	for _, test := range suite {
-		for input, expected := range test {
-			records := bytes.Buffer{}
-			aliases := bytes.Buffer{}
-			var marker bool
-
-			err := r53.Format(&marker, input, &records, &aliases, expected["type"].String())
-
-			if err != nil {
-				t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
-			}
-
-			if !reflect.DeepEqual(expected["records"].String(), records.String()) {
-				t.Errorf("\nEXPECTED records: \n'%+v'\n\nGOT records: \n'%+v'\n\n", expected["records"].String(), records.String())
-			}
-
-			if !reflect.DeepEqual(expected["aliases"].String(), aliases.String()) {
-				t.Errorf("\nEXPECTED aliases: \n'%+v'\n\nGOT aliases: \n'%+v'\n\n", expected["aliases"].String(), aliases.String())
-			}
-		}
-	}
-}
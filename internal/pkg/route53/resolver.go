@@ -0,0 +1,277 @@
+package r53
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"dns-exporter/internal/pkg/utils"
+
+	"github.com/aws/aws-sdk-go/service/route53resolver"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Resolver interface
+type Resolver interface {
+	ListResolverEndpoints(*route53resolver.ListResolverEndpointsInput) (*route53resolver.ListResolverEndpointsOutput, error)
+	ListResolverEndpointIpAddresses(*route53resolver.ListResolverEndpointIpAddressesInput) (*route53resolver.ListResolverEndpointIpAddressesOutput, error)
+	ListResolverRules(*route53resolver.ListResolverRulesInput) (*route53resolver.ListResolverRulesOutput, error)
+	ListResolverRuleAssociations(*route53resolver.ListResolverRuleAssociationsInput) (*route53resolver.ListResolverRuleAssociationsOutput, error)
+	ListFirewallRuleGroups(*route53resolver.ListFirewallRuleGroupsInput) (*route53resolver.ListFirewallRuleGroupsOutput, error)
+	ListFirewallRules(*route53resolver.ListFirewallRulesInput) (*route53resolver.ListFirewallRulesOutput, error)
+	ListFirewallRuleGroupAssociations(*route53resolver.ListFirewallRuleGroupAssociationsInput) (*route53resolver.ListFirewallRuleGroupAssociationsOutput, error)
+	ListFirewallDomainLists(*route53resolver.ListFirewallDomainListsInput) (*route53resolver.ListFirewallDomainListsOutput, error)
+	ListFirewallDomains(*route53resolver.ListFirewallDomainsInput) (*route53resolver.ListFirewallDomainsOutput, error)
+}
+
+// ResolverEndpoint with its IP addresses
+type ResolverEndpoint struct {
+	Endpoint    *route53resolver.ResolverEndpoint    `json:"endpoint"`
+	IPAddresses []*route53resolver.IpAddressResponse `json:"ip_addresses"`
+}
+
+// FirewallRuleGroup with its rules
+type FirewallRuleGroup struct {
+	Group *route53resolver.FirewallRuleGroupMetadata `json:"group"`
+	Rules []*route53resolver.FirewallRule            `json:"rules"`
+}
+
+// FirewallDomainList with its domains
+type FirewallDomainList struct {
+	List    *route53resolver.FirewallDomainListMetadata `json:"list"`
+	Domains []*string                                   `json:"domains"`
+}
+
+// exportResolvers writes Resolver endpoints, rules, associations and DNS Firewall resources of every region into 'Resolver/<region>'
+func exportResolvers(resolvers map[string]Resolver, dir string, fs afero.Fs) error {
+	for region, c := range resolvers {
+		log.WithFields(log.Fields{
+			"provider": "Route53",
+			"region":   region,
+		}).Info("exporting resolver")
+
+		resources, err := getResolverResources(c)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Route53: error retrieving resolver resources of region '%s'", region))
+		}
+
+		d := fmt.Sprintf("%v/Resolver/%v", dir, region)
+		_, err = utils.ValidateDir(d, true, fs)
+		if err != nil {
+			return errors.Wrap(err, "Route53: error exporting resolver resources")
+		}
+
+		for name, content := range resources {
+			b, err := json.MarshalIndent(content, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("Route53: error composing resolver %s of region '%s'", name, region))
+			}
+
+			_, err = utils.WriteToFileAs(name, "json", string(b)+"\n", d, fs)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("Route53: error exporting resolver %s of region '%s'", name, region))
+			}
+		}
+	}
+
+	return nil
+}
+
+// getResolverResources returns Resolver resources of a region by a name of a file they are exported into
+func getResolverResources(c Resolver) (map[string]interface{}, error) {
+	endpoints, err := getResolverEndpoints(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing resolver endpoints")
+	}
+
+	rules := []*route53resolver.ResolverRule{}
+	var token *string
+	for {
+		o, err := c.ListResolverRules(&route53resolver.ListResolverRulesInput{NextToken: token})
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing resolver rules")
+		}
+
+		rules = append(rules, o.ResolverRules...)
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	associations := []*route53resolver.ResolverRuleAssociation{}
+	for {
+		o, err := c.ListResolverRuleAssociations(&route53resolver.ListResolverRuleAssociationsInput{NextToken: token})
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing resolver rule associations")
+		}
+
+		associations = append(associations, o.ResolverRuleAssociations...)
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	groups, err := getFirewallRuleGroups(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing firewall rule groups")
+	}
+
+	groupAssociations := []*route53resolver.FirewallRuleGroupAssociation{}
+	for {
+		o, err := c.ListFirewallRuleGroupAssociations(&route53resolver.ListFirewallRuleGroupAssociationsInput{NextToken: token})
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing firewall rule group associations")
+		}
+
+		groupAssociations = append(groupAssociations, o.FirewallRuleGroupAssociations...)
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	lists, err := getFirewallDomainLists(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing firewall domain lists")
+	}
+
+	return map[string]interface{}{
+		"endpoints":                        endpoints,
+		"rules":                            rules,
+		"rule-associations":                associations,
+		"firewall-rule-groups":             groups,
+		"firewall-rule-group-associations": groupAssociations,
+		"firewall-domain-lists":            lists,
+	}, nil
+}
+
+// getResolverEndpoints returns inbound and outbound endpoints with their IP addresses
+func getResolverEndpoints(c Resolver) ([]ResolverEndpoint, error) {
+	endpoints := []ResolverEndpoint{}
+
+	var token *string
+	for {
+		o, err := c.ListResolverEndpoints(&route53resolver.ListResolverEndpointsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range o.ResolverEndpoints {
+			endpoints = append(endpoints, ResolverEndpoint{Endpoint: e})
+		}
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	for i := range endpoints {
+		for {
+			o, err := c.ListResolverEndpointIpAddresses(&route53resolver.ListResolverEndpointIpAddressesInput{
+				ResolverEndpointId: endpoints[i].Endpoint.Id,
+				NextToken:          token,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing resolver endpoint IP addresses")
+			}
+
+			endpoints[i].IPAddresses = append(endpoints[i].IPAddresses, o.IpAddresses...)
+
+			if token = o.NextToken; token == nil {
+				break
+			}
+		}
+	}
+
+	return endpoints, nil
+}
+
+// getFirewallRuleGroups returns DNS Firewall rule groups with their rules
+func getFirewallRuleGroups(c Resolver) ([]FirewallRuleGroup, error) {
+	groups := []FirewallRuleGroup{}
+
+	var token *string
+	for {
+		o, err := c.ListFirewallRuleGroups(&route53resolver.ListFirewallRuleGroupsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range o.FirewallRuleGroups {
+			groups = append(groups, FirewallRuleGroup{Group: g})
+		}
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	for i := range groups {
+		for {
+			o, err := c.ListFirewallRules(&route53resolver.ListFirewallRulesInput{
+				FirewallRuleGroupId: groups[i].Group.Id,
+				NextToken:           token,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing firewall rules")
+			}
+
+			groups[i].Rules = append(groups[i].Rules, o.FirewallRules...)
+
+			if token = o.NextToken; token == nil {
+				break
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// getFirewallDomainLists returns DNS Firewall domain lists with their domains
+func getFirewallDomainLists(c Resolver) ([]FirewallDomainList, error) {
+	lists := []FirewallDomainList{}
+
+	var token *string
+	for {
+		o, err := c.ListFirewallDomainLists(&route53resolver.ListFirewallDomainListsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range o.FirewallDomainLists {
+			lists = append(lists, FirewallDomainList{List: l})
+		}
+
+		if token = o.NextToken; token == nil {
+			break
+		}
+	}
+
+	for i := range lists {
+		// contents of AWS managed domain lists are not exposed
+		if lists[i].List.ManagedOwnerName != nil {
+			continue
+		}
+
+		for {
+			o, err := c.ListFirewallDomains(&route53resolver.ListFirewallDomainsInput{
+				FirewallDomainListId: lists[i].List.Id,
+				NextToken:            token,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing firewall domains")
+			}
+
+			lists[i].Domains = append(lists[i].Domains, o.Domains...)
+
+			if token = o.NextToken; token == nil {
+				break
+			}
+		}
+	}
+
+	return lists, nil
+}
@@ -0,0 +1,182 @@
+package r53_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53resolver"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestProviderExportResolvers(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Route53{}
+	r := mocks.Route53Resolver{}
+
+	p := r53.NewProvider(&c)
+	p.Resolvers = map[string]r53.Resolver{
+		"eu-west-1": &r,
+	}
+
+	fs := afero.NewMemMapFs()
+
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+
+	r.On("ListResolverEndpoints").Return(&route53resolver.ListResolverEndpointsOutput{
+		ResolverEndpoints: []*route53resolver.ResolverEndpoint{
+			{
+				Id:        aws.String("rslvr-out-1"),
+				Name:      aws.String("on-prem"),
+				Direction: aws.String("OUTBOUND"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListResolverEndpointIpAddresses").Return(&route53resolver.ListResolverEndpointIpAddressesOutput{
+		IpAddresses: []*route53resolver.IpAddressResponse{
+			{
+				Ip:       aws.String("10.0.1.10"),
+				SubnetId: aws.String("subnet-1"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListResolverRules").Return(&route53resolver.ListResolverRulesOutput{
+		NextToken: aws.String("next"),
+		ResolverRules: []*route53resolver.ResolverRule{
+			{
+				Id:                 aws.String("rslvr-rr-1"),
+				DomainName:         aws.String("corp.local."),
+				RuleType:           aws.String("FORWARD"),
+				ResolverEndpointId: aws.String("rslvr-out-1"),
+				TargetIps: []*route53resolver.TargetAddress{
+					{
+						Ip:   aws.String("192.168.0.2"),
+						Port: aws.Int64(53),
+					},
+				},
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListResolverRules").Return(&route53resolver.ListResolverRulesOutput{
+		ResolverRules: []*route53resolver.ResolverRule{
+			{
+				Id:         aws.String("rslvr-autodefined-rr-internet-resolver"),
+				DomainName: aws.String("."),
+				RuleType:   aws.String("RECURSIVE"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListResolverRuleAssociations").Return(&route53resolver.ListResolverRuleAssociationsOutput{
+		ResolverRuleAssociations: []*route53resolver.ResolverRuleAssociation{
+			{
+				ResolverRuleId: aws.String("rslvr-rr-1"),
+				VPCId:          aws.String("vpc-1"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListFirewallRuleGroups").Return(&route53resolver.ListFirewallRuleGroupsOutput{
+		FirewallRuleGroups: []*route53resolver.FirewallRuleGroupMetadata{
+			{
+				Id:   aws.String("rslvr-frg-1"),
+				Name: aws.String("block-malware"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListFirewallRules").Return(&route53resolver.ListFirewallRulesOutput{
+		FirewallRules: []*route53resolver.FirewallRule{
+			{
+				Name:                 aws.String("block"),
+				Action:               aws.String("BLOCK"),
+				FirewallDomainListId: aws.String("rslvr-fdl-1"),
+				Priority:             aws.Int64(100),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListFirewallRuleGroupAssociations").Return(&route53resolver.ListFirewallRuleGroupAssociationsOutput{}, nil).Once()
+
+	r.On("ListFirewallDomainLists").Return(&route53resolver.ListFirewallDomainListsOutput{
+		FirewallDomainLists: []*route53resolver.FirewallDomainListMetadata{
+			{
+				Id:   aws.String("rslvr-fdl-1"),
+				Name: aws.String("blocked"),
+			},
+			{
+				Id:               aws.String("rslvr-fdl-2"),
+				Name:             aws.String("AWSManagedDomainsMalwareDomainList"),
+				ManagedOwnerName: aws.String("Route 53 Resolver DNS Firewall"),
+			},
+		},
+	}, nil).Once()
+
+	r.On("ListFirewallDomains").Return(&route53resolver.ListFirewallDomainsOutput{
+		Domains: aws.StringSlice([]string{"bad.example.com."}),
+	}, nil).Once()
+
+	p.Export(0, zone.Annotated, errs, &wg, ".", fs)
+
+	err := <-errs
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	r.AssertExpectations(t)
+
+	for _, file := range []string{"endpoints", "rule-associations", "firewall-rule-groups", "firewall-rule-group-associations", "firewall-domain-lists"} {
+		ok, err := afero.Exists(fs, "./Route53/Resolver/eu-west-1/"+file+".json")
+		if err != nil || !ok {
+			t.Errorf("\nEXPECTED file: \n%s.json\n\nGOT error: \n%v\n\n", file, err)
+		}
+	}
+
+	content, err := afero.ReadFile(fs, "./Route53/Resolver/eu-west-1/rules.json")
+	if err != nil {
+		t.Fatal("error reading exported resolver rules:", err)
+	}
+
+	var rules []route53resolver.ResolverRule
+	err = json.Unmarshal(content, &rules)
+	if err != nil {
+		t.Fatal("error parsing exported resolver rules:", err)
+	}
+
+	if len(rules) != 2 || *rules[0].TargetIps[0].Ip != "192.168.0.2" {
+		t.Errorf("\nEXPECTED resolver rules: \n2 rules, forwarding to 192.168.0.2\n\nGOT resolver rules: \n%+v\n\n", rules)
+	}
+
+	content, err = afero.ReadFile(fs, "./Route53/Resolver/eu-west-1/firewall-domain-lists.json")
+	if err != nil {
+		t.Fatal("error reading exported firewall domain lists:", err)
+	}
+
+	var lists []r53.FirewallDomainList
+	err = json.Unmarshal(content, &lists)
+	if err != nil {
+		t.Fatal("error parsing exported firewall domain lists:", err)
+	}
+
+	if len(lists) != 2 || len(lists[0].Domains) != 1 || lists[1].Domains != nil {
+		t.Errorf("\nEXPECTED firewall domain lists: \ndomains of a custom list only\n\nGOT firewall domain lists: \n%+v\n\n", lists)
+	}
+}
@@ -0,0 +1,406 @@
+package r53
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// limits of a single 'ChangeResourceRecordSets' request, UPSERT changes count twice
+const (
+	maxBatchRecords    = 1000
+	maxBatchCharacters = 32000
+)
+
+// interval between polls of a change status and a time a restore waits for a change to become INSYNC
+var (
+	waitInterval = 5 * time.Second
+	waitTimeout  = 10 * time.Minute
+)
+
+// Plan of changes that restore a hosted zone to its backup
+type Plan struct {
+	Zone    string
+	ZoneID  string
+	Changes []*route53.Change
+	// Replaced record sets of UPSERT changes, by their key
+	Replaced map[string]*route53.ResourceRecordSet
+	// Skipped record sets that a restore leaves untouched
+	Skipped []string
+}
+
+// Plan diffs a backup of a hosted zone against the live one.
+// Hosted zones have to be fetched beforehand, a deleted hosted zone is not recreated.
+func (p *Provider) Plan(backup zone.Zone) (Plan, error) {
+	zones := p.Zones.Public
+	if backup.Private {
+		zones = p.Zones.Private
+	}
+
+	origin := strings.ToLower(dns.Fqdn(backup.Name))
+
+	id, ok := zones[origin]
+	if !ok {
+		return Plan{}, fmt.Errorf("Route53: hosted zone '%s' does not exist", backup.Name)
+	}
+
+	live, err := getRecords(id, p.Client)
+	if err != nil {
+		return Plan{}, errors.Wrap(err, fmt.Sprintf("Route53: error retrieving zone '%s' records", backup.Name))
+	}
+
+	// live record sets of traffic policy instances are managed by their policy, which records API does not tell
+	policies, err := getTrafficPolicies(p.Client)
+	if err != nil && !denied(err, id, "traffic policy instances", "route53:ListTrafficPolicies") {
+		return Plan{}, errors.Wrap(err, fmt.Sprintf("Route53: error retrieving traffic policies of zone '%s'", backup.Name))
+	}
+
+	live.reference(referencesOf(nil, policies), id)
+
+	plan := diff(id, origin, backup.Records, live.List())
+	plan.Zone = origin
+
+	return plan, nil
+}
+
+// Apply changes of a plan in batches, waiting for every batch to become INSYNC
+func (p *Provider) Apply(plan Plan) error {
+	batches := batches(plan.Changes)
+	for i, batch := range batches {
+		log.WithFields(log.Fields{
+			"provider": "Route53",
+			"zone":     strings.TrimSuffix(plan.Zone, "."),
+			"batch":    fmt.Sprintf("%d/%d", i+1, len(batches)),
+			"changes":  len(batch),
+		}).Info("applying changes")
+
+		o, err := p.Client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(plan.ZoneID),
+			ChangeBatch: &route53.ChangeBatch{
+				Comment: aws.String("dns-exporter restore"),
+				Changes: batch,
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Route53: error applying changes to zone '%s'", plan.Zone))
+		}
+
+		err = wait(p.Client, *o.ChangeInfo.Id)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Route53: error applying changes to zone '%s'", plan.Zone))
+		}
+	}
+
+	return nil
+}
+
+// diff returns changes that turn live record sets of a zone 'origin' into backed up ones.
+// SOA and apex NS record sets are managed by Route53 and record sets of traffic policy instances by their policy, neither is changed.
+func diff(id, origin string, backup, live []zone.Record) Plan {
+	plan := Plan{
+		ZoneID:   id,
+		Replaced: make(map[string]*route53.ResourceRecordSet),
+	}
+
+	current := make(map[string]*route53.ResourceRecordSet)
+	var deleted []string
+
+	for _, r := range live {
+		if managed(r, origin) {
+			continue
+		}
+
+		set := recordSet(r, id)
+		current[key(set)] = set
+		deleted = append(deleted, key(set))
+	}
+
+	restored := make(map[string]bool)
+
+	for _, r := range backup {
+		if managed(r, origin) {
+			if r.Routing != nil && r.Routing.TrafficPolicyInstance != "" {
+				plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s %s (traffic policy instance %s)", r.Name, r.Type, r.Routing.TrafficPolicyInstance))
+			}
+			continue
+		}
+
+		set := recordSet(r, id)
+		k := key(set)
+		restored[k] = true
+
+		existing, ok := current[k]
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, &route53.Change{Action: aws.String(route53.ChangeActionCreate), ResourceRecordSet: set})
+		case !reflect.DeepEqual(existing, set):
+			plan.Changes = append(plan.Changes, &route53.Change{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: set})
+			plan.Replaced[k] = existing
+		}
+	}
+
+	for _, k := range deleted {
+		if !restored[k] {
+			plan.Changes = append(plan.Changes, &route53.Change{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: current[k]})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return rank(plan.Changes[i]) < rank(plan.Changes[j])
+	})
+
+	return plan
+}
+
+// managed reports whether a record set is managed by Route53 or a traffic policy rather than by a restore
+func managed(r zone.Record, origin string) bool {
+	switch {
+	case r.Type == "SOA":
+		return true
+	case r.Type == "NS" && strings.EqualFold(r.Name, origin):
+		return true
+	case r.Routing != nil && r.Routing.TrafficPolicyInstance != "":
+		return true
+	}
+
+	return false
+}
+
+// rank orders changes so that a record set is deleted before another one takes its name
+// and aliases are created after record sets they may point at
+func rank(c *route53.Change) int {
+	alias := c.ResourceRecordSet.AliasTarget != nil
+
+	switch {
+	case *c.Action == route53.ChangeActionDelete && alias:
+		return 0
+	case *c.Action == route53.ChangeActionDelete:
+		return 1
+	case !alias:
+		return 2
+	}
+
+	return 3
+}
+
+// recordSet returns a Route53 record set of a record, aliases to the same hosted zone point at a zone 'id'
+func recordSet(r zone.Record, id string) *route53.ResourceRecordSet {
+	set := &route53.ResourceRecordSet{
+		Name: aws.String(r.Name),
+		Type: aws.String(r.Type),
+	}
+
+	if r.Alias && len(r.Value) > 0 {
+		set.AliasTarget = &route53.AliasTarget{
+			DNSName:              aws.String(r.Value[0]),
+			EvaluateTargetHealth: aws.Bool(false),
+		}
+
+		if t := r.Target; t != nil {
+			set.AliasTarget.HostedZoneId = aws.String(t.HostedZoneID)
+			set.AliasTarget.EvaluateTargetHealth = aws.Bool(t.EvaluateTargetHealth)
+
+			// the hosted zone may have been recreated under a new ID since the backup
+			if t.Service == "Route53" {
+				set.AliasTarget.HostedZoneId = aws.String(strings.TrimPrefix(id, "/hostedzone/"))
+			}
+		}
+	} else {
+		values := append([]string{}, r.Value...)
+		sort.Strings(values)
+
+		set.TTL = aws.Int64(r.TTL)
+		for _, v := range values {
+			set.ResourceRecords = append(set.ResourceRecords, &route53.ResourceRecord{Value: aws.String(v)})
+		}
+	}
+
+	if p := r.Routing; p != nil {
+		if p.SetIdentifier != "" {
+			set.SetIdentifier = aws.String(p.SetIdentifier)
+		}
+
+		if p.Weight != nil {
+			set.Weight = aws.Int64(*p.Weight)
+		}
+
+		if p.Region != "" {
+			set.Region = aws.String(p.Region)
+		}
+
+		if g := p.GeoLocation; g != nil {
+			set.GeoLocation = &route53.GeoLocation{}
+
+			if g.ContinentCode != "" {
+				set.GeoLocation.ContinentCode = aws.String(g.ContinentCode)
+			}
+
+			if g.CountryCode != "" {
+				set.GeoLocation.CountryCode = aws.String(g.CountryCode)
+			}
+
+			if g.SubdivisionCode != "" {
+				set.GeoLocation.SubdivisionCode = aws.String(g.SubdivisionCode)
+			}
+		}
+
+		if p.Failover != "" {
+			set.Failover = aws.String(p.Failover)
+		}
+
+		if p.MultiValueAnswer {
+			set.MultiValueAnswer = aws.Bool(true)
+		}
+
+		if p.HealthCheckID != "" {
+			set.HealthCheckId = aws.String(p.HealthCheckID)
+		}
+	}
+
+	return set
+}
+
+// key identifies a record set within a hosted zone
+func key(set *route53.ResourceRecordSet) string {
+	k := fmt.Sprintf("%s %s", strings.ToLower(*set.Name), *set.Type)
+	if set.SetIdentifier != nil {
+		k += " " + *set.SetIdentifier
+	}
+
+	return k
+}
+
+// batches splits changes into requests within Route53 limits of values and their characters, keeping an order of changes
+func batches(changes []*route53.Change) [][]*route53.Change {
+	var batches [][]*route53.Change
+	var batch []*route53.Change
+	var records, characters int
+
+	for _, c := range changes {
+		r, ch := size(c)
+
+		if len(batch) > 0 && (records+r > maxBatchRecords || characters+ch > maxBatchCharacters) {
+			batches = append(batches, batch)
+			batch, records, characters = nil, 0, 0
+		}
+
+		batch = append(batch, c)
+		records += r
+		characters += ch
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// size returns a number of values and characters a change counts towards request limits
+func size(c *route53.Change) (int, int) {
+	records, characters := 1, 0
+
+	if c.ResourceRecordSet.AliasTarget == nil {
+		records = len(c.ResourceRecordSet.ResourceRecords)
+		for _, r := range c.ResourceRecordSet.ResourceRecords {
+			characters += len(*r.Value)
+		}
+	}
+
+	if *c.Action == route53.ChangeActionUpsert {
+		return records * 2, characters * 2
+	}
+
+	return records, characters
+}
+
+// wait polls a change until Route53 propagates it to all authoritative servers
+func wait(c Client, id string) error {
+	deadline := time.Now().Add(waitTimeout)
+
+	for {
+		o, err := c.GetChange(&route53.GetChangeInput{Id: aws.String(id)})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error retrieving change '%s' status", id))
+		}
+
+		if *o.ChangeInfo.Status == route53.ChangeStatusInsync {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("change '%s' is not INSYNC after %s", id, waitTimeout)
+		}
+
+		time.Sleep(waitInterval)
+	}
+}
+
+// Len returns a number of changes of a plan
+func (p Plan) Len() int {
+	return len(p.Changes)
+}
+
+// String returns a human readable plan
+func (p Plan) String() string {
+	b := strings.Builder{}
+
+	if len(p.Changes) == 0 {
+		b.WriteString(fmt.Sprintf("hosted zone %s is up-to-date with the backup\n", p.ZoneID))
+	} else {
+		b.WriteString(fmt.Sprintf("hosted zone %s, %d changes:\n", p.ZoneID, len(p.Changes)))
+	}
+
+	symbols := map[string]string{
+		route53.ChangeActionCreate: "+",
+		route53.ChangeActionUpsert: "~",
+		route53.ChangeActionDelete: "-",
+	}
+
+	for _, c := range p.Changes {
+		b.WriteString(fmt.Sprintf("  %s %s %s\n", symbols[*c.Action], *c.Action, describe(c.ResourceRecordSet)))
+
+		if replaced, ok := p.Replaced[key(c.ResourceRecordSet)]; ok {
+			b.WriteString(fmt.Sprintf("      was %s\n", describe(replaced)))
+		}
+	}
+
+	if len(p.Skipped) > 0 {
+		b.WriteString("record sets left untouched:\n")
+		for _, s := range p.Skipped {
+			b.WriteString(fmt.Sprintf("  %s\n", s))
+		}
+	}
+
+	return b.String()
+}
+
+// describe returns a single line description of a record set
+func describe(set *route53.ResourceRecordSet) string {
+	parts := []string{*set.Name, *set.Type}
+
+	if set.SetIdentifier != nil {
+		parts = append(parts, fmt.Sprintf("[%s]", *set.SetIdentifier))
+	}
+
+	if a := set.AliasTarget; a != nil {
+		parts = append(parts, fmt.Sprintf("ALIAS %s (%s)", aws.StringValue(a.DNSName), aws.StringValue(a.HostedZoneId)))
+	} else {
+		parts = append(parts, fmt.Sprintf("%d", aws.Int64Value(set.TTL)))
+		for _, r := range set.ResourceRecords {
+			parts = append(parts, *r.Value)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,251 @@
+package r53_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+	"dns-exporter/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	log "github.com/sirupsen/logrus"
+)
+
+// restored is a backup of 'domain.com.' zone, recreated since as '/hostedzone/Z2'
+func restored() []zone.Record {
+	weight := int64(10)
+
+	return []zone.Record{
+		{Name: "domain.com.", Type: "SOA", TTL: 900, Value: []string{"ns-1.awsdns-01.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}},
+		{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org."}},
+		{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5", "1.2.3.4"}},
+		{Name: "www.domain.com.", Type: "A", Value: []string{"domain.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z1", Service: "Route53"}},
+		{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &weight, HealthCheckID: "hc-1", HealthCheckName: "api"}},
+		{Name: "mail.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"mx.provider.com."}},
+		{Name: "tp.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.8"}, Routing: &zone.RoutingPolicy{TrafficPolicyInstance: "instance-1"}},
+	}
+}
+
+func live() []zone.Record {
+	weight := int64(10)
+
+	return []zone.Record{
+		{Name: "domain.com.", Type: "SOA", TTL: 900, Value: []string{"ns-9.awsdns-09.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}},
+		{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-9.awsdns-09.org."}},
+		{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4", "1.2.3.5"}},
+		{Name: "api.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &weight, HealthCheckID: "hc-1"}},
+		{Name: "mail.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.7"}},
+		{Name: "tp.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.9"}, Routing: &zone.RoutingPolicy{TrafficPolicyInstance: "instance-1"}},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	plan := r53.Diff("/hostedzone/Z2", "domain.com.", restored(), live())
+
+	expected := `hosted zone /hostedzone/Z2, 4 changes:
+  - DELETE mail.domain.com. A 300 1.2.3.7
+  ~ UPSERT api.domain.com. A [blue] 60 1.2.3.6
+      was api.domain.com. A [blue] 300 1.2.3.6
+  + CREATE mail.domain.com. CNAME 300 mx.provider.com.
+  + CREATE www.domain.com. A ALIAS domain.com. (Z2)
+record sets left untouched:
+  tp.domain.com. A (traffic policy instance instance-1)
+`
+
+	if plan.String() != expected {
+		t.Errorf("\nEXPECTED plan: \n%s\n\nGOT plan: \n%s\n\n", expected, plan.String())
+	}
+
+	plan = r53.Diff("/hostedzone/Z2", "domain.com.", live(), live())
+	if len(plan.Changes) != 0 {
+		t.Errorf("\nEXPECTED changes: \n0\n\nGOT changes: \n%d\n\n", len(plan.Changes))
+	}
+}
+
+func TestBatches(t *testing.T) {
+	change := func(action string, values, length int) *route53.Change {
+		set := &route53.ResourceRecordSet{Name: aws.String("domain.com."), Type: aws.String("TXT"), TTL: aws.Int64(300)}
+		for i := 0; i < values; i++ {
+			set.ResourceRecords = append(set.ResourceRecords, &route53.ResourceRecord{Value: aws.String(fmt.Sprintf("%0*d", length, i))})
+		}
+
+		return &route53.Change{Action: aws.String(action), ResourceRecordSet: set}
+	}
+
+	suite := []struct {
+		changes  []*route53.Change
+		expected []int
+	}{
+		// within limits
+		{[]*route53.Change{change("CREATE", 100, 10), change("DELETE", 100, 10)}, []int{2}},
+		// 1000 values
+		{[]*route53.Change{change("CREATE", 600, 10), change("CREATE", 400, 10), change("CREATE", 1, 10)}, []int{2, 1}},
+		// UPSERT counts twice
+		{[]*route53.Change{change("UPSERT", 300, 10), change("CREATE", 500, 10)}, []int{1, 1}},
+		// 32000 characters
+		{[]*route53.Change{change("CREATE", 300, 40), change("CREATE", 300, 40), change("CREATE", 300, 40)}, []int{2, 1}},
+	}
+
+	for _, s := range suite {
+		var sizes []int
+		for _, b := range r53.Batches(s.changes) {
+			sizes = append(sizes, len(b))
+		}
+
+		if !reflect.DeepEqual(sizes, s.expected) {
+			t.Errorf("\nEXPECTED batch sizes: \n%v\n\nGOT batch sizes: \n%v\n\n", s.expected, sizes)
+		}
+	}
+}
+
+func TestProviderPlanApply(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+	*r53.WaitInterval = 0
+
+	sets := &route53.ListResourceRecordSetsOutput{
+		IsTruncated: aws.Bool(false),
+		ResourceRecordSets: []*route53.ResourceRecordSet{
+			{
+				Name:            aws.String("domain.com."),
+				Type:            aws.String("SOA"),
+				TTL:             aws.Int64(900),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("ns-9.awsdns-09.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400")}},
+			},
+			{
+				Name:            aws.String("domain.com."),
+				Type:            aws.String("A"),
+				TTL:             aws.Int64(300),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+			},
+		},
+	}
+
+	backup := zone.Zone{
+		Name: "domain.com.",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}},
+		},
+	}
+
+	// planning does not change a zone
+	c := mocks.Route53{}
+	c.On("ListResourceRecordSets").Return(sets, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+
+	p := r53.NewProvider(&c)
+	p.Zones.Public["domain.com."] = "/hostedzone/Z2"
+
+	plan, err := p.Plan(backup)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(plan.Changes) != 1 || *plan.Changes[0].Action != "UPSERT" {
+		t.Errorf("\nEXPECTED changes: \nUPSERT domain.com. A\n\nGOT plan: \n%s\n\n", plan)
+	}
+
+	c.AssertNotCalled(t, "ChangeResourceRecordSets")
+
+	// applied changes are awaited
+	c = mocks.Route53{}
+	c.On("ChangeResourceRecordSets").Return(&route53.ChangeResourceRecordSetsOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: aws.String("/change/C1"), Status: aws.String("PENDING")},
+	}, nil).Once()
+	c.On("GetChange").Return(&route53.GetChangeOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: aws.String("/change/C1"), Status: aws.String("PENDING")},
+	}, nil).Once()
+	c.On("GetChange").Return(&route53.GetChangeOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: aws.String("/change/C1"), Status: aws.String("INSYNC")},
+	}, nil).Once()
+
+	p.Client = &c
+
+	err = p.Apply(plan)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	c.AssertExpectations(t)
+
+	// a deleted hosted zone is not recreated
+	backup.Name = "deleted.com."
+
+	_, err = p.Plan(backup)
+	if err == nil {
+		t.Error("\nEXPECTED error: \nhosted zone 'deleted.com.' does not exist\n\nGOT error: \n<nil>")
+	}
+}
+
+func TestProviderPlanTrafficPolicy(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Route53{}
+	c.On("ListResourceRecordSets").Return(&route53.ListResourceRecordSetsOutput{
+		IsTruncated: aws.Bool(false),
+		ResourceRecordSets: []*route53.ResourceRecordSet{
+			{
+				Name:            aws.String("domain.com."),
+				Type:            aws.String("A"),
+				TTL:             aws.Int64(300),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+			},
+			{
+				Name:            aws.String("tp.domain.com."),
+				Type:            aws.String("A"),
+				TTL:             aws.Int64(60),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.9")}},
+			},
+		},
+	}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{
+		IsTruncated: aws.Bool(false),
+		TrafficPolicySummaries: []*route53.TrafficPolicySummary{
+			{
+				Id:            aws.String("12345678-abcd-abcd-abcd-123456789012"),
+				Name:          aws.String("geo-web"),
+				Type:          aws.String("A"),
+				LatestVersion: aws.Int64(1),
+			},
+		},
+	}, nil).Once()
+	c.On("ListTrafficPolicyVersions").Return(&route53.ListTrafficPolicyVersionsOutput{
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{
+		IsTruncated: aws.Bool(false),
+		TrafficPolicyInstances: []*route53.TrafficPolicyInstance{
+			{
+				Id:                   aws.String("instance-1"),
+				HostedZoneId:         aws.String("Z2"),
+				Name:                 aws.String("tp.domain.com."),
+				TrafficPolicyId:      aws.String("12345678-abcd-abcd-abcd-123456789012"),
+				TrafficPolicyVersion: aws.Int64(1),
+				TrafficPolicyType:    aws.String("A"),
+			},
+		},
+	}, nil).Once()
+
+	p := r53.NewProvider(&c)
+	p.Zones.Public["domain.com."] = "/hostedzone/Z2"
+
+	// a record set of a traffic policy instance missing in a backup is not deleted
+	plan, err := p.Plan(zone.Zone{
+		Name: "domain.com.",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+		},
+	})
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(plan.Changes) != 0 {
+		t.Errorf("\nEXPECTED changes: \nnone\n\nGOT plan: \n%s\n\n", plan)
+	}
+
+	c.AssertExpectations(t)
+}
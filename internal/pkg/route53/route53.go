@@ -1,13 +1,16 @@
 package r53
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"dns-exporter/internal/pkg/utils"
+	"dns-exporter/internal/pkg/zone"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -47,13 +50,22 @@ func (z Zones) Fetch(c Client, errs chan error, wg *sync.WaitGroup) {
 	errs <- nil
 }
 
+// dir returns export directory of an account, every named account is exported into its own subdirectory
+func dir(root, account string) string {
+	if account == "" {
+		return fmt.Sprintf("%v/Route53", root)
+	}
+
+	return fmt.Sprintf("%v/Route53/%v", root, account)
+}
+
 // Export hosted zones
-func (z Zones) Export(c Client, delay int, errs chan error, wg *sync.WaitGroup, root string, fs afero.Fs) {
+func (z Zones) Export(c Client, delay int, mode zone.Mode, errs chan error, wg *sync.WaitGroup, root, account string, fs afero.Fs) {
 	defer wg.Done()
 
-	parent := fmt.Sprintf("%v/Route53", root)
-	public := fmt.Sprintf("%v/Route53/Public", root)
-	private := fmt.Sprintf("%v/Route53/Private", root)
+	parent := dir(root, account)
+	public := fmt.Sprintf("%v/Public", parent)
+	private := fmt.Sprintf("%v/Private", parent)
 
 	// validate filetree
 	for _, dir := range []string{parent, public, private} {
@@ -64,42 +76,92 @@ func (z Zones) Export(c Client, delay int, errs chan error, wg *sync.WaitGroup,
 		}
 	}
 
-	export := func(domain, id, t, dir string, c Client, fs afero.Fs) {
-		log.WithFields(log.Fields{
+	// health checks and traffic policies are shared by all zones of an account
+	refs, err := exportPolicies(c, parent, fs)
+	if err != nil {
+		errs <- errors.Wrap(err, "Route53: error exporting health checks and traffic policies")
+		return
+	}
+
+	export := func(domain, id, t, dir string, c Client, fs afero.Fs) error {
+		fields := log.Fields{
 			"provider": "Route53",
 			"zone":     strings.TrimSuffix(domain, "."),
 			"type":     t,
-		}).Info("exporting zone")
+		}
+		if account != "" {
+			fields["account"] = account
+		}
+		log.WithFields(fields).Info("exporting zone")
 
 		r, err := getRecords(id, c)
 		if err != nil {
-			errs <- errors.Wrap(err, fmt.Sprintf("Route53: error retrieving zone '%s' records", domain))
-			return
+			return errors.Wrap(err, fmt.Sprintf("Route53: error retrieving zone '%s' records", domain))
+		}
+
+		r.reference(refs, id)
+
+		m, err := getMetadata(id, t == "private", c)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Route53: error retrieving zone '%s' metadata", domain))
+		}
+
+		// hosted zone details are missing when the policy does not permit reading them
+		if m.Name == "" {
+			m.Name = domain
 		}
 
-		content, err := r.ConvertToZonefile()
+		records := zone.Zone{
+			Name:     domain,
+			ID:       id,
+			Provider: "Route53",
+			Private:  t == "private",
+			Records:  r.List(),
+		}
+
+		metadata, err := json.MarshalIndent(m, "", "  ")
 		if err != nil {
-			errs <- errors.Wrap(err, fmt.Sprintf("Route53: error composing zonefile for '%s' zone", domain))
-			return
+			return errors.Wrap(err, fmt.Sprintf("Route53: error composing metadata for '%s' zone", domain))
 		}
 
-		// write zonefile
-		_, err = utils.WriteToFile(domain, content.String(), dir, fs)
+		// write zone exports and metadata
+		err = records.Write(dir, mode, fs)
 		if err != nil {
-			errs <- errors.Wrap(err, fmt.Sprintf("CloudFlare: error exporting zone: '%s'", domain))
-			return
+			return errors.Wrap(err, fmt.Sprintf("Route53: error exporting zone: '%s'", domain))
+		}
+
+		_, err = utils.WriteToFileAs(domain, "meta.json", string(metadata)+"\n", dir, fs)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Route53: error exporting zone metadata: '%s'", domain))
+		}
+
+		if zone.Enabled(zone.TerraformFormat) {
+			err = zone.WriteConfig(domain, "tf", terraform(id, m, records.Records), fmt.Sprintf("%s/terraform", dir), fs)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("Route53: error exporting zone Terraform config: '%s'", domain))
+			}
 		}
 
 		time.Sleep(time.Duration(delay) * time.Second)
+
+		return nil
 	}
 
 	// export zonefiles
 	for domain, id := range z.Public {
-		export(domain, id, "public", public, c, fs)
+		err := export(domain, id, "public", public, c, fs)
+		if err != nil {
+			errs <- err
+			return
+		}
 	}
 
 	for domain, id := range z.Private {
-		export(domain, id, "private", private, c, fs)
+		err := export(domain, id, "private", private, c, fs)
+		if err != nil {
+			errs <- err
+			return
+		}
 	}
 
 	errs <- nil
@@ -109,12 +171,14 @@ func (z Zones) Export(c Client, delay int, errs chan error, wg *sync.WaitGroup,
 func getRecords(id string, c Client) (Records, error) {
 	var r Records
 
-	var t, n *string
+	// a page may end within record sets of a routing policy, which share a name and a type
+	var t, n, s *string
 	for {
 		o, err := c.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
-			HostedZoneId:    &id,
-			StartRecordType: t,
-			StartRecordName: n,
+			HostedZoneId:          &id,
+			StartRecordType:       t,
+			StartRecordName:       n,
+			StartRecordIdentifier: s,
 		})
 		if err != nil {
 			return Records{}, errors.Wrap(err, "error retrieving zone records")
@@ -128,10 +192,32 @@ func getRecords(id string, c Client) (Records, error) {
 		if *o.IsTruncated {
 			t = o.NextRecordType
 			n = o.NextRecordName
+			s = o.NextRecordIdentifier
 		} else {
 			break
 		}
 	}
 
+	r.resolveLocalAliases(id)
+
 	return r, nil
 }
+
+// denied reports whether a request failed on a missing IAM permission, which is logged as a warning
+func denied(err error, id, name, permission string) bool {
+	var e awserr.Error
+	if !errors.As(err, &e) || (e.Code() != "AccessDenied" && e.Code() != "AccessDeniedException") {
+		return false
+	}
+
+	fields := log.Fields{
+		"provider":   "Route53",
+		"permission": permission,
+	}
+	if id != "" {
+		fields["zone"] = id
+	}
+	log.WithFields(fields).Warn(fmt.Sprintf("skipping %s export, IAM policy does not permit reading it", name))
+
+	return true
+}
@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
 	"dns-exporter/mocks"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/route53"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -192,8 +194,16 @@ local.	1	IN	A	192.168.1.52
 
 	c.On("ListResourceRecordSets").Return(replies["1"], nil).Once()
 	c.On("ListResourceRecordSets").Return(replies["2"], nil).Once()
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{HostedZone: &route53.HostedZone{Id: aws.String("1"), Name: aws.String("domain.com.")}}, nil).Once()
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{HostedZone: &route53.HostedZone{Id: aws.String("2"), Name: aws.String("local.")}}, nil).Once()
+	c.On("ListTagsForResource").Return(&route53.ListTagsForResourceOutput{}, nil).Twice()
+	c.On("GetDNSSEC").Return(&route53.GetDNSSECOutput{Status: &route53.DNSSECStatus{ServeSignature: aws.String("NOT_SIGNING")}}, nil).Once()
+	c.On("ListQueryLoggingConfigs").Return(&route53.ListQueryLoggingConfigsOutput{}, nil).Once()
+	c.On("ListHealthChecks").Return(&route53.ListHealthChecksOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicies").Return(&route53.ListTrafficPoliciesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
+	c.On("ListTrafficPolicyInstances").Return(&route53.ListTrafficPolicyInstancesOutput{IsTruncated: aws.Bool(false)}, nil).Once()
 
-	z.Export(&c, 0, errs, &wg, ".", fs)
+	z.Export(&c, 0, zone.Annotated, errs, &wg, ".", "", fs)
 
 	err := <-errs
 	if err != nil {
@@ -262,17 +272,19 @@ func TestGetRecords(t *testing.T) {
 	}
 
 	expected := r53.Records{
-		A: []r53.Record{
-			r53.Record{
+		A: []zone.Record{
+			zone.Record{
 				Name:  "a.domain.com.",
+				Type:  "A",
 				Value: []string{"192.168.1.51"},
 				Alias: false,
 				TTL:   300,
 			},
 		},
-		AAAA: []r53.Record{
-			r53.Record{
+		AAAA: []zone.Record{
+			zone.Record{
 				Name:  "aaaa.domain.com.",
+				Type:  "AAAA",
 				Value: []string{"fe80:0:0:0:202:b3ff:fe1e:8329"},
 				Alias: false,
 				TTL:   300,
@@ -293,3 +305,317 @@ func TestGetRecords(t *testing.T) {
 		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, r)
 	}
 }
+
+// paging replies with pages of record sets and keeps requests it receives
+type paging struct {
+	mocks.Route53
+	pages    []*route53.ListResourceRecordSetsOutput
+	requests []*route53.ListResourceRecordSetsInput
+}
+
+func (c *paging) ListResourceRecordSets(i *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	c.requests = append(c.requests, i)
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+	return page, nil
+}
+
+func TestGetRecordsPagination(t *testing.T) {
+	weighted := func(id string, weight int64, value string) *route53.ResourceRecordSet {
+		return &route53.ResourceRecordSet{
+			Name:            aws.String("api.domain.com."),
+			Type:            aws.String("A"),
+			SetIdentifier:   aws.String(id),
+			Weight:          aws.Int64(weight),
+			TTL:             aws.Int64(60),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(value)}},
+		}
+	}
+
+	// a page ends within a weighted group
+	c := &paging{
+		pages: []*route53.ListResourceRecordSetsOutput{
+			{
+				IsTruncated:          aws.Bool(true),
+				NextRecordName:       aws.String("api.domain.com."),
+				NextRecordType:       aws.String("A"),
+				NextRecordIdentifier: aws.String("green"),
+				ResourceRecordSets:   []*route53.ResourceRecordSet{weighted("blue", 10, "1.2.3.4")},
+			},
+			{
+				IsTruncated:        aws.Bool(false),
+				ResourceRecordSets: []*route53.ResourceRecordSet{weighted("green", 0, "1.2.3.5")},
+			},
+		},
+	}
+
+	r, err := r53.GetRecords("/hostedzone/A1M9OJ3HY2SUQY", c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(c.requests) != 2 || aws.StringValue(c.requests[1].StartRecordIdentifier) != "green" {
+		t.Errorf("\nEXPECTED second page from: \ngreen\n\nGOT requests: \n%+v\n\n", c.requests)
+	}
+
+	if len(r.A) != 2 {
+		t.Errorf("\nEXPECTED A record sets: \n2\n\nGOT A record sets: \n%+v\n\n", r.A)
+	}
+}
+
+func TestGetRecordsAliasTargets(t *testing.T) {
+	c := mocks.Route53{}
+	id := "/hostedzone/A1M9OJ3HY2SUQY"
+
+	alias := func(name, target, zoneID string) *route53.ResourceRecordSet {
+		return &route53.ResourceRecordSet{
+			Name: aws.String(name),
+			Type: aws.String("A"),
+			AliasTarget: &route53.AliasTarget{
+				DNSName:              aws.String(target),
+				HostedZoneId:         aws.String(zoneID),
+				EvaluateTargetHealth: aws.Bool(true),
+			},
+		}
+	}
+
+	reply := &route53.ListResourceRecordSetsOutput{
+		IsTruncated: aws.Bool(false),
+		ResourceRecordSets: []*route53.ResourceRecordSet{
+			alias("cdn.domain.com.", "d111111abcdef8.cloudfront.net.", "Z2FDTNDATAQYW2"),
+			alias("lb.domain.com.", "dualstack.my-lb-1234567890.eu-west-1.elb.amazonaws.com.", "Z32O12XQLNTSW2"),
+			alias("nlb.domain.com.", "my-nlb-0123456789abcdef.elb.eu-west-1.amazonaws.com.", "Z2IFOLAFXWLO4F"),
+			alias("static.domain.com.", "s3-website-eu-west-1.amazonaws.com.", "Z1BKCTXD74EZPE"),
+			alias("api.domain.com.", "d-abcdef1234.execute-api.eu-west-1.amazonaws.com.", "ZLY8HYME6SFDD"),
+			alias("ga.domain.com.", "a0123456789abcdef.awsglobalaccelerator.com.", "Z2BJ6XQ5FK7U4H"),
+			alias("local.domain.com.", "www.domain.com.", "A1M9OJ3HY2SUQY"),
+			alias("other.domain.com.", "www.example.com.", "Z0000000000000"),
+		},
+	}
+
+	expected := map[string]string{
+		"cdn.domain.com.":    "CloudFront",
+		"lb.domain.com.":     "ELB",
+		"nlb.domain.com.":    "ELB",
+		"static.domain.com.": "S3 website",
+		"api.domain.com.":    "API Gateway",
+		"ga.domain.com.":     "Global Accelerator",
+		"local.domain.com.":  "Route53",
+		"other.domain.com.":  "",
+	}
+
+	c.On("ListResourceRecordSets").Return(reply, nil).Once()
+
+	r, err := r53.GetRecords(id, &c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(r.A) != len(expected) {
+		t.Fatalf("\nEXPECTED records: \n%v\n\nGOT records: \n%v\n\n", len(expected), len(r.A))
+	}
+
+	for _, record := range r.A {
+		if record.Target == nil {
+			t.Fatalf("\nEXPECTED alias target for: \n%s\n\nGOT: \n<nil>\n\n", record.Name)
+		}
+
+		if record.Target.Service != expected[record.Name] {
+			t.Errorf("\nEXPECTED service of '%s': \n'%s'\n\nGOT service: \n'%s'\n\n", record.Name, expected[record.Name], record.Target.Service)
+		}
+
+		if !record.Target.EvaluateTargetHealth || record.Target.HostedZoneID == "" {
+			t.Errorf("\nEXPECTED full alias target of '%s'\n\nGOT: \n%+v\n\n", record.Name, record.Target)
+		}
+	}
+}
+
+func TestGetMetadata(t *testing.T) {
+	c := mocks.Route53{}
+	id := "/hostedzone/A1M9OJ3HY2SUQY"
+
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{
+		HostedZone: &route53.HostedZone{
+			Id:   aws.String(id),
+			Name: aws.String("domain.com."),
+			Config: &route53.HostedZoneConfig{
+				Comment:     aws.String("managed by terraform"),
+				PrivateZone: aws.Bool(false),
+			},
+		},
+		DelegationSet: &route53.DelegationSet{
+			Id:          aws.String("/delegationset/N1PA6795SAMPLE"),
+			NameServers: aws.StringSlice([]string{"ns1.domain.com", "ns2.domain.com"}),
+		},
+	}, nil).Once()
+
+	c.On("ListTagsForResource").Return(&route53.ListTagsForResourceOutput{
+		ResourceTagSet: &route53.ResourceTagSet{
+			Tags: []*route53.Tag{
+				{
+					Key:   aws.String("team"),
+					Value: aws.String("platform"),
+				},
+			},
+		},
+	}, nil).Once()
+
+	c.On("GetDNSSEC").Return(&route53.GetDNSSECOutput{
+		Status: &route53.DNSSECStatus{
+			ServeSignature: aws.String("SIGNING"),
+		},
+		KeySigningKeys: []*route53.KeySigningKey{
+			{
+				Name:                     aws.String("ksk1"),
+				Status:                   aws.String("ACTIVE"),
+				KmsArn:                   aws.String("arn:aws:kms:us-east-1:111111111111:key/abcd"),
+				KeyTag:                   aws.Int64(12345),
+				SigningAlgorithmMnemonic: aws.String("ECDSAP256SHA256"),
+				DNSKEYRecord:             aws.String("257 3 13 AAAA"),
+				DSRecord:                 aws.String("12345 13 2 ABCD"),
+			},
+		},
+	}, nil).Once()
+
+	c.On("ListQueryLoggingConfigs").Return(&route53.ListQueryLoggingConfigsOutput{
+		NextToken: aws.String("next"),
+		QueryLoggingConfigs: []*route53.QueryLoggingConfig{
+			{
+				Id:                        aws.String("87654321-dcba-1234-abcd-1a2b3c4d5e6f"),
+				CloudWatchLogsLogGroupArn: aws.String("arn:aws:logs:us-east-1:111111111111:log-group:/aws/route53/domain.com"),
+			},
+		},
+	}, nil).Once()
+	c.On("ListQueryLoggingConfigs").Return(&route53.ListQueryLoggingConfigsOutput{}, nil).Once()
+
+	expected := r53.Metadata{
+		ID:      id,
+		Name:    "domain.com.",
+		Comment: "managed by terraform",
+		Tags: map[string]string{
+			"team": "platform",
+		},
+		DelegationSet: &r53.DelegationSet{
+			ID:          "/delegationset/N1PA6795SAMPLE",
+			Reusable:    true,
+			NameServers: []string{"ns1.domain.com", "ns2.domain.com"},
+		},
+		DNSSEC: &r53.DNSSEC{
+			Status: "SIGNING",
+			KeySigningKeys: []r53.KeySigningKey{
+				{
+					Name:      "ksk1",
+					Status:    "ACTIVE",
+					KMSKey:    "arn:aws:kms:us-east-1:111111111111:key/abcd",
+					KeyTag:    12345,
+					Algorithm: "ECDSAP256SHA256",
+					DNSKey:    "257 3 13 AAAA",
+					DS:        "12345 13 2 ABCD",
+				},
+			},
+		},
+		QueryLogging: []r53.QueryLogging{
+			{
+				ID:       "87654321-dcba-1234-abcd-1a2b3c4d5e6f",
+				LogGroup: "arn:aws:logs:us-east-1:111111111111:log-group:/aws/route53/domain.com",
+			},
+		},
+	}
+
+	m, err := r53.GetMetadata(id, false, &c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, m) {
+		t.Errorf("\nEXPECTED metadata: \n%+v\n\nGOT metadata: \n%+v\n\n", expected, m)
+	}
+
+	// private zone: VPC associations, no DNSSEC and query logging
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{
+		HostedZone: &route53.HostedZone{
+			Id:   aws.String("/hostedzone/C3M9OJ3HY2SUQY"),
+			Name: aws.String("domain.local."),
+		},
+		VPCs: []*route53.VPC{
+			{
+				VPCId:     aws.String("vpc-1a2b3c4d"),
+				VPCRegion: aws.String("eu-west-1"),
+			},
+		},
+	}, nil).Once()
+	c.On("ListTagsForResource").Return(&route53.ListTagsForResourceOutput{}, nil).Once()
+
+	expected = r53.Metadata{
+		ID:      "/hostedzone/C3M9OJ3HY2SUQY",
+		Name:    "domain.local.",
+		Private: true,
+		VPCs: []r53.VPC{
+			{
+				ID:     "vpc-1a2b3c4d",
+				Region: "eu-west-1",
+			},
+		},
+	}
+
+	m, err = r53.GetMetadata("/hostedzone/C3M9OJ3HY2SUQY", true, &c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, m) {
+		t.Errorf("\nEXPECTED metadata: \n%+v\n\nGOT metadata: \n%+v\n\n", expected, m)
+	}
+
+	c.AssertExpectations(t)
+}
+
+func TestGetMetadataDenied(t *testing.T) {
+	log.SetLevel(log.ErrorLevel)
+
+	c := mocks.Route53{}
+	id := "/hostedzone/A1M9OJ3HY2SUQY"
+
+	denied := awserr.New("AccessDenied", "User is not authorized to perform this action", nil)
+
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{}, denied).Once()
+	c.On("ListTagsForResource").Return(&route53.ListTagsForResourceOutput{
+		ResourceTagSet: &route53.ResourceTagSet{
+			Tags: []*route53.Tag{
+				{
+					Key:   aws.String("team"),
+					Value: aws.String("platform"),
+				},
+			},
+		},
+	}, nil).Once()
+	c.On("GetDNSSEC").Return(&route53.GetDNSSECOutput{}, denied).Once()
+	c.On("ListQueryLoggingConfigs").Return(&route53.ListQueryLoggingConfigsOutput{}, denied).Once()
+
+	// parts the policy permits reading are exported only
+	expected := r53.Metadata{
+		ID: id,
+		Tags: map[string]string{
+			"team": "platform",
+		},
+	}
+
+	m, err := r53.GetMetadata(id, false, &c)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, m) {
+		t.Errorf("\nEXPECTED metadata: \n%+v\n\nGOT metadata: \n%+v\n\n", expected, m)
+	}
+
+	// other errors fail the zone
+	c.On("GetHostedZone").Return(&route53.GetHostedZoneOutput{}, awserr.New("Throttling", "Rate exceeded", nil)).Once()
+
+	_, err = r53.GetMetadata(id, false, &c)
+	if err == nil {
+		t.Fatal("\nEXPECTED error: \nThrottling: Rate exceeded\n\nGOT error: \n<nil>")
+	}
+
+	c.AssertExpectations(t)
+}
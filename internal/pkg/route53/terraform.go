@@ -0,0 +1,176 @@
+package r53
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
+)
+
+// terraform returns Terraform config of a hosted zone and its record sets, with import blocks of the existing resources.
+// Record sets that are managed by a traffic policy instance are listed as comments, as Terraform would fight over them.
+func terraform(id string, m Metadata, records []zone.Record) string {
+	id = strings.TrimPrefix(id, "/hostedzone/")
+	names := zone.Resources{}
+
+	b := strings.Builder{}
+
+	z := names.Name(unescape(m.Name))
+	b.WriteString(fmt.Sprintf("resource \"aws_route53_zone\" %q {\n", z))
+
+	// comment defaults to 'Managed by Terraform' when omitted
+	attributes := [][2]string{
+		{"name", zone.HCL(unescape(m.Name))},
+		{"comment", zone.HCL(m.Comment)},
+	}
+
+	if m.DelegationSet != nil && m.DelegationSet.Reusable {
+		attributes = append(attributes, [2]string{"delegation_set_id", zone.HCL(strings.TrimPrefix(m.DelegationSet.ID, "/delegationset/"))})
+	}
+
+	if len(m.Tags) > 0 {
+		attributes = append(attributes, [2]string{"tags", zone.HCLMap(m.Tags)})
+	}
+
+	b.WriteString(zone.Attributes(attributes, "  "))
+
+	for _, vpc := range m.VPCs {
+		b.WriteString("\n  vpc {\n")
+		b.WriteString(zone.Attributes([][2]string{{"vpc_id", zone.HCL(vpc.ID)}, {"vpc_region", zone.HCL(vpc.Region)}}, "    "))
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n\n")
+	b.WriteString(zone.Import("aws_route53_zone."+z, id))
+
+	var skipped []string
+
+	for _, r := range records {
+		if r.Routing != nil && r.Routing.TrafficPolicyInstance != "" {
+			skipped = append(skipped, fmt.Sprintf("# %s %s (traffic policy instance %s)", r.Name, r.Type, r.Routing.TrafficPolicyInstance))
+			continue
+		}
+
+		name := unescape(r.Name)
+
+		parts := []string{name, r.Type}
+		if r.Routing != nil && r.Routing.SetIdentifier != "" {
+			parts = append(parts, r.Routing.SetIdentifier)
+		}
+
+		n := names.Name(parts...)
+
+		b.WriteString(fmt.Sprintf("\nresource \"aws_route53_record\" %q {\n", n))
+		b.WriteString(resource(r, name, z))
+		b.WriteString("}\n\n")
+		b.WriteString(zone.Import("aws_route53_record."+n, strings.Join(append([]string{id}, parts...), "_")))
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("\n# record sets not represented in this config:\n" + strings.Join(skipped, "\n") + "\n")
+	}
+
+	return b.String()
+}
+
+// resource returns attributes and nested blocks of an 'aws_route53_record' resource
+func resource(r zone.Record, name, z string) string {
+	attributes := [][2]string{
+		{"zone_id", fmt.Sprintf("aws_route53_zone.%s.zone_id", z)},
+		{"name", zone.HCL(name)},
+		{"type", zone.HCL(r.Type)},
+	}
+
+	// TXT values are quoted by a provider
+	var values []string
+	for _, v := range r.Value {
+		if r.Type == "TXT" || r.Type == "SPF" {
+			v = strings.TrimSuffix(strings.TrimPrefix(v, `"`), `"`)
+		}
+		values = append(values, v)
+	}
+
+	if !r.Alias {
+		attributes = append(attributes, [2]string{"ttl", strconv.FormatInt(r.TTL, 10)}, [2]string{"records", zone.HCLList(values)})
+	}
+
+	var blocks []string
+
+	if p := r.Routing; p != nil {
+		if p.SetIdentifier != "" {
+			attributes = append(attributes, [2]string{"set_identifier", zone.HCL(p.SetIdentifier)})
+		}
+
+		if p.HealthCheckID != "" {
+			attributes = append(attributes, [2]string{"health_check_id", zone.HCL(p.HealthCheckID)})
+		}
+
+		if p.MultiValueAnswer {
+			attributes = append(attributes, [2]string{"multivalue_answer_routing_policy", "true"})
+		}
+
+		if p.Weight != nil {
+			blocks = append(blocks, zone.Block("weighted_routing_policy", [][2]string{{"weight", strconv.FormatInt(*p.Weight, 10)}}))
+		}
+
+		if p.Region != "" {
+			blocks = append(blocks, zone.Block("latency_routing_policy", [][2]string{{"region", zone.HCL(p.Region)}}))
+		}
+
+		if p.Failover != "" {
+			blocks = append(blocks, zone.Block("failover_routing_policy", [][2]string{{"type", zone.HCL(p.Failover)}}))
+		}
+
+		if g := p.GeoLocation; g != nil {
+			var location [][2]string
+			if g.ContinentCode != "" {
+				location = append(location, [2]string{"continent", zone.HCL(g.ContinentCode)})
+			}
+			if g.CountryCode != "" {
+				location = append(location, [2]string{"country", zone.HCL(g.CountryCode)})
+			}
+			if g.SubdivisionCode != "" {
+				location = append(location, [2]string{"subdivision", zone.HCL(g.SubdivisionCode)})
+			}
+			blocks = append(blocks, zone.Block("geolocation_routing_policy", location))
+		}
+	}
+
+	if r.Alias && len(values) > 0 {
+		target := [][2]string{{"name", zone.HCL(strings.TrimSuffix(unescape(values[0]), "."))}}
+
+		var evaluate bool
+		if t := r.Target; t != nil {
+			evaluate = t.EvaluateTargetHealth
+
+			// targets of the same hosted zone reference it
+			if t.Service == "Route53" {
+				target = append(target, [2]string{"zone_id", fmt.Sprintf("aws_route53_zone.%s.zone_id", z)})
+			} else {
+				target = append(target, [2]string{"zone_id", zone.HCL(t.HostedZoneID)})
+			}
+		}
+
+		target = append(target, [2]string{"evaluate_target_health", strconv.FormatBool(evaluate)})
+		blocks = append(blocks, zone.Block("alias", target))
+	}
+
+	content := zone.Attributes(attributes, "  ")
+	for _, b := range blocks {
+		content += "\n" + b
+	}
+
+	return content
+}
+
+// unescape returns a domain name without a trailing dot and with octal escapes of Route53 (such as '\052' for a wildcard) decoded
+func unescape(name string) string {
+	name = regexp.MustCompile(`\\[0-7]{3}`).ReplaceAllStringFunc(name, func(e string) string {
+		c, _ := strconv.ParseUint(e[1:], 8, 8)
+		return string(rune(c))
+	})
+
+	return strings.TrimSuffix(name, ".")
+}
@@ -0,0 +1,149 @@
+package r53_test
+
+import (
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestTerraform(t *testing.T) {
+	weight := int64(10)
+
+	m := r53.Metadata{
+		ID:            "/hostedzone/Z1",
+		Name:          "domain.com.",
+		Comment:       "production",
+		Tags:          map[string]string{"team": "web", "env": "prod"},
+		DelegationSet: &r53.DelegationSet{ID: "/delegationset/N1", Reusable: true},
+	}
+
+	records := []zone.Record{
+		{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org.", "ns-2.awsdns-02.com."}},
+		{Name: "domain.com.", Type: "A", Value: []string{"d111111abcdef8.cloudfront.net."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2FDTNDATAQYW2", Service: "CloudFront"}},
+		{Name: "www.domain.com.", Type: "A", Value: []string{"domain.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z1", EvaluateTargetHealth: true, Service: "Route53"}},
+		{Name: `\052.domain.com.`, Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`, `"part one" "${part} two"`}},
+		{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &weight, HealthCheckID: "hc-1"}},
+		{Name: "geo.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.7"}, Routing: &zone.RoutingPolicy{SetIdentifier: "us", GeoLocation: &zone.GeoLocation{CountryCode: "US", SubdivisionCode: "CA"}}},
+		{Name: "tp.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.8"}, Routing: &zone.RoutingPolicy{TrafficPolicyInstance: "instance-1"}},
+	}
+
+	expected := `resource "aws_route53_zone" "domain_com" {
+  name              = "domain.com"
+  comment           = "production"
+  delegation_set_id = "N1"
+  tags              = { "env" = "prod", "team" = "web" }
+}
+
+import {
+  to = aws_route53_zone.domain_com
+  id = "Z1"
+}
+
+resource "aws_route53_record" "domain_com_ns" {
+  zone_id = aws_route53_zone.domain_com.zone_id
+  name    = "domain.com"
+  type    = "NS"
+  ttl     = 172800
+  records = ["ns-1.awsdns-01.org.", "ns-2.awsdns-02.com."]
+}
+
+import {
+  to = aws_route53_record.domain_com_ns
+  id = "Z1_domain.com_NS"
+}
+
+resource "aws_route53_record" "domain_com_a" {
+  zone_id = aws_route53_zone.domain_com.zone_id
+  name    = "domain.com"
+  type    = "A"
+
+  alias {
+    name                   = "d111111abcdef8.cloudfront.net"
+    zone_id                = "Z2FDTNDATAQYW2"
+    evaluate_target_health = false
+  }
+}
+
+import {
+  to = aws_route53_record.domain_com_a
+  id = "Z1_domain.com_A"
+}
+
+resource "aws_route53_record" "www_domain_com_a" {
+  zone_id = aws_route53_zone.domain_com.zone_id
+  name    = "www.domain.com"
+  type    = "A"
+
+  alias {
+    name                   = "domain.com"
+    zone_id                = aws_route53_zone.domain_com.zone_id
+    evaluate_target_health = true
+  }
+}
+
+import {
+  to = aws_route53_record.www_domain_com_a
+  id = "Z1_www.domain.com_A"
+}
+
+resource "aws_route53_record" "wildcard_domain_com_txt" {
+  zone_id = aws_route53_zone.domain_com.zone_id
+  name    = "*.domain.com"
+  type    = "TXT"
+  ttl     = 300
+  records = ["v=spf1 -all", "part one\" \"$${part} two"]
+}
+
+import {
+  to = aws_route53_record.wildcard_domain_com_txt
+  id = "Z1_*.domain.com_TXT"
+}
+
+resource "aws_route53_record" "api_domain_com_a_blue" {
+  zone_id         = aws_route53_zone.domain_com.zone_id
+  name            = "api.domain.com"
+  type            = "A"
+  ttl             = 60
+  records         = ["1.2.3.6"]
+  set_identifier  = "blue"
+  health_check_id = "hc-1"
+
+  weighted_routing_policy {
+    weight = 10
+  }
+}
+
+import {
+  to = aws_route53_record.api_domain_com_a_blue
+  id = "Z1_api.domain.com_A_blue"
+}
+
+resource "aws_route53_record" "geo_domain_com_a_us" {
+  zone_id        = aws_route53_zone.domain_com.zone_id
+  name           = "geo.domain.com"
+  type           = "A"
+  ttl            = 60
+  records        = ["1.2.3.7"]
+  set_identifier = "us"
+
+  geolocation_routing_policy {
+    country     = "US"
+    subdivision = "CA"
+  }
+}
+
+import {
+  to = aws_route53_record.geo_domain_com_a_us
+  id = "Z1_geo.domain.com_A_us"
+}
+
+# record sets not represented in this config:
+# tp.domain.com. A (traffic policy instance instance-1)
+`
+
+	config := r53.Terraform("/hostedzone/Z1", m, records)
+	if config != expected {
+		t.Errorf("\nEXPECTED config: \n%s\n\nGOT config: \n%s\n\n", expected, config)
+	}
+}
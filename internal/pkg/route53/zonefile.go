@@ -0,0 +1,263 @@
+package r53
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// routing policy and alias target annotations preceding a record set
+	routingAnnotation = regexp.MustCompile(`^; routing: (.+)$`)
+	aliasAnnotation   = regexp.MustCompile(`^; alias: (.+)$`)
+	// rows of record sets a strict zonefile keeps as comments
+	commented = regexp.MustCompile(`^; (Route53 alias|Route53 routing policy|CNAME at zone apex|CNAME alongside other data|invalid): (.+)$`)
+)
+
+// continents of a geolocation routing policy, any other single code is a country
+var continents = []string{"AF", "AN", "AS", "EU", "NA", "OC", "SA"}
+
+// ReadZonefile returns a hosted zone out of its zonefile export, annotated or strict, for backups without a JSON or YAML export.
+// Routing policies and alias targets are recovered from record annotations, values are kept as exported.
+// A geolocation of a single code is read as a continent when it is one, which mistakes countries AF, AS and NA for continents.
+func ReadZonefile(name string, private bool, content []byte) (zone.Zone, error) {
+	var records []zone.Record
+	var routing *zone.RoutingPolicy
+	var target *zone.AliasTarget
+
+	// a record set starts on an annotation, a name or a type of its own
+	annotated := false
+
+	for i, row := range strings.Split(string(content), "\n") {
+		row = strings.TrimSpace(row)
+		alias := false
+
+		if m := commented.FindStringSubmatch(row); m != nil {
+			row = m[2]
+			alias = m[1] == "Route53 alias"
+		}
+
+		switch {
+		case row == "", strings.HasPrefix(row, "$"):
+			continue
+		case routingAnnotation.MatchString(row):
+			p, err := readRouting(routingAnnotation.FindStringSubmatch(row)[1])
+			if err != nil {
+				return zone.Zone{}, errors.Wrap(err, fmt.Sprintf("error parsing zonefile export, line %d", i+1))
+			}
+			routing, annotated = p, true
+			continue
+		case aliasAnnotation.MatchString(row):
+			t, err := readTarget(aliasAnnotation.FindStringSubmatch(row)[1])
+			if err != nil {
+				return zone.Zone{}, errors.Wrap(err, fmt.Sprintf("error parsing zonefile export, line %d", i+1))
+			}
+			target, annotated = t, true
+			continue
+		case strings.HasPrefix(row, ";"):
+			continue
+		}
+
+		r, err := readRow(row)
+		if err != nil {
+			return zone.Zone{}, errors.Wrap(err, fmt.Sprintf("error parsing zonefile export, line %d", i+1))
+		}
+
+		r.Alias = alias || target != nil
+		if r.Alias {
+			r.Target = target
+		}
+		r.Routing = routing
+
+		last := len(records) - 1
+		if !annotated && last >= 0 && records[last].Name == r.Name && records[last].Type == r.Type && records[last].Alias == r.Alias {
+			records[last].Value = append(records[last].Value, r.Value...)
+			continue
+		}
+
+		records = append(records, r)
+		routing, target, annotated = nil, nil, false
+	}
+
+	return zone.Zone{
+		Name:     name,
+		Provider: "Route53",
+		Private:  private,
+		Records:  records,
+	}, nil
+}
+
+// readRow returns a record of a tab separated zonefile row, aliases without a TTL of their own have no TTL column
+func readRow(row string) (zone.Record, error) {
+	columns := strings.SplitN(row, "\t", 5)
+	if len(columns) == 4 && columns[1] == "IN" {
+		columns = []string{columns[0], "0", columns[1], columns[2], columns[3]}
+	}
+
+	if len(columns) != 5 || columns[2] != "IN" {
+		return zone.Record{}, fmt.Errorf("unexpected record '%s'", row)
+	}
+
+	ttl, err := strconv.ParseInt(columns[1], 10, 64)
+	if err != nil {
+		return zone.Record{}, errors.Wrap(err, fmt.Sprintf("unexpected TTL of record '%s'", row))
+	}
+
+	return zone.Record{
+		Name:  columns[0],
+		Type:  columns[3],
+		TTL:   ttl,
+		Value: []string{columns[4]},
+	}, nil
+}
+
+// readRouting returns a routing policy out of its 'key=value' annotation
+func readRouting(s string) (*zone.RoutingPolicy, error) {
+	attributes, err := readAttributes(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := zone.RoutingPolicy{}
+
+	for _, a := range attributes {
+		switch a[0] {
+		case "set-identifier":
+			p.SetIdentifier = a[1]
+		case "weight":
+			w, err := strconv.ParseInt(a[1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "unexpected routing weight")
+			}
+			p.Weight = &w
+		case "region":
+			p.Region = a[1]
+		case "geolocation":
+			p.GeoLocation = readGeoLocation(a[1])
+		case "failover":
+			p.Failover = a[1]
+		case "multivalue":
+			p.MultiValueAnswer = a[1] == "true"
+		case "health-check":
+			p.HealthCheckID = a[1]
+		case "health-check-name":
+			p.HealthCheckName = a[1]
+		case "traffic-policy-instance":
+			p.TrafficPolicyInstance = a[1]
+		default:
+			return nil, fmt.Errorf("unexpected routing attribute '%s'", a[0])
+		}
+	}
+
+	return &p, nil
+}
+
+// readGeoLocation returns a geolocation out of its codes separated by '/'
+func readGeoLocation(s string) *zone.GeoLocation {
+	codes := strings.Split(s, "/")
+
+	switch {
+	case len(codes) > 1:
+		return &zone.GeoLocation{CountryCode: codes[0], SubdivisionCode: codes[1]}
+	case contains(continents, codes[0]):
+		return &zone.GeoLocation{ContinentCode: codes[0]}
+	}
+
+	return &zone.GeoLocation{CountryCode: codes[0]}
+}
+
+// readTarget returns an alias target out of its 'key=value' annotation
+func readTarget(s string) (*zone.AliasTarget, error) {
+	attributes, err := readAttributes(s)
+	if err != nil {
+		return nil, err
+	}
+
+	t := zone.AliasTarget{}
+
+	for _, a := range attributes {
+		switch a[0] {
+		case "service":
+			t.Service = a[1]
+		case "hosted-zone":
+			t.HostedZoneID = a[1]
+		case "evaluate-target-health":
+			t.EvaluateTargetHealth = a[1] == "true"
+		default:
+			return nil, fmt.Errorf("unexpected alias attribute '%s'", a[0])
+		}
+	}
+
+	return &t, nil
+}
+
+// readAttributes returns 'key=value' pairs separated by spaces in their order, quoted values are unquoted
+func readAttributes(s string) ([][2]string, error) {
+	var attributes [][2]string
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		eq := strings.Index(s, "=")
+		if eq < 1 {
+			return nil, fmt.Errorf("unexpected attribute '%s'", s)
+		}
+
+		key, rest := s[:eq], s[eq+1:]
+
+		end := strings.Index(rest, " ")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		// a quoted value ends at its closing quote, spaces included
+		if strings.HasPrefix(rest, `"`) {
+			end = closing(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated value of attribute '%s'", key)
+			}
+		}
+
+		value := rest[:end]
+		if strings.HasPrefix(value, `"`) {
+			v, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("unexpected value of attribute '%s'", key))
+			}
+			value = v
+		}
+
+		attributes = append(attributes, [2]string{key, value})
+		s = rest[end:]
+	}
+
+	return attributes, nil
+}
+
+// closing returns an end of a quoted string at the start of 's', -1 when it is not terminated
+func closing(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// contains reports whether a list contains a value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
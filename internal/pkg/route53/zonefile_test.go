@@ -0,0 +1,69 @@
+package r53_test
+
+import (
+	"reflect"
+	"testing"
+
+	r53 "dns-exporter/internal/pkg/route53"
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestReadZonefile(t *testing.T) {
+	blue, green := int64(10), int64(90)
+
+	export := zone.Zone{
+		Name:     "domain.com.",
+		ID:       "/hostedzone/Z2",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 900, Value: []string{"ns-1.awsdns-01.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}},
+			{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org.", "ns-2.awsdns-02.net."}},
+			{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4", "1.2.3.5"}},
+			{Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`, `"say \"hi\""`}},
+			{Name: "www.domain.com.", Type: "A", Value: []string{"domain.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2"}},
+			{Name: "cdn.domain.com.", Type: "A", TTL: 60, Value: []string{"d111111abcdef8.cloudfront.net."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2FDTNDATAQYW2", Service: "CloudFront", EvaluateTargetHealth: true}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &blue, HealthCheckID: "hc-1", HealthCheckName: "HTTPS api.domain.com:443/health"}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.7", "1.2.3.8"}, Routing: &zone.RoutingPolicy{SetIdentifier: "green", Weight: &green}},
+			{Name: "eu.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.9"}, Routing: &zone.RoutingPolicy{SetIdentifier: "eu", GeoLocation: &zone.GeoLocation{ContinentCode: "EU"}}},
+			{Name: "eu.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.10"}, Routing: &zone.RoutingPolicy{SetIdentifier: "ca", GeoLocation: &zone.GeoLocation{CountryCode: "US", SubdivisionCode: "CA"}}},
+			{Name: "tp.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.11"}, Routing: &zone.RoutingPolicy{TrafficPolicyInstance: "instance-1 (geo-web v2)"}},
+		},
+	}
+
+	for _, mode := range []zone.Mode{zone.Annotated, zone.Strict} {
+		content, err := export.Zonefile(mode)
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
+
+		z, err := r53.ReadZonefile("domain.com.", false, content.Bytes())
+		if err != nil {
+			t.Fatalf("%s: \nEXPECTED error: \n<nil>\n\nGOT error: %v", mode, err)
+		}
+
+		if z.Name != "domain.com." || z.Provider != "Route53" || len(z.Records) != len(export.Records) {
+			t.Errorf("%s: \nEXPECTED zone: \n%+v\n\nGOT zone: \n%+v\n\n", mode, export, z)
+		}
+
+		// every record set is read back as exported, in the order of a zonefile
+		for _, expected := range export.Records {
+			found := false
+			for _, r := range z.Records {
+				if reflect.DeepEqual(expected, r) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				t.Errorf("%s: \nEXPECTED record: \n%+v\n\nGOT records: \n%+v\n\n", mode, expected, z.Records)
+			}
+		}
+	}
+
+	// rows that are not records fail the zone
+	_, err := r53.ReadZonefile("domain.com.", false, []byte("domain.com.\tIN\n"))
+	if err == nil {
+		t.Error("\nEXPECTED error: \nunexpected record 'domain.com.\tIN'\n\nGOT error: \n<nil>")
+	}
+}
@@ -13,8 +13,12 @@ import (
 
 // WriteToFile creates/writes content to file
 func WriteToFile(domain string, content string, dir string, fs afero.Fs) (string, error) {
-	// format filename
-	file := fmt.Sprintf("%s/%s.txt", path.Clean(dir), strings.Replace(strings.TrimSuffix(domain, "."), ".", "-", 1))
+	return WriteToFileAs(domain, "txt", content, dir, fs)
+}
+
+// WriteToFileAs creates/writes content to file with a custom extension
+func WriteToFileAs(domain string, extension string, content string, dir string, fs afero.Fs) (string, error) {
+	file := fmt.Sprintf("%s/%s", path.Clean(dir), FileName(domain, extension))
 
 	// create file
 	out, err := fs.Create(file)
@@ -32,6 +36,11 @@ func WriteToFile(domain string, content string, dir string, fs afero.Fs) (string
 	return file, nil
 }
 
+// FileName returns a name of a file that a zone export of a domain is written to
+func FileName(domain string, extension string) string {
+	return fmt.Sprintf("%s.%s", strings.Replace(strings.TrimSuffix(domain, "."), ".", "-", 1), extension)
+}
+
 // ValidateDir validates a directory exists, 'create=true' will force the creation.
 func ValidateDir(dir string, create bool, fs afero.Fs) (bool, error) {
 	b, err := afero.DirExists(fs, dir)
@@ -35,6 +35,28 @@ func TestWriteToFile(t *testing.T) {
 	}
 }
 
+func TestWriteToFileAs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	filename, err := utils.WriteToFileAs("domain.com.", "meta.json", "{}", "./", fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if filename != "./domain-com.meta.json" && filename != "domain-com.meta.json" {
+		t.Errorf("\nEXPECTED filename: \ndomain-com.meta.json\n\nGOT filename: \n%+v\n\n", filename)
+	}
+
+	result, err := afero.ReadFile(fs, filename)
+	if err != nil {
+		t.Fatal("error reading exported file:", err)
+	}
+
+	if !reflect.DeepEqual("{}", string(result)) {
+		t.Errorf("\nEXPECTED content: \n{}\n\nGOT content: \n%+v\n\n", string(result))
+	}
+}
+
 func TestValidateDir(t *testing.T) {
 	fs := afero.NewMemMapFs()
 
@@ -0,0 +1,23 @@
+package zone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns alias target attributes in a 'key=value' form
+func (a *AliasTarget) String() string {
+	var s []string
+
+	if a.Service != "" {
+		s = append(s, fmt.Sprintf("service=%q", a.Service))
+	}
+
+	if a.HostedZoneID != "" {
+		s = append(s, fmt.Sprintf("hosted-zone=%s", a.HostedZoneID))
+	}
+
+	s = append(s, fmt.Sprintf("evaluate-target-health=%v", a.EvaluateTargetHealth))
+
+	return strings.Join(s, " ")
+}
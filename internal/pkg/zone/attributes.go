@@ -0,0 +1,33 @@
+package zone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// attributes returns provider specific record attributes in a 'key=value' form
+func (r *Record) attributes() string {
+	var s []string
+
+	if r.Proxied != nil {
+		s = append(s, fmt.Sprintf("proxied=%v", *r.Proxied))
+	}
+
+	if r.AutoTTL {
+		s = append(s, "ttl=auto")
+	}
+
+	if len(r.Tags) > 0 {
+		s = append(s, fmt.Sprintf("tags=%q", strings.Join(r.Tags, ",")))
+	}
+
+	return strings.Join(s, " ")
+}
+
+// newlines of a comment, which would otherwise end a zonefile comment and start a record line
+var newlines = strings.NewReplacer("\r\n", `\n`, "\r", `\n`, "\n", `\n`)
+
+// comment returns a record comment on a single line, newlines are written as '\n'
+func comment(s string) string {
+	return newlines.Replace(s)
+}
@@ -0,0 +1,201 @@
+package zone
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// ConvertToDNSControl returns DNSControl 'dnsconfig.js' fragment of a zone.
+// Route53 aliases become 'R53_ALIAS' records and CloudFlare proxied status becomes 'CF_PROXY_ON' / 'CF_PROXY_OFF' modifiers,
+// record sets that cannot be represented (routing policies, disabled and unsupported records) are kept as comments.
+// Registrar 'REG_NONE' and DNS provider 'DSP_<PROVIDER>' are expected to be declared by a config that includes the fragment.
+func (z *Zone) ConvertToDNSControl() (string, error) {
+	origin := dns.Fqdn(z.Name)
+
+	var lines []string
+	var skipped []string
+
+	s := z.Sorted()
+	for _, r := range s.Records {
+		if r.Type == "SOA" {
+			continue
+		}
+
+		records, reason := r.dnscontrol(origin)
+		if reason != "" {
+			skipped = append(skipped, fmt.Sprintf("\t// %s %s (%s)", r.Name, r.Type, reason))
+			continue
+		}
+
+		if r.Comment != "" {
+			lines = append(lines, fmt.Sprintf("\t// %s", scriptComment(r.Comment)))
+		}
+
+		lines = append(lines, records...)
+	}
+
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("// DNSControl config of %s zone exported from %s\n", origin, z.Provider))
+	b.WriteString(fmt.Sprintf("D(%s, REG_NONE, DnsProvider(%s),\n", strconv.Quote(strings.TrimSuffix(origin, ".")), dsp(z.Provider)))
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t//") {
+			b.WriteString(line + "\n")
+			continue
+		}
+
+		b.WriteString(line + ",\n")
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("\t// record sets not represented in this config:\n")
+		b.WriteString(strings.Join(skipped, "\n") + "\n")
+	}
+
+	b.WriteString("END);\n")
+
+	return b.String(), nil
+}
+
+// terminators of JavaScript lines besides newlines, which would otherwise end a comment and start a statement
+var terminators = strings.NewReplacer("\u2028", `\u2028`, "\u2029", `\u2029`)
+
+// scriptComment returns a record comment on a single line of 'dnsconfig.js'
+func scriptComment(s string) string {
+	return terminators.Replace(comment(s))
+}
+
+// dnscontrol returns DNSControl records of a record set, or a reason it cannot be represented
+func (r *Record) dnscontrol(origin string) ([]string, string) {
+	switch {
+	case r.Routing != nil && r.Routing.SetIdentifier != "":
+		return nil, fmt.Sprintf("routing policy: %s", r.Routing)
+	case r.Disabled:
+		return nil, "disabled"
+	}
+
+	name := strconv.Quote(label(r.Name, origin))
+
+	if r.Alias {
+		if len(r.Value) == 0 {
+			return nil, "alias without a target"
+		}
+
+		args := []string{name, strconv.Quote(r.Type), strconv.Quote(dns.Fqdn(r.Value[0]))}
+		if r.Target != nil {
+			if r.Target.HostedZoneID != "" && r.Target.Service != "Route53" {
+				args = append(args, fmt.Sprintf("R53_ZONE(%s)", strconv.Quote(r.Target.HostedZoneID)))
+			}
+
+			args = append(args, fmt.Sprintf("R53_EVALUATE_TARGET_HEALTH(%v)", r.Target.EvaluateTargetHealth))
+		}
+
+		return []string{fmt.Sprintf("\tR53_ALIAS(%s)", strings.Join(args, ", "))}, ""
+	}
+
+	modifiers := []string{fmt.Sprintf("TTL(%v)", r.TTL)}
+	if r.Proxied != nil {
+		if *r.Proxied {
+			modifiers = append(modifiers, "CF_PROXY_ON")
+		} else {
+			modifiers = append(modifiers, "CF_PROXY_OFF")
+		}
+	}
+
+	var records []string
+	for _, v := range r.Value {
+		rr, err := dns.NewRR(fmt.Sprintf("%s %v IN %s %s", dns.Fqdn(r.Name), r.TTL, r.Type, v))
+		if err != nil || rr == nil {
+			return nil, "invalid value"
+		}
+
+		// nameservers of a zone apex are managed by a provider
+		if ns, ok := rr.(*dns.NS); ok && dns.Fqdn(r.Name) == origin {
+			records = append(records, fmt.Sprintf("\tNAMESERVER(%s)", strconv.Quote(strings.TrimSuffix(ns.Ns, "."))))
+			continue
+		}
+
+		args := dnscontrolArgs(rr)
+		if args == nil {
+			return nil, "unsupported type"
+		}
+
+		args = append(append([]string{name}, args...), modifiers...)
+		records = append(records, fmt.Sprintf("\t%s(%s)", r.Type, strings.Join(args, ", ")))
+	}
+
+	return records, ""
+}
+
+// dnscontrolArgs returns DNSControl arguments of a resource record, following a record name, nil for unsupported types
+func dnscontrolArgs(rr dns.RR) []string {
+	q := strconv.Quote
+
+	switch v := rr.(type) {
+	case *dns.A:
+		return []string{q(v.A.String())}
+	case *dns.AAAA:
+		return []string{q(v.AAAA.String())}
+	case *dns.CNAME:
+		return []string{q(v.Target)}
+	case *dns.NS:
+		return []string{q(v.Ns)}
+	case *dns.PTR:
+		return []string{q(v.Ptr)}
+	case *dns.TXT:
+		return []string{q(txt(v.Txt))}
+	case *dns.MX:
+		return []string{strconv.Itoa(int(v.Preference)), q(v.Mx)}
+	case *dns.SRV:
+		return []string{strconv.Itoa(int(v.Priority)), strconv.Itoa(int(v.Weight)), strconv.Itoa(int(v.Port)), q(v.Target)}
+	case *dns.CAA:
+		args := []string{q(v.Tag), q(v.Value)}
+		if v.Flag&128 != 0 {
+			args = append(args, "CAA_CRITICAL")
+		}
+		return args
+	case *dns.NAPTR:
+		return []string{strconv.Itoa(int(v.Order)), strconv.Itoa(int(v.Preference)), q(v.Flags), q(v.Service), q(v.Regexp), q(v.Replacement)}
+	case *dns.SSHFP:
+		return []string{strconv.Itoa(int(v.Algorithm)), strconv.Itoa(int(v.Type)), q(v.FingerPrint)}
+	case *dns.TLSA:
+		return []string{strconv.Itoa(int(v.Usage)), strconv.Itoa(int(v.Selector)), strconv.Itoa(int(v.MatchingType)), q(v.Certificate)}
+	case *dns.DS:
+		return []string{strconv.Itoa(int(v.KeyTag)), strconv.Itoa(int(v.Algorithm)), strconv.Itoa(int(v.DigestType)), q(v.Digest)}
+	case *dns.HTTPS:
+		return svcbArgs(&v.SVCB)
+	case *dns.SVCB:
+		return svcbArgs(v)
+	}
+
+	return nil
+}
+
+// svcbArgs returns DNSControl arguments of SVCB and HTTPS records
+func svcbArgs(v *dns.SVCB) []string {
+	var params []string
+	for _, p := range v.Value {
+		params = append(params, fmt.Sprintf("%s=%s", p.Key(), p.String()))
+	}
+
+	return []string{strconv.Itoa(int(v.Priority)), strconv.Quote(v.Target), strconv.Quote(strings.Join(params, " "))}
+}
+
+// dsp returns a DNSControl DNS provider variable of an exporting provider
+func dsp(provider string) string {
+	return "DSP_" + strings.ToUpper(regexp.MustCompile(`[^A-Za-z0-9]+`).ReplaceAllString(provider, "_"))
+}
+
+// label returns a DNSControl record name, '@' for the apex
+func label(name, origin string) string {
+	l := relative(name, origin)
+	if l == "" {
+		return "@"
+	}
+
+	return l
+}
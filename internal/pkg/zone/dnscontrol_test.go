@@ -0,0 +1,104 @@
+package zone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+func TestConvertToDNSControl(t *testing.T) {
+	z := iacZone()
+
+	expected := `// DNSControl config of domain.com. zone exported from Route53
+D("domain.com", REG_NONE, DnsProvider(DSP_ROUTE53),
+	// catch all
+	CNAME("*", "domain.com.", TTL(1), CF_PROXY_ON),
+	TXT("_dmarc", "v=DMARC1; p=reject; say \"hi\"", TTL(300)),
+	R53_ALIAS("@", "A", "d111111abcdef8.cloudfront.net.", R53_ZONE("Z2FDTNDATAQYW2"), R53_EVALUATE_TARGET_HEALTH(false)),
+	CAA("@", "issue", "letsencrypt.org", CAA_CRITICAL, TTL(300)),
+	MX("@", 10, "mail.domain.com.", TTL(300)),
+	MX("@", 20, "mail2.domain.com.", TTL(300)),
+	NAMESERVER("ns-1.awsdns-01.org"),
+	NAMESERVER("ns-2.awsdns-02.com"),
+	R53_ALIAS("www", "A", "domain.com.", R53_EVALUATE_TARGET_HEALTH(true)),
+	// record sets not represented in this config:
+	// api.domain.com. A (routing policy: set-identifier="blue" weight=10)
+	// domain.com. SPF (unsupported type)
+END);
+`
+
+	content, err := z.ConvertToDNSControl()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, content) {
+		t.Errorf("\nEXPECTED config: \n%s\n\nGOT config: \n%s\n\n", expected, content)
+	}
+}
+
+func TestConvertToDNSControlComment(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{
+				Name:    "www.domain.com.",
+				Type:    "A",
+				TTL:     300,
+				Value:   []string{"1.2.3.4"},
+				Comment: "first line\r\n\tA(\"evil\", \"6.6.6.6\"),\u2028A(\"evil\", \"6.6.6.7\"),",
+			},
+		},
+	}
+
+	content, err := z.ConvertToDNSControl()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// test: a comment does not end a JavaScript line
+	for _, line := range strings.FieldsFunc(content, func(r rune) bool { return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029' }) {
+		if strings.Contains(line, "evil") && !strings.HasPrefix(line, "\t//") {
+			t.Errorf("\nEXPECTED comment: \nkept on a single line\n\nGOT config: \n%s\n\n", content)
+		}
+	}
+}
+
+func TestWriteDNSAsCode(t *testing.T) {
+	defer func() {
+		zone.Formats = []zone.Format{zone.ZonefileFormat}
+	}()
+
+	zone.Formats = []zone.Format{zone.OctoDNSFormat, zone.DNSControlFormat}
+
+	z := iacZone()
+	fs := afero.NewMemMapFs()
+
+	err := z.Write("./Route53/Public", zone.Annotated, fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// zones of the same name written under file names of their own
+	err = z.WriteAs("domain-com-staging", "./Route53/Public", zone.Annotated, fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for _, file := range []string{
+		"./Route53/Public/octodns/domain.com.yaml",
+		"./Route53/Public/dnscontrol/domain.com.js",
+		"./Route53/Public/octodns/domain-com-staging/domain.com.yaml",
+		"./Route53/Public/dnscontrol/domain-com-staging/domain.com.js",
+	} {
+		ok, err := afero.Exists(fs, file)
+		if err != nil || !ok {
+			t.Errorf("\nEXPECTED file: \n%s\n\nGOT error: \n%v\n\n", file, err)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+package zone
+
+var FormatRecords = format
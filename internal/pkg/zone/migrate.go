@@ -0,0 +1,302 @@
+package zone
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// CloudFlare TTL bounds, TTL of 1 is automatic
+const (
+	minCloudFlareTTL = 60
+	maxCloudFlareTTL = 86400
+)
+
+// autoTTL is a TTL CloudFlare serves records of automatic TTL with
+const autoTTL = 300
+
+// route53Types are record types Route53 hosts
+var route53Types = []string{"A", "AAAA", "CAA", "CNAME", "DS", "HTTPS", "MX", "NAPTR", "NS", "PTR", "SPF", "SRV", "SSHFP", "SVCB", "TLSA", "TXT"}
+
+// Migrate returns a zone translated for another provider and a report of record sets that were changed or dropped on the way.
+// SOA and apex NS record sets belong to a provider hosting a zone and are never migrated.
+func (z *Zone) Migrate(provider string) (Zone, []string, error) {
+	if z.Provider == provider {
+		return Zone{}, nil, fmt.Errorf("zone '%s' is already hosted by %s", z.Name, provider)
+	}
+
+	switch provider {
+	case "CloudFlare":
+		if z.Private {
+			return Zone{}, nil, fmt.Errorf("private zone '%s' cannot be migrated to CloudFlare", z.Name)
+		}
+
+		records, report := z.toCloudFlare()
+		return Zone{Name: z.Name, Provider: provider, Records: records}, report, nil
+	case "Route53":
+		records, report := z.toRoute53()
+		return Zone{Name: z.Name, Provider: provider, Private: z.Private, Records: records}, report, nil
+	}
+
+	return Zone{}, nil, fmt.Errorf("migration to %s is not supported", provider)
+}
+
+// toCloudFlare returns a record per value of every record set.
+// Aliases of A, AAAA and CNAME types become CNAME records, flattened by CloudFlare at the apex,
+// aliases of other types are resolved to values of the record set they point at.
+func (z *Zone) toCloudFlare() ([]Record, []string) {
+	origin := strings.ToLower(dns.Fqdn(z.Name))
+	s := z.Sorted()
+
+	// record sets of a name, to resolve aliases and to find names a CNAME cannot share
+	sets := make(map[string][]Record)
+	for _, r := range s.Records {
+		name := strings.ToLower(r.Name)
+		sets[name] = append(sets[name], r)
+	}
+
+	var records []Record
+	var report []string
+	cnames := make(map[string]bool)
+
+	for _, r := range s.Records {
+		name := strings.ToLower(r.Name)
+
+		if r.Type == "SOA" || (r.Type == "NS" && name == origin) {
+			continue
+		}
+
+		if reason := r.untranslatable(); reason != "" {
+			report = append(report, fmt.Sprintf("%s %s (%s)", r.Name, r.Type, reason))
+			continue
+		}
+
+		if r.Routing != nil && r.Routing.HealthCheckID != "" {
+			report = append(report, fmt.Sprintf("%s %s (health check %s dropped)", r.Name, r.Type, r.Routing.HealthCheckID))
+		}
+
+		if r.Alias {
+			if len(r.Value) == 0 {
+				report = append(report, fmt.Sprintf("%s %s (alias without a target)", r.Name, r.Type))
+				continue
+			}
+
+			switch r.Type {
+			case "A", "AAAA", "CNAME":
+				// A and AAAA aliases of a name share a single CNAME
+				if cnames[name] {
+					continue
+				}
+
+				if name != origin && shared(sets[name]) {
+					report = append(report, fmt.Sprintf("%s %s (alias to %s cannot become a CNAME next to other records of the name)", r.Name, r.Type, r.Value[0]))
+					continue
+				}
+
+				cnames[name] = true
+				records = append(records, Record{Name: decode(r.Name), Type: "CNAME", TTL: 1, AutoTTL: true, Value: []string{dns.Fqdn(r.Value[0])}})
+			default:
+				values := resolve(sets[strings.ToLower(dns.Fqdn(r.Value[0]))], r.Type)
+				if values == nil {
+					report = append(report, fmt.Sprintf("%s %s (alias to %s does not resolve to a record set of the zone)", r.Name, r.Type, r.Value[0]))
+					continue
+				}
+
+				for _, v := range values {
+					records = append(records, Record{Name: decode(r.Name), Type: r.Type, TTL: 1, AutoTTL: true, Value: []string{v}})
+				}
+			}
+
+			continue
+		}
+
+		ttl := r.TTL
+		if ttl != 1 && ttl < minCloudFlareTTL {
+			ttl = minCloudFlareTTL
+		}
+		if ttl > maxCloudFlareTTL {
+			ttl = maxCloudFlareTTL
+		}
+		if ttl != r.TTL {
+			report = append(report, fmt.Sprintf("%s %s (TTL %d is out of CloudFlare bounds, set to %d)", r.Name, r.Type, r.TTL, ttl))
+		}
+
+		for _, v := range r.Value {
+			records = append(records, Record{Name: decode(r.Name), Type: r.Type, TTL: ttl, AutoTTL: ttl == 1, Value: []string{v}, Comment: r.Comment, Tags: r.Tags})
+		}
+	}
+
+	return records, report
+}
+
+// toRoute53 returns record sets merged out of records of the same name and type.
+// Apex CNAME records, flattened by CloudFlare, become aliases when they point at a name of the zone.
+func (z *Zone) toRoute53() ([]Record, []string) {
+	origin := strings.ToLower(dns.Fqdn(z.Name))
+	s := z.Sorted()
+
+	var sets []Record
+	var report []string
+	index := make(map[string]int)
+
+	for _, r := range s.Records {
+		name := strings.ToLower(r.Name)
+
+		if r.Type == "SOA" || (r.Type == "NS" && name == origin) {
+			continue
+		}
+
+		if reason := r.untranslatable(); reason != "" {
+			report = append(report, fmt.Sprintf("%s %s (%s)", r.Name, r.Type, reason))
+			continue
+		}
+
+		if r.Alias {
+			report = append(report, fmt.Sprintf("%s %s (%s alias to %s is not supported by Route53)", r.Name, r.Type, z.Provider, strings.Join(r.Value, " ")))
+			continue
+		}
+
+		if !contains(route53Types, r.Type) {
+			report = append(report, fmt.Sprintf("%s %s (record type is not supported by Route53)", r.Name, r.Type))
+			continue
+		}
+
+		if r.Proxied != nil && *r.Proxied {
+			report = append(report, fmt.Sprintf("%s %s (proxied by %s, migrated as DNS only, exposing %s)", r.Name, r.Type, z.Provider, strings.Join(r.Value, " ")))
+		}
+
+		if r.Comment != "" || len(r.Tags) > 0 {
+			report = append(report, fmt.Sprintf("%s %s (comment and tags dropped)", r.Name, r.Type))
+		}
+
+		ttl := r.TTL
+		if r.AutoTTL || ttl == 1 {
+			ttl = autoTTL
+		}
+
+		var values []string
+		for _, v := range r.Value {
+			if r.Type == "TXT" || r.Type == "SPF" {
+				v = split(v)
+			}
+			values = append(values, v)
+		}
+
+		key := fmt.Sprintf("%s %s", name, r.Type)
+		if i, ok := index[key]; ok {
+			if sets[i].TTL != ttl {
+				report = append(report, fmt.Sprintf("%s %s (records of different TTLs merged into a record set of the lowest TTL)", r.Name, r.Type))
+				if ttl < sets[i].TTL {
+					sets[i].TTL = ttl
+				}
+			}
+
+			sets[i].Value = append(sets[i].Value, values...)
+			continue
+		}
+
+		index[key] = len(sets)
+		sets = append(sets, Record{Name: r.Name, Type: r.Type, TTL: ttl, Value: values})
+	}
+
+	// Route53 has no CNAME at the apex, an alias to a name of the same zone answers the same
+	var records []Record
+	for _, r := range sets {
+		if r.Type != "CNAME" || strings.ToLower(r.Name) != origin {
+			records = append(records, r)
+			continue
+		}
+
+		target := strings.ToLower(dns.Fqdn(r.Value[0]))
+		if !strings.HasSuffix(target, "."+origin) {
+			report = append(report, fmt.Sprintf("%s CNAME (flattened CNAME to %s outside of the zone cannot be an alias)", r.Name, r.Value[0]))
+			continue
+		}
+
+		var aliases []Record
+		for _, t := range []string{"A", "AAAA"} {
+			if _, ok := index[fmt.Sprintf("%s %s", target, t)]; ok {
+				aliases = append(aliases, Record{Name: r.Name, Type: t, Alias: true, Value: []string{target}, Target: &AliasTarget{Service: "Route53"}})
+			}
+		}
+
+		if len(aliases) == 0 {
+			report = append(report, fmt.Sprintf("%s CNAME (flattened CNAME to %s without A or AAAA records cannot be an alias)", r.Name, r.Value[0]))
+			continue
+		}
+
+		records = append(records, aliases...)
+	}
+
+	return records, report
+}
+
+// untranslatable returns a reason a record set cannot be migrated, empty for the ones that can
+func (r *Record) untranslatable() string {
+	switch {
+	case r.Disabled:
+		return "disabled"
+	case r.Routing != nil && r.Routing.SetIdentifier != "":
+		return fmt.Sprintf("routing policy: %s", r.Routing)
+	case r.Routing != nil && r.Routing.TrafficPolicyInstance != "":
+		return fmt.Sprintf("traffic policy instance %s", r.Routing.TrafficPolicyInstance)
+	}
+
+	return ""
+}
+
+// shared reports whether a name has record sets other than A, AAAA and CNAME aliases
+func shared(sets []Record) bool {
+	for _, r := range sets {
+		if !r.Alias || (r.Type != "A" && r.Type != "AAAA" && r.Type != "CNAME") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolve returns sorted values of a simple record set of a type, nil when there is none
+func resolve(sets []Record, t string) []string {
+	for _, r := range sets {
+		if r.Type == t && !r.Alias && r.Routing == nil {
+			values := append([]string{}, r.Value...)
+			sort.Strings(values)
+			return values
+		}
+	}
+
+	return nil
+}
+
+// decode returns a name with octal escapes of Route53 (such as '\052' for a wildcard) decoded
+func decode(name string) string {
+	return regexp.MustCompile(`\\[0-7]{3}`).ReplaceAllStringFunc(name, func(e string) string {
+		c, _ := strconv.ParseUint(e[1:], 8, 8)
+		return string(rune(c))
+	})
+}
+
+// split returns a TXT value of a single character string longer than 255 characters as several character strings
+func split(value string) string {
+	s, err := strconv.Unquote(value)
+	if err != nil || len(s) <= 255 {
+		return value
+	}
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var chunks []string
+	for len(s) > 255 {
+		chunks = append(chunks, `"`+escape.Replace(s[:255])+`"`)
+		s = s[255:]
+	}
+
+	chunks = append(chunks, `"`+escape.Replace(s)+`"`)
+
+	return strings.Join(chunks, " ")
+}
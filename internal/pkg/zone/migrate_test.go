@@ -0,0 +1,130 @@
+package zone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestMigrateToCloudFlare(t *testing.T) {
+	weight := int64(10)
+
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 900, Value: []string{"ns-1.awsdns-01.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}},
+			{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org."}},
+			{Name: "domain.com.", Type: "A", Value: []string{"d111111abcdef8.cloudfront.net."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2FDTNDATAQYW2", Service: "CloudFront"}},
+			{Name: "domain.com.", Type: "AAAA", Value: []string{"d111111abcdef8.cloudfront.net."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2FDTNDATAQYW2", Service: "CloudFront"}},
+			{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}},
+			{Name: "mx.domain.com.", Type: "MX", Value: []string{"domain.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z1", Service: "Route53"}},
+			{Name: `\052.domain.com.`, Type: "CNAME", TTL: 300, Value: []string{"domain.com."}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &weight}},
+			{Name: "shop.domain.com.", Type: "A", Value: []string{"shop-123.eu-west-1.elb.amazonaws.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z32O12XQLNTSW2", Service: "ELB"}},
+			{Name: "shop.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"verification"`}},
+			{Name: "www.domain.com.", Type: "A", TTL: 30, Value: []string{"1.2.3.5", "1.2.3.4"}},
+		},
+	}
+
+	migrated, report, err := z.Migrate("CloudFlare")
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := []zone.Record{
+		{Name: "*.domain.com.", Type: "CNAME", TTL: 300, Value: []string{"domain.com."}},
+		{Name: "domain.com.", Type: "CNAME", TTL: 1, AutoTTL: true, Value: []string{"d111111abcdef8.cloudfront.net."}},
+		{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}},
+		{Name: "mx.domain.com.", Type: "MX", TTL: 1, AutoTTL: true, Value: []string{"10 mail.domain.com."}},
+		{Name: "shop.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"verification"`}},
+		{Name: "www.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.4"}},
+		{Name: "www.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.5"}},
+	}
+
+	if !reflect.DeepEqual(expected, migrated.Records) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, migrated.Records)
+	}
+
+	expectedReport := []string{
+		`api.domain.com. A (routing policy: set-identifier="blue" weight=10)`,
+		"shop.domain.com. A (alias to shop-123.eu-west-1.elb.amazonaws.com. cannot become a CNAME next to other records of the name)",
+		"www.domain.com. A (TTL 30 is out of CloudFlare bounds, set to 60)",
+	}
+
+	if !reflect.DeepEqual(expectedReport, report) {
+		t.Errorf("\nEXPECTED report: \n%s\n\nGOT report: \n%s\n\n", strings.Join(expectedReport, "\n"), strings.Join(report, "\n"))
+	}
+
+	z.Private = true
+
+	_, _, err = z.Migrate("CloudFlare")
+	if err == nil {
+		t.Error("\nEXPECTED error: \nprivate zone 'domain.com.' cannot be migrated to CloudFlare\n\nGOT error: \n<nil>")
+	}
+}
+
+func TestMigrateToRoute53(t *testing.T) {
+	on, off := true, false
+	long := strings.Repeat("a", 300)
+
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 3600, Value: []string{"ns1.domain.com. dns.domain.com. 1 10000 2400 604800 3600"}},
+			{ID: "r1", Name: "domain.com.", Type: "CNAME", TTL: 1, AutoTTL: true, Value: []string{"www.domain.com."}, Proxied: &on},
+			{ID: "r2", Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}, Proxied: &off},
+			{ID: "r3", Name: "domain.com.", Type: "MX", TTL: 600, Value: []string{"20 mail2.domain.com."}, Proxied: &off},
+			{ID: "r4", Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"` + long + `"`}},
+			{ID: "r5", Name: "loc.domain.com.", Type: "LOC", TTL: 300, Value: []string{"52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m"}},
+			{ID: "r6", Name: "www.domain.com.", Type: "A", TTL: 1, AutoTTL: true, Value: []string{"1.2.3.4"}, Proxied: &on, Comment: "web"},
+			{ID: "r7", Name: "www.domain.com.", Type: "A", TTL: 1, AutoTTL: true, Value: []string{"1.2.3.5"}, Proxied: &on},
+		},
+	}
+
+	migrated, report, err := z.Migrate("Route53")
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	expected := []zone.Record{
+		{Name: "domain.com.", Type: "A", Alias: true, Value: []string{"www.domain.com."}, Target: &zone.AliasTarget{Service: "Route53"}},
+		{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com.", "20 mail2.domain.com."}},
+		{Name: "domain.com.", Type: "TXT", TTL: 300, Value: []string{`"` + long[:255] + `" "` + long[255:] + `"`}},
+		{Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4", "1.2.3.5"}},
+	}
+
+	if !reflect.DeepEqual(expected, migrated.Records) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, migrated.Records)
+	}
+
+	expectedReport := []string{
+		"domain.com. CNAME (proxied by CloudFlare, migrated as DNS only, exposing www.domain.com.)",
+		"domain.com. MX (records of different TTLs merged into a record set of the lowest TTL)",
+		"loc.domain.com. LOC (record type is not supported by Route53)",
+		"www.domain.com. A (proxied by CloudFlare, migrated as DNS only, exposing 1.2.3.4)",
+		"www.domain.com. A (comment and tags dropped)",
+		"www.domain.com. A (proxied by CloudFlare, migrated as DNS only, exposing 1.2.3.5)",
+	}
+
+	if !reflect.DeepEqual(expectedReport, report) {
+		t.Errorf("\nEXPECTED report: \n%s\n\nGOT report: \n%s\n\n", strings.Join(expectedReport, "\n"), strings.Join(report, "\n"))
+	}
+
+	// a flattened CNAME outside of the zone has no Route53 counterpart
+	z.Records = []zone.Record{
+		{ID: "r1", Name: "domain.com.", Type: "CNAME", TTL: 300, Value: []string{"app.herokudns.com."}},
+	}
+
+	migrated, report, err = z.Migrate("Route53")
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if len(migrated.Records) != 0 || len(report) != 1 {
+		t.Errorf("\nEXPECTED report: \ndomain.com. CNAME (flattened CNAME to app.herokudns.com. outside of the zone cannot be an alias)\n\nGOT report: \n%s\n\n", strings.Join(report, "\n"))
+	}
+}
@@ -0,0 +1,56 @@
+package zone
+
+// Zone is a provider-neutral representation of a hosted zone
+type Zone struct {
+	Name     string   `json:"name" yaml:"name"`
+	ID       string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Provider string   `json:"provider" yaml:"provider"`
+	Private  bool     `json:"private" yaml:"private"`
+	Records  []Record `json:"records" yaml:"records"`
+}
+
+// Record is a single DNS record set
+type Record struct {
+	// ID of a record at a provider which identifies every record rather than a record set
+	ID       string         `json:"id,omitempty" yaml:"id,omitempty"`
+	Name     string         `json:"name" yaml:"name"`
+	Type     string         `json:"type" yaml:"type"`
+	TTL      int64          `json:"ttl" yaml:"ttl"`
+	Value    []string       `json:"values" yaml:"values"`
+	Alias    bool           `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Proxied  *bool          `json:"proxied,omitempty" yaml:"proxied,omitempty"`
+	AutoTTL  bool           `json:"auto_ttl,omitempty" yaml:"auto_ttl,omitempty"`
+	Disabled bool           `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Comment  string         `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Tags     []string       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Routing  *RoutingPolicy `json:"routing,omitempty" yaml:"routing,omitempty"`
+	Target   *AliasTarget   `json:"alias_target,omitempty" yaml:"alias_target,omitempty"`
+}
+
+// AliasTarget of an alias record
+type AliasTarget struct {
+	HostedZoneID         string `json:"hosted_zone_id,omitempty" yaml:"hosted_zone_id,omitempty"`
+	EvaluateTargetHealth bool   `json:"evaluate_target_health" yaml:"evaluate_target_health"`
+	Service              string `json:"service,omitempty" yaml:"service,omitempty"`
+}
+
+// RoutingPolicy of a record set that answers differently depending on a query or health
+type RoutingPolicy struct {
+	SetIdentifier    string       `json:"set_identifier,omitempty" yaml:"set_identifier,omitempty"`
+	Weight           *int64       `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Region           string       `json:"region,omitempty" yaml:"region,omitempty"`
+	GeoLocation      *GeoLocation `json:"geolocation,omitempty" yaml:"geolocation,omitempty"`
+	Failover         string       `json:"failover,omitempty" yaml:"failover,omitempty"`
+	MultiValueAnswer bool         `json:"multivalue_answer,omitempty" yaml:"multivalue_answer,omitempty"`
+	HealthCheckID    string       `json:"health_check_id,omitempty" yaml:"health_check_id,omitempty"`
+	HealthCheckName  string       `json:"health_check_name,omitempty" yaml:"health_check_name,omitempty"`
+	// TrafficPolicyInstance that manages a record
+	TrafficPolicyInstance string `json:"traffic_policy_instance,omitempty" yaml:"traffic_policy_instance,omitempty"`
+}
+
+// GeoLocation of a geolocation routing policy
+type GeoLocation struct {
+	ContinentCode   string `json:"continent_code,omitempty" yaml:"continent_code,omitempty"`
+	CountryCode     string `json:"country_code,omitempty" yaml:"country_code,omitempty"`
+	SubdivisionCode string `json:"subdivision_code,omitempty" yaml:"subdivision_code,omitempty"`
+}
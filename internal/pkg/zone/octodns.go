@@ -0,0 +1,334 @@
+package zone
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertToOctoDNS returns OctoDNS YAML zone config.
+// Route53 aliases become 'Route53Provider/ALIAS' records and CloudFlare proxied status is kept in 'octodns.cloudflare' extension,
+// record sets that cannot be represented (routing policies, disabled and unsupported records) are listed in a heading comment.
+// Records of the same name and type (CloudFlare returns a record per value) are a single OctoDNS record set,
+// records of a set that differ in TTL or proxied status are listed as a conflict instead.
+func (z *Zone) ConvertToOctoDNS() (string, error) {
+	origin := dns.Fqdn(z.Name)
+	config := make(map[string][]map[string]interface{})
+	conflicts := make(map[string]bool)
+
+	var skipped []string
+
+	s := z.Sorted()
+	for _, r := range s.Records {
+		if r.Type == "SOA" {
+			continue
+		}
+
+		record, reason := r.octodns(origin)
+		if reason != "" {
+			skipped = append(skipped, fmt.Sprintf("# %s %s (%s)", r.Name, r.Type, reason))
+			continue
+		}
+
+		name := relative(r.Name, origin)
+		set := fmt.Sprintf("%s %s", name, record["type"])
+
+		if conflicts[set] {
+			continue
+		}
+
+		i := find(config[name], record["type"])
+		if i < 0 {
+			config[name] = append(config[name], record)
+			continue
+		}
+
+		if !merge(config[name][i], record) {
+			conflicts[set] = true
+			config[name] = append(config[name][:i], config[name][i+1:]...)
+			skipped = append(skipped, fmt.Sprintf("# %s %s (conflicting TTL or proxied status of a record set)", r.Name, r.Type))
+		}
+	}
+
+	// a single record of a name is not wrapped into a list, the way OctoDNS dumps configs
+	out := make(map[string]interface{})
+	for name, records := range config {
+		if len(records) == 0 {
+			continue
+		}
+
+		if len(records) == 1 {
+			out[name] = records[0]
+			continue
+		}
+
+		out[name] = records
+	}
+
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+
+	header := fmt.Sprintf("# OctoDNS config of %s zone exported from %s\n", origin, z.Provider)
+	if len(skipped) > 0 {
+		header += "# record sets not represented in this config:\n" + strings.Join(skipped, "\n") + "\n"
+	}
+
+	return header + "---\n" + string(b), nil
+}
+
+// find returns an index of a record set of a type, -1 when there is none
+func find(records []map[string]interface{}, t interface{}) int {
+	for i, r := range records {
+		if r["type"] == t {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// merge adds values of a record into a record set of the same name and type, unless they differ in TTL or provider extensions
+func merge(set, r map[string]interface{}) bool {
+	if set["ttl"] != r["ttl"] || !reflect.DeepEqual(set["octodns"], r["octodns"]) {
+		return false
+	}
+
+	set["values"] = append(values(set), values(r)...)
+	delete(set, "value")
+
+	return true
+}
+
+// values returns values of an OctoDNS record set
+func values(r map[string]interface{}) []interface{} {
+	if v, ok := r["values"].([]interface{}); ok {
+		return v
+	}
+
+	return []interface{}{r["value"]}
+}
+
+// octodns returns OctoDNS representation of a record set, or a reason it cannot be represented
+func (r *Record) octodns(origin string) (map[string]interface{}, string) {
+	switch {
+	case r.Routing != nil && r.Routing.SetIdentifier != "":
+		return nil, fmt.Sprintf("routing policy: %s", r.Routing)
+	case r.Disabled:
+		return nil, "disabled"
+	}
+
+	record := map[string]interface{}{
+		"type": r.Type,
+		"ttl":  r.TTL,
+	}
+
+	if r.Alias {
+		if len(r.Value) == 0 {
+			return nil, "alias without a target"
+		}
+
+		value := map[string]interface{}{
+			"name": dns.Fqdn(r.Value[0]),
+			"type": r.Type,
+		}
+
+		if r.Target != nil {
+			value["evaluate-target-health"] = r.Target.EvaluateTargetHealth
+
+			// targets of the same hosted zone are referenced by a relative name
+			if r.Target.Service == "Route53" {
+				value["name"] = relative(r.Value[0], origin)
+			} else if r.Target.HostedZoneID != "" {
+				value["hosted-zone-id"] = r.Target.HostedZoneID
+			}
+		}
+
+		return map[string]interface{}{
+			"type":  "Route53Provider/ALIAS",
+			"value": value,
+		}, ""
+	}
+
+	var values []interface{}
+	for _, v := range r.Value {
+		rr, err := dns.NewRR(fmt.Sprintf("%s %v IN %s %s", dns.Fqdn(r.Name), r.TTL, r.Type, v))
+		if err != nil || rr == nil {
+			return nil, "invalid value"
+		}
+
+		value := octodnsValue(rr)
+		if value == nil {
+			return nil, "unsupported type"
+		}
+
+		values = append(values, value)
+	}
+
+	if len(values) == 1 {
+		record["value"] = values[0]
+	} else {
+		record["values"] = values
+	}
+
+	cloudflare := make(map[string]interface{})
+	if r.Proxied != nil {
+		cloudflare["proxied"] = *r.Proxied
+	}
+
+	if r.AutoTTL {
+		cloudflare["auto-ttl"] = true
+	}
+
+	if len(cloudflare) > 0 {
+		record["octodns"] = map[string]interface{}{
+			"cloudflare": cloudflare,
+		}
+	}
+
+	return record, ""
+}
+
+// octodnsValue returns OctoDNS value of a resource record, nil for unsupported types
+func octodnsValue(rr dns.RR) interface{} {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return v.Target
+	case *dns.NS:
+		return v.Ns
+	case *dns.PTR:
+		return v.Ptr
+	case *dns.TXT:
+		return escapeTXT(v.Txt)
+	case *dns.SPF:
+		return escapeTXT(v.Txt)
+	case *dns.MX:
+		return map[string]interface{}{
+			"preference": v.Preference,
+			"exchange":   v.Mx,
+		}
+	case *dns.SRV:
+		return map[string]interface{}{
+			"priority": v.Priority,
+			"weight":   v.Weight,
+			"port":     v.Port,
+			"target":   v.Target,
+		}
+	case *dns.CAA:
+		return map[string]interface{}{
+			"flags": v.Flag,
+			"tag":   v.Tag,
+			"value": v.Value,
+		}
+	case *dns.NAPTR:
+		return map[string]interface{}{
+			"order":       v.Order,
+			"preference":  v.Preference,
+			"flags":       v.Flags,
+			"service":     v.Service,
+			"regexp":      v.Regexp,
+			"replacement": v.Replacement,
+		}
+	case *dns.SSHFP:
+		return map[string]interface{}{
+			"algorithm":        v.Algorithm,
+			"fingerprint_type": v.Type,
+			"fingerprint":      v.FingerPrint,
+		}
+	case *dns.TLSA:
+		return map[string]interface{}{
+			"certificate_usage":            v.Usage,
+			"selector":                     v.Selector,
+			"matching_type":                v.MatchingType,
+			"certificate_association_data": v.Certificate,
+		}
+	case *dns.DS:
+		return map[string]interface{}{
+			"key_tag":     v.KeyTag,
+			"algorithm":   v.Algorithm,
+			"digest_type": v.DigestType,
+			"digest":      v.Digest,
+		}
+	case *dns.HTTPS:
+		return svcb(&v.SVCB)
+	case *dns.SVCB:
+		return svcb(v)
+	}
+
+	return nil
+}
+
+// svcb returns OctoDNS value of SVCB and HTTPS records
+func svcb(v *dns.SVCB) map[string]interface{} {
+	params := make(map[string]interface{})
+	for _, p := range v.Value {
+		switch p.Key() {
+		case dns.SVCB_ALPN, dns.SVCB_MANDATORY:
+			params[p.Key().String()] = strings.Split(p.String(), ",")
+		default:
+			params[p.Key().String()] = p.String()
+		}
+	}
+
+	return map[string]interface{}{
+		"svcpriority": v.Priority,
+		"targetname":  v.Target,
+		"svcparams":   params,
+	}
+}
+
+// escapeTXT returns a single TXT value out of its character strings, OctoDNS requires semicolons to be escaped
+func escapeTXT(chunks []string) string {
+	return strings.ReplaceAll(txt(chunks), ";", `\;`)
+}
+
+// txt returns a single TXT value out of its character strings, decoding escapes of a zonefile presentation format
+func txt(chunks []string) string {
+	b := strings.Builder{}
+
+	for _, c := range chunks {
+		for i := 0; i < len(c); i++ {
+			if c[i] != '\\' || i+1 == len(c) {
+				b.WriteByte(c[i])
+				continue
+			}
+
+			// '\DDD' is a decimal code of a byte, any other escaped character is taken as is
+			if i+3 < len(c) {
+				if n, err := strconv.Atoi(c[i+1 : i+4]); err == nil && n < 256 {
+					b.WriteByte(byte(n))
+					i += 3
+					continue
+				}
+			}
+
+			b.WriteByte(c[i+1])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// relative returns a record name relative to a zone origin, empty for the apex
+func relative(name, origin string) string {
+	name = strings.ReplaceAll(dns.Fqdn(name), `\052`, "*")
+
+	switch {
+	case name == origin:
+		return ""
+	case strings.HasSuffix(name, "."+origin):
+		return strings.TrimSuffix(name, "."+origin)
+	}
+
+	return name
+}
@@ -0,0 +1,144 @@
+package zone_test
+
+import (
+	"reflect"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestConvertToOctoDNS(t *testing.T) {
+	z := iacZone()
+
+	expected := `# OctoDNS config of domain.com. zone exported from Route53
+# record sets not represented in this config:
+# api.domain.com. A (routing policy: set-identifier="blue" weight=10)
+---
+"":
+    - type: Route53Provider/ALIAS
+      value:
+        evaluate-target-health: false
+        hosted-zone-id: Z2FDTNDATAQYW2
+        name: d111111abcdef8.cloudfront.net.
+        type: A
+    - ttl: 300
+      type: CAA
+      value:
+        flags: 128
+        tag: issue
+        value: letsencrypt.org
+    - ttl: 300
+      type: MX
+      values:
+        - exchange: mail.domain.com.
+          preference: 10
+        - exchange: mail2.domain.com.
+          preference: 20
+    - ttl: 172800
+      type: NS
+      values:
+        - ns-1.awsdns-01.org.
+        - ns-2.awsdns-02.com.
+    - ttl: 300
+      type: SPF
+      value: v=spf1 -all
+'*':
+    octodns:
+        cloudflare:
+            auto-ttl: true
+            proxied: true
+    ttl: 1
+    type: CNAME
+    value: domain.com.
+_dmarc:
+    ttl: 300
+    type: TXT
+    value: v=DMARC1\; p=reject\; say "hi"
+www:
+    type: Route53Provider/ALIAS
+    value:
+        evaluate-target-health: true
+        name: ""
+        type: A
+`
+
+	content, err := z.ConvertToOctoDNS()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, content) {
+		t.Errorf("\nEXPECTED config: \n%s\n\nGOT config: \n%s\n\n", expected, content)
+	}
+}
+
+func TestConvertToOctoDNSRecordPerValue(t *testing.T) {
+	proxied, direct := true, false
+
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{ID: "1", Name: "www.domain.com", Type: "A", TTL: 1, AutoTTL: true, Proxied: &proxied, Value: []string{"1.2.3.5"}},
+			{ID: "2", Name: "www.domain.com", Type: "A", TTL: 1, AutoTTL: true, Proxied: &proxied, Value: []string{"1.2.3.4"}},
+			{ID: "3", Name: "www.domain.com", Type: "TXT", TTL: 300, Value: []string{`"verification"`}},
+			{ID: "4", Name: "api.domain.com", Type: "A", TTL: 1, AutoTTL: true, Proxied: &proxied, Value: []string{"1.2.3.6"}},
+			{ID: "5", Name: "api.domain.com", Type: "A", TTL: 1, AutoTTL: true, Proxied: &direct, Value: []string{"1.2.3.7"}},
+			{ID: "6", Name: "mail.domain.com", Type: "A", TTL: 300, Proxied: &direct, Value: []string{"1.2.3.8"}},
+			{ID: "7", Name: "mail.domain.com", Type: "A", TTL: 600, Proxied: &direct, Value: []string{"1.2.3.9"}},
+			{ID: "8", Name: "mail.domain.com", Type: "A", TTL: 300, Proxied: &direct, Value: []string{"1.2.3.10"}},
+		},
+	}
+
+	expected := `# OctoDNS config of domain.com. zone exported from CloudFlare
+# record sets not represented in this config:
+# api.domain.com A (conflicting TTL or proxied status of a record set)
+# mail.domain.com A (conflicting TTL or proxied status of a record set)
+---
+www:
+    - octodns:
+        cloudflare:
+            auto-ttl: true
+            proxied: true
+      ttl: 1
+      type: A
+      values:
+        - 1.2.3.4
+        - 1.2.3.5
+    - ttl: 300
+      type: TXT
+      value: verification
+`
+
+	content, err := z.ConvertToOctoDNS()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, content) {
+		t.Errorf("\nEXPECTED config: \n%s\n\nGOT config: \n%s\n\n", expected, content)
+	}
+}
+
+// iacZone returns a zone with provider-specific records of DNS-as-code exports
+func iacZone() zone.Zone {
+	weight := int64(10)
+	proxied := true
+
+	return zone.Zone{
+		Name:     "domain.com",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "SOA", TTL: 900, Value: []string{"ns-1.awsdns-01.org. hostmaster.domain.com. 1 7200 900 1209600 86400"}},
+			{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org.", "ns-2.awsdns-02.com."}},
+			{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com.", "20 mail2.domain.com."}},
+			{Name: "domain.com.", Type: "A", Value: []string{"d111111abcdef8.cloudfront.net."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z2FDTNDATAQYW2", Service: "CloudFront"}},
+			{Name: "www.domain.com.", Type: "A", Value: []string{"domain.com."}, Alias: true, Target: &zone.AliasTarget{HostedZoneID: "Z1", EvaluateTargetHealth: true, Service: "Route53"}},
+			{Name: `\052.domain.com.`, Type: "CNAME", TTL: 1, Value: []string{"domain.com."}, Proxied: &proxied, AutoTTL: true, Comment: "catch all"},
+			{Name: "_dmarc.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=DMARC1; p=reject" "; say \"hi\""`}},
+			{Name: "domain.com.", Type: "CAA", TTL: 300, Value: []string{`128 issue "letsencrypt.org"`}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &weight}},
+			{Name: "domain.com.", Type: "SPF", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package zone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns routing policy attributes in a 'key=value' form
+func (p *RoutingPolicy) String() string {
+	var s []string
+
+	if p.SetIdentifier != "" {
+		s = append(s, fmt.Sprintf("set-identifier=%q", p.SetIdentifier))
+	}
+
+	if p.Weight != nil {
+		s = append(s, fmt.Sprintf("weight=%v", *p.Weight))
+	}
+
+	if p.Region != "" {
+		s = append(s, fmt.Sprintf("region=%s", p.Region))
+	}
+
+	if p.GeoLocation != nil {
+		s = append(s, fmt.Sprintf("geolocation=%s", p.GeoLocation))
+	}
+
+	if p.Failover != "" {
+		s = append(s, fmt.Sprintf("failover=%s", p.Failover))
+	}
+
+	if p.MultiValueAnswer {
+		s = append(s, "multivalue=true")
+	}
+
+	if p.HealthCheckID != "" {
+		s = append(s, fmt.Sprintf("health-check=%s", p.HealthCheckID))
+	}
+
+	if p.HealthCheckName != "" {
+		s = append(s, fmt.Sprintf("health-check-name=%q", p.HealthCheckName))
+	}
+
+	if p.TrafficPolicyInstance != "" {
+		s = append(s, fmt.Sprintf("traffic-policy-instance=%q", p.TrafficPolicyInstance))
+	}
+
+	return strings.Join(s, " ")
+}
+
+// String returns geolocation codes separated by '/', '*' stands for a default location
+func (g *GeoLocation) String() string {
+	var s []string
+
+	for _, code := range []string{g.ContinentCode, g.CountryCode, g.SubdivisionCode} {
+		if code != "" {
+			s = append(s, code)
+		}
+	}
+
+	return strings.Join(s, "/")
+}
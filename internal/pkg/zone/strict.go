@@ -0,0 +1,217 @@
+package zone
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// Mode of a zonefile output
+type Mode string
+
+const (
+	// Annotated zonefile is intended to be read by human and is not importable as is
+	Annotated Mode = "annotated"
+	// Strict zonefile follows RFC 1035 and may be loaded by a DNS server
+	Strict Mode = "strict"
+)
+
+// Zonefile returns zone formatted in an output mode
+func (z *Zone) Zonefile(mode Mode) (bytes.Buffer, error) {
+	if mode == Strict {
+		return z.ConvertToStrictZonefile()
+	}
+
+	return z.ConvertToZonefile()
+}
+
+// ConvertToStrictZonefile returns RFC 1035 zonefile.
+// Constructs that only exist at a provider (aliases, routing policies) and records that do not parse are kept as comments.
+func (z *Zone) ConvertToStrictZonefile() (bytes.Buffer, error) {
+	b := bytes.Buffer{}
+	p := bytes.Buffer{}
+
+	origin := dns.Fqdn(z.Name)
+
+	types, groups := z.sections()
+
+	soa := strictSOA(origin, z.Provider, groups)
+
+	_, err := b.WriteString(fmt.Sprintf("$ORIGIN %s\n$TTL %v\n\n;; SOA Record\n", origin, soa.TTL))
+	if err != nil {
+		return bytes.Buffer{}, errors.Wrap(err, "error formatting records of type 'SOA'")
+	}
+
+	if soa.Comment != "" {
+		_, err = b.WriteString(fmt.Sprintf("; %s\n", comment(soa.Comment)))
+		if err != nil {
+			return bytes.Buffer{}, errors.Wrap(err, "error formatting records of type 'SOA'")
+		}
+	}
+
+	_, err = b.WriteString(fmt.Sprintf("%s\t%v\tIN\tSOA\t%s\n\n", dns.Fqdn(soa.Name), soa.TTL, soa.Value[0]))
+	if err != nil {
+		return bytes.Buffer{}, errors.Wrap(err, "error formatting records of type 'SOA'")
+	}
+
+	occupied := occupied(z.Records)
+
+	for _, t := range types {
+		records := groups[t]
+		if len(records) == 0 {
+			continue
+		}
+
+		err := formatStrict(origin, z.Provider, occupied, records, &b, &p, t)
+		if err != nil {
+			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
+		}
+	}
+
+	if p.Len() > 0 {
+		_, err = b.WriteString(fmt.Sprintf(";; %s Records (provider-only, not imported)\n%s", z.Provider, p.String()))
+		if err != nil {
+			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error appending %s Aliases", z.Provider))
+		}
+	}
+
+	return b, nil
+}
+
+// strictSOA returns an exported SOA record, or a placeholder when a provider does not expose one, as a zonefile is not loadable without it
+func strictSOA(origin, provider string, groups map[string][]Record) Record {
+	if soa, ok := groups["SOA"]; ok && len(soa[0].Value) > 0 {
+		return soa[0]
+	}
+
+	primary := "ns." + origin
+	for _, ns := range groups["NS"] {
+		if dns.Fqdn(ns.Name) == origin && len(ns.Value) > 0 && !ns.Alias {
+			primary = dns.Fqdn(ns.Value[0])
+			break
+		}
+	}
+
+	return Record{
+		Name:    origin,
+		Type:    "SOA",
+		TTL:     3600,
+		Value:   []string{fmt.Sprintf("%s hostmaster.%s 1 7200 3600 1209600 3600", primary, origin)},
+		Comment: fmt.Sprintf("placeholder, SOA record is not exported by %s", provider),
+	}
+}
+
+// occupied returns names holding importable data other than a CNAME record, which a CNAME must not coexist with (RFC 1034, section 3.6.2)
+func occupied(r []Record) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, record := range r {
+		if record.Type != "CNAME" && !record.Alias && !record.Disabled {
+			names[strings.ToLower(dns.Fqdn(record.Name))] = true
+		}
+	}
+
+	return names
+}
+
+// formatStrict writes importable records into 'b' and provider-only ones as comments into 'p'
+func formatStrict(origin, provider string, occupied map[string]bool, r []Record, b, p *bytes.Buffer, t string) error {
+	var rows []string
+	var comments []string
+
+	for _, record := range r {
+		var note []string
+		var annotated bool
+
+		if record.Routing != nil {
+			note = append(note, fmt.Sprintf("; routing: %s", record.Routing))
+		}
+
+		if record.Target != nil {
+			note = append(note, fmt.Sprintf("; alias: %s", record.Target))
+		}
+
+		// record sets of a routing policy share a name and would be merged into a single one by a DNS server
+		var reason string
+		switch {
+		case record.Alias:
+			reason = fmt.Sprintf("%s alias", provider)
+		case record.Routing != nil && record.Routing.SetIdentifier != "":
+			reason = fmt.Sprintf("%s routing policy", provider)
+		case record.Disabled:
+			reason = "disabled"
+		// apex CNAME is flattened by a provider, a DNS server rejects it next to SOA and NS records of a zone
+		case record.kind(t) == "CNAME" && strings.EqualFold(dns.Fqdn(record.Name), origin):
+			reason = "CNAME at zone apex"
+		case record.kind(t) == "CNAME" && occupied[strings.ToLower(dns.Fqdn(record.Name))]:
+			reason = "CNAME alongside other data"
+		}
+
+		// provider-only rows keep record attributes, so that a zonefile can be read back into a zone
+		commented := note
+		if a := record.attributes(); a != "" {
+			commented = append(append([]string{}, note...), fmt.Sprintf("; %s", a))
+		}
+
+		for _, value := range record.Value {
+			row := fmt.Sprintf("%s\t%sIN\t%s\t%s", dns.Fqdn(record.Name), record.ttl(), record.kind(t), value)
+
+			if reason != "" {
+				comments = append(comments, commented...)
+				comments = append(comments, fmt.Sprintf("; %s: %s", reason, row))
+				commented = nil
+				continue
+			}
+
+			if !parsable(origin, row) {
+				rows = append(rows, fmt.Sprintf("; invalid: %s", row))
+				continue
+			}
+
+			// annotations precede the first value of a record set only
+			if !annotated {
+				if record.Comment != "" {
+					rows = append(rows, fmt.Sprintf("; %s", comment(record.Comment)))
+				}
+
+				rows = append(rows, note...)
+
+				if a := record.attributes(); a != "" {
+					rows = append(rows, fmt.Sprintf("; %s", a))
+				}
+
+				annotated = true
+			}
+
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) > 0 {
+		_, err := b.WriteString(fmt.Sprintf(";; %s Records\n%s\n\n", t, strings.Join(rows, "\n")))
+		if err != nil {
+			return errors.New("error formatting zonefile")
+		}
+	}
+
+	if len(comments) > 0 {
+		_, err := p.WriteString(strings.Join(comments, "\n") + "\n")
+		if err != nil {
+			return errors.New("error adding provider-only records")
+		}
+	}
+
+	return nil
+}
+
+// parsable reports whether a zonefile row is a valid resource record
+func parsable(origin, row string) bool {
+	zp := dns.NewZoneParser(strings.NewReader(row), origin, "")
+
+	_, ok := zp.Next()
+
+	return ok && zp.Err() == nil
+}
@@ -0,0 +1,292 @@
+package zone_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+)
+
+func TestConvertToStrictZonefileParseBack(t *testing.T) {
+	suite := map[string][]string{
+		"SOA":   {"ns1.domain.com. hostmaster.domain.com. 1 7200 900 1209600 86400"},
+		"NS":    {"ns1.domain.com.", "ns2.domain.com."},
+		"MX":    {"10 mail1.domain.com.", "20 mail2.domain.com."},
+		"A":     {"1.2.3.4", "1.2.3.5"},
+		"AAAA":  {"2001:db8::1"},
+		"CNAME": {"domain.com."},
+		"TXT":   {`"v=spf1 -all"`, `"part one" "part two"`},
+		"SRV":   {"10 5 5060 sip.domain.com."},
+		"PTR":   {"host.domain.com."},
+		"SPF":   {`"v=spf1 include:_spf.google.com ~all"`},
+		"NAPTR": {`100 10 "S" "SIP+D2U" "" _sip._udp.domain.com.`},
+		"CAA":   {`0 issue "letsencrypt.org"`, `0 iodef "mailto:security@domain.com"`},
+		"DS":    {"2371 13 2 48E939042E82C22542CB377B580DFDC52A361CEFDC72E7F9107E2B6BD9306A45"},
+		"HTTPS": {`1 . alpn="h2,h3"`},
+		"SVCB":  {`1 svc.domain.com. port=8443`},
+		"TLSA":  {"3 1 1 0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6"},
+		"SSHFP": {"4 2 123456789ABCDEF67890123456789ABCDEF67890123456789ABCDEF123456789A"},
+	}
+
+	for rtype, values := range suite {
+		name := "domain.com."
+		if rtype != "SOA" && rtype != "NS" && rtype != "MX" {
+			name = fmt.Sprintf("%s.domain.com.", strings.ToLower(rtype))
+		}
+
+		z := zone.Zone{
+			Name:     "domain.com",
+			Provider: "Route53",
+			Records: []zone.Record{
+				{
+					Name:  name,
+					Type:  rtype,
+					TTL:   300,
+					Value: values,
+				},
+			},
+		}
+
+		// SOA record of a zone under test
+		if rtype != "SOA" {
+			z.Records = append(z.Records, zone.Record{
+				Name:  "domain.com.",
+				Type:  "SOA",
+				TTL:   900,
+				Value: suite["SOA"],
+			})
+		}
+
+		content, err := z.ConvertToStrictZonefile()
+		if err != nil {
+			t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+		}
+
+		parsed := parse(t, content.String())
+
+		for _, value := range values {
+			expected, err := dns.NewRR(fmt.Sprintf("%s 300 IN %s %s", name, rtype, value))
+			if err != nil {
+				t.Fatalf("error parsing '%s' test record: %v", rtype, err)
+			}
+
+			var found bool
+			for _, rr := range parsed {
+				if dns.IsDuplicate(rr, expected) && rr.Header().Ttl == expected.Header().Ttl {
+					found = true
+				}
+			}
+
+			if !found {
+				t.Errorf("\nEXPECTED parsed record: \n%s\n\nGOT zonefile: \n%s\n\n", expected, content.String())
+			}
+		}
+	}
+}
+
+func TestConvertToStrictZonefile(t *testing.T) {
+	weight := int64(10)
+
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{
+				Name:  "domain.com.",
+				Type:  "NS",
+				TTL:   172800,
+				Value: []string{"ns-1.awsdns-01.org.", "ns-2.awsdns-02.com."},
+			},
+			{
+				Name:    "www.domain.com.",
+				Type:    "A",
+				TTL:     300,
+				Value:   []string{"1.2.3.4", "1.2.3.5"},
+				Comment: "web frontend",
+			},
+			{
+				Name:  "domain.com.",
+				Type:  "A",
+				Value: []string{"d111111abcdef8.cloudfront.net."},
+				Alias: true,
+				Target: &zone.AliasTarget{
+					HostedZoneID: "Z2FDTNDATAQYW2",
+					Service:      "CloudFront",
+				},
+			},
+			{
+				Name:  "api.domain.com.",
+				Type:  "A",
+				TTL:   60,
+				Value: []string{"1.2.3.6"},
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier: "blue",
+					Weight:        &weight,
+				},
+			},
+			{
+				Name:  "domain.com.",
+				Type:  "TYPE65534",
+				TTL:   0,
+				Value: []string{"0x1234"},
+			},
+		},
+	}
+
+	expected := `$ORIGIN domain.com.
+$TTL 3600
+
+;; SOA Record
+; placeholder, SOA record is not exported by Route53
+domain.com.	3600	IN	SOA	ns-1.awsdns-01.org. hostmaster.domain.com. 1 7200 3600 1209600 3600
+
+;; NS Records
+domain.com.	172800	IN	NS	ns-1.awsdns-01.org.
+domain.com.	172800	IN	NS	ns-2.awsdns-02.com.
+
+;; A Records
+; web frontend
+www.domain.com.	300	IN	A	1.2.3.4
+www.domain.com.	300	IN	A	1.2.3.5
+
+;; Other Records
+; invalid: domain.com.	0	IN	TYPE65534	0x1234
+
+;; Route53 Records (provider-only, not imported)
+; alias: service="CloudFront" hosted-zone=Z2FDTNDATAQYW2 evaluate-target-health=false
+; Route53 alias: domain.com.	IN	A	d111111abcdef8.cloudfront.net.
+; routing: set-identifier="blue" weight=10
+; Route53 routing policy: api.domain.com.	60	IN	A	1.2.3.6
+`
+
+	content, err := z.ConvertToStrictZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, content.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n%s\n\nGOT zonefile: \n%s\n\n", expected, content.String())
+	}
+
+	// test: provider-only records are not loaded
+	parsed := parse(t, content.String())
+	if len(parsed) != 5 {
+		t.Errorf("\nEXPECTED parsed records: \n5\n\nGOT parsed records: \n%v\n\n", parsed)
+	}
+}
+
+// parse returns resource records of a zonefile, failing a test on a syntax error
+func parse(t *testing.T, content string) []dns.RR {
+	var records []dns.RR
+
+	zp := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		records = append(records, rr)
+	}
+
+	if err := zp.Err(); err != nil {
+		t.Fatalf("error parsing zonefile: %v\n\n%s", err, content)
+	}
+
+	return records
+}
+
+func TestConvertToStrictZonefileComment(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{
+				Name:    "www.domain.com.",
+				Type:    "A",
+				TTL:     300,
+				Value:   []string{"1.2.3.4"},
+				Comment: "first line\nevil.domain.com. 300 IN A 6.6.6.6",
+			},
+		},
+	}
+
+	content, err := z.ConvertToStrictZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for _, rr := range parse(t, content.String()) {
+		if rr.Header().Name == "evil.domain.com." {
+			t.Errorf("\nEXPECTED comment: \nkept on a single line\n\nGOT zonefile: \n%s\n\n", content.String())
+		}
+	}
+}
+
+func TestConvertToStrictZonefileCNAME(t *testing.T) {
+	proxied := true
+
+	z := zone.Zone{
+		Name:     "domain.com",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{
+				Name:  "domain.com.",
+				Type:  "NS",
+				TTL:   86400,
+				Value: []string{"ns1.domain.com."},
+			},
+			{
+				Name:    "domain.com.",
+				Type:    "CNAME",
+				TTL:     300,
+				Value:   []string{"domain.pages.dev."},
+				Proxied: &proxied,
+			},
+			{
+				Name:  "www.domain.com.",
+				Type:  "CNAME",
+				TTL:   300,
+				Value: []string{"domain.com."},
+			},
+			{
+				Name:  "www.domain.com.",
+				Type:  "TXT",
+				TTL:   300,
+				Value: []string{`"verification"`},
+			},
+			{
+				Name:  "api.domain.com.",
+				Type:  "CNAME",
+				TTL:   300,
+				Value: []string{"domain.com."},
+			},
+		},
+	}
+
+	content, err := z.ConvertToStrictZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	// test: CNAME records that violate RFC 1034 are not loaded
+	var cnames []string
+	for _, rr := range parse(t, content.String()) {
+		if rr.Header().Rrtype == dns.TypeCNAME {
+			cnames = append(cnames, rr.Header().Name)
+		}
+	}
+
+	if !reflect.DeepEqual([]string{"api.domain.com."}, cnames) {
+		t.Errorf("\nEXPECTED loaded CNAME records: \n[api.domain.com.]\n\nGOT loaded CNAME records: \n%v\n\n%s", cnames, content.String())
+	}
+
+	// test: CNAME records that violate RFC 1034 are kept as provider-only
+	for _, line := range []string{
+		"; proxied=true\n; CNAME at zone apex: domain.com.\t300\tIN\tCNAME\tdomain.pages.dev.\n",
+		"; CNAME alongside other data: www.domain.com.\t300\tIN\tCNAME\tdomain.com.\n",
+	} {
+		if !strings.Contains(content.String(), line) {
+			t.Errorf("\nEXPECTED provider-only record: \n%s\nGOT zonefile: \n%s\n\n", line, content.String())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"dns-exporter/internal/pkg/utils"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// Format of an exported zone file
+type Format string
+
+const (
+	// ZonefileFormat is a zonefile in a configured output mode
+	ZonefileFormat Format = "zonefile"
+	// JSONFormat is a structured zone with full record metadata
+	JSONFormat Format = "json"
+	// YAMLFormat is a structured zone with full record metadata
+	YAMLFormat Format = "yaml"
+	// OctoDNSFormat is an OctoDNS YAML zone config
+	OctoDNSFormat Format = "octodns"
+	// DNSControlFormat is a DNSControl 'dnsconfig.js' fragment
+	DNSControlFormat Format = "dnscontrol"
+	// TerraformFormat is a Terraform config with import blocks, generated by providers that have Terraform resources
+	TerraformFormat Format = "terraform"
+)
+
+// Formats of exported zones, configured on startup
+var Formats = []Format{ZonefileFormat}
+
+// Enabled reports whether zones are exported in a format
+func Enabled(f Format) bool {
+	for _, format := range Formats {
+		if format == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseFormat returns a supported export format
+func ParseFormat(f string) (Format, error) {
+	switch Format(f) {
+	case ZonefileFormat, JSONFormat, YAMLFormat, OctoDNSFormat, DNSControlFormat, TerraformFormat:
+		return Format(f), nil
+	}
+
+	return "", fmt.Errorf("unsupported export format '%s'", f)
+}
+
+// Write exports zone into 'dir', a file per configured format, zonefiles in an output mode
+func (z *Zone) Write(dir string, mode Mode, fs afero.Fs) error {
+	return z.WriteAs(z.Name, dir, mode, fs)
+}
+
+// WriteAs exports zone into 'dir' under a file name of its own, for providers that host several zones of the same name.
+// DNS-as-code configs keep a name of a zone, in a subdirectory of the file name when it differs.
+func (z *Zone) WriteAs(name, dir string, mode Mode, fs afero.Fs) error {
+	for _, f := range Formats {
+		if f == TerraformFormat {
+			continue
+		}
+
+		content, extension, err := z.render(f, mode)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error composing %s export", f))
+		}
+
+		// DNS-as-code tools expect a file per zone named exactly as the zone, in a directory of its own
+		if f == OctoDNSFormat || f == DNSControlFormat {
+			config := fmt.Sprintf("%s/%s", dir, f)
+			if name != z.Name {
+				config = fmt.Sprintf("%s/%s", config, name)
+			}
+
+			err = WriteConfig(z.Name, extension, content, config, fs)
+		} else {
+			_, err = utils.WriteToFileAs(name, extension, content, dir, fs)
+		}
+
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error writing %s export", f))
+		}
+	}
+
+	return nil
+}
+
+// WriteConfig writes a DNS-as-code config of a zone into '<dir>/<zone>.<extension>'
+func WriteConfig(name, extension, content, dir string, fs afero.Fs) error {
+	_, err := utils.ValidateDir(dir, true, fs)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, fmt.Sprintf("%s/%s.%s", dir, strings.TrimSuffix(name, "."), extension), []byte(content), 0644)
+}
+
+// render returns zone content and a file extension of a format
+func (z *Zone) render(f Format, mode Mode) (string, string, error) {
+	switch f {
+	case JSONFormat:
+		b, err := json.MarshalIndent(z.Sorted(), "", "  ")
+		if err != nil {
+			return "", "", err
+		}
+
+		return string(b) + "\n", "json", nil
+	case YAMLFormat:
+		b, err := yaml.Marshal(z.Sorted())
+		if err != nil {
+			return "", "", err
+		}
+
+		return string(b), "yaml", nil
+	case OctoDNSFormat:
+		content, err := z.ConvertToOctoDNS()
+		return content, "yaml", err
+	case DNSControlFormat:
+		content, err := z.ConvertToDNSControl()
+		return content, "js", err
+	}
+
+	b, err := z.Zonefile(mode)
+	if err != nil {
+		return "", "", err
+	}
+
+	return b.String(), "txt", nil
+}
+
+// Sorted returns a copy of a zone with records sorted by name, type, routing set identifier, values and ID, and values of every record sorted,
+// so that an unchanged zone is exported identically regardless of an order in which a provider returns it
+func (z *Zone) Sorted() Zone {
+	s := *z
+	s.Records = make([]Record, len(z.Records))
+
+	for i, r := range z.Records {
+		r.Value = append([]string{}, r.Value...)
+		sort.Strings(r.Value)
+		s.Records[i] = r
+	}
+
+	sort.SliceStable(s.Records, func(i, j int) bool {
+		a, b := s.Records[i], s.Records[j]
+
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		if a.identifier() != b.identifier() {
+			return a.identifier() < b.identifier()
+		}
+
+		// providers without record sets, such as CloudFlare, return a record per value
+		if v, w := strings.Join(a.Value, "\n"), strings.Join(b.Value, "\n"); v != w {
+			return v < w
+		}
+
+		return a.ID < b.ID
+	})
+
+	return s
+}
+
+// identifier of a record set within a routing policy
+func (r *Record) identifier() string {
+	if r.Routing == nil {
+		return ""
+	}
+
+	return r.Routing.SetIdentifier
+}
@@ -0,0 +1,216 @@
+package zone_test
+
+import (
+	"reflect"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/spf13/afero"
+)
+
+func TestSorted(t *testing.T) {
+	blue, green := int64(10), int64(90)
+
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5", "1.2.3.4"}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.7"}, Routing: &zone.RoutingPolicy{SetIdentifier: "green", Weight: &green}},
+			{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-2.awsdns-02.com.", "ns-1.awsdns-01.org."}},
+			{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &blue}},
+			{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}},
+		},
+	}
+
+	expected := []zone.Record{
+		{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.6"}, Routing: &zone.RoutingPolicy{SetIdentifier: "blue", Weight: &blue}},
+		{Name: "api.domain.com.", Type: "A", TTL: 60, Value: []string{"1.2.3.7"}, Routing: &zone.RoutingPolicy{SetIdentifier: "green", Weight: &green}},
+		{Name: "domain.com.", Type: "MX", TTL: 300, Value: []string{"10 mail.domain.com."}},
+		{Name: "domain.com.", Type: "NS", TTL: 172800, Value: []string{"ns-1.awsdns-01.org.", "ns-2.awsdns-02.com."}},
+		{Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4", "1.2.3.5"}},
+	}
+
+	s := z.Sorted()
+
+	if !reflect.DeepEqual(expected, s.Records) {
+		t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, s.Records)
+	}
+
+	// test: original zone is not modified
+	if z.Records[0].Value[0] != "1.2.3.5" || z.Records[0].Name != "www.domain.com." {
+		t.Errorf("\nEXPECTED original records: \nunsorted\n\nGOT original records: \n%+v\n\n", z.Records)
+	}
+}
+
+func TestSortedRecordPerValue(t *testing.T) {
+	records := []zone.Record{
+		{ID: "b", Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}},
+		{ID: "d", Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+		{ID: "a", Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+		{ID: "c", Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+	}
+
+	expected := []zone.Record{
+		{ID: "a", Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+		{ID: "b", Name: "www.domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.5"}},
+		{ID: "c", Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+		{ID: "d", Name: "www.domain.com.", Type: "TXT", TTL: 300, Value: []string{`"v=spf1 -all"`}},
+	}
+
+	// test: records of a provider returned in any order are sorted identically
+	for _, order := range [][]int{{0, 1, 2, 3}, {2, 3, 0, 1}, {3, 2, 1, 0}} {
+		z := zone.Zone{
+			Name:     "domain.com.",
+			Provider: "CloudFlare",
+		}
+
+		for _, i := range order {
+			z.Records = append(z.Records, records[i])
+		}
+
+		s := z.Sorted()
+
+		if !reflect.DeepEqual(expected, s.Records) {
+			t.Errorf("\nEXPECTED records: \n%+v\n\nGOT records: \n%+v\n\n", expected, s.Records)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	defer func() {
+		zone.Formats = []zone.Format{zone.ZonefileFormat}
+	}()
+
+	proxied := true
+
+	z := zone.Zone{
+		Name:     "domain.com",
+		ID:       "1",
+		Provider: "CloudFlare",
+		Records: []zone.Record{
+			{
+				Name:    "www.domain.com.",
+				Type:    "CNAME",
+				TTL:     1,
+				Value:   []string{"domain.com."},
+				Proxied: &proxied,
+				AutoTTL: true,
+				Comment: "web frontend",
+				Tags:    []string{"env:prod"},
+			},
+			{
+				Name:  "domain.com.",
+				Type:  "A",
+				TTL:   300,
+				Value: []string{"1.2.3.4"},
+			},
+		},
+	}
+
+	expected := map[string]string{
+		"./domain-com.txt": `;; A Records
+domain.com.	300	IN	A	1.2.3.4
+
+;; CNAME Records
+; web frontend
+; proxied=true ttl=auto tags="env:prod"
+www.domain.com.	1	IN	CNAME	domain.com.
+
+`,
+		"./domain-com.json": `{
+  "name": "domain.com",
+  "id": "1",
+  "provider": "CloudFlare",
+  "private": false,
+  "records": [
+    {
+      "name": "domain.com.",
+      "type": "A",
+      "ttl": 300,
+      "values": [
+        "1.2.3.4"
+      ]
+    },
+    {
+      "name": "www.domain.com.",
+      "type": "CNAME",
+      "ttl": 1,
+      "values": [
+        "domain.com."
+      ],
+      "proxied": true,
+      "auto_ttl": true,
+      "comment": "web frontend",
+      "tags": [
+        "env:prod"
+      ]
+    }
+  ]
+}
+`,
+		"./domain-com.yaml": `name: domain.com
+id: "1"
+provider: CloudFlare
+private: false
+records:
+    - name: domain.com.
+      type: A
+      ttl: 300
+      values:
+        - 1.2.3.4
+    - name: www.domain.com.
+      type: CNAME
+      ttl: 1
+      values:
+        - domain.com.
+      proxied: true
+      auto_ttl: true
+      comment: web frontend
+      tags:
+        - env:prod
+`,
+	}
+
+	zone.Formats = []zone.Format{zone.ZonefileFormat, zone.JSONFormat, zone.YAMLFormat}
+
+	fs := afero.NewMemMapFs()
+
+	err := z.Write(".", zone.Annotated, fs)
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	for file, content := range expected {
+		result, err := afero.ReadFile(fs, file)
+		if err != nil {
+			t.Fatal("error reading exported file:", err)
+		}
+
+		if !reflect.DeepEqual(content, string(result)) {
+			t.Errorf("\nEXPECTED content of %s: \n%s\n\nGOT content: \n%s\n\n", file, content, string(result))
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	suite := map[string]bool{
+		"zonefile": true,
+		"json":     true,
+		"yaml":     true,
+		"xml":      false,
+		"":         false,
+	}
+
+	for input, valid := range suite {
+		f, err := zone.ParseFormat(input)
+		if valid && (err != nil || string(f) != input) {
+			t.Errorf("\nEXPECTED format: \n%s\n\nGOT format: \n%s (%v)\n\n", input, f, err)
+		}
+
+		if !valid && err == nil {
+			t.Errorf("\nEXPECTED error: \nunsupported export format '%s'\n\nGOT error: \n<nil>\n\n", input)
+		}
+	}
+}
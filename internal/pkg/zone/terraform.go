@@ -0,0 +1,121 @@
+package zone
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Resources names Terraform resources of a config, keeping the names unique
+type Resources map[string]int
+
+// Name returns a Terraform resource name out of parts such as a record name and type
+func (r Resources) Name(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "_"))
+	name = strings.ReplaceAll(name, `\052`, "wildcard")
+	name = strings.ReplaceAll(name, "*", "wildcard")
+	name = regexp.MustCompile(`[^a-z0-9_-]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+
+	// names start with a letter or an underscore
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+
+	r[name]++
+	if r[name] > 1 {
+		return fmt.Sprintf("%s_%v", name, r[name])
+	}
+
+	return name
+}
+
+// HCL returns a quoted HCL string, escaping template sequences.
+// Only escapes of HCL are used, as Go escapes such as '\x' or '\a' are not valid HCL, invalid UTF-8 becomes a replacement character.
+func HCL(s string) string {
+	b := strings.Builder{}
+	b.WriteByte('"')
+
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			b.WriteString(fmt.Sprintf(`\U%08X`, r))
+		default:
+			b.WriteString(fmt.Sprintf(`\u%04X`, r))
+		}
+	}
+
+	b.WriteByte('"')
+
+	out := strings.ReplaceAll(b.String(), "${", "$${")
+	out = strings.ReplaceAll(out, "%{", "%%{")
+
+	return out
+}
+
+// HCLList returns an HCL list of quoted strings
+func HCLList(values []string) string {
+	var l []string
+	for _, v := range values {
+		l = append(l, HCL(v))
+	}
+
+	return "[" + strings.Join(l, ", ") + "]"
+}
+
+// HCLMap returns an HCL map of strings, sorted by keys
+func HCLMap(m map[string]string) string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s = %s", HCL(k), HCL(m[k])))
+	}
+
+	return "{ " + strings.Join(pairs, ", ") + " }"
+}
+
+// Attributes returns HCL attributes aligned the way 'terraform fmt' does
+func Attributes(attributes [][2]string, indent string) string {
+	var width int
+	for _, a := range attributes {
+		if len(a[0]) > width {
+			width = len(a[0])
+		}
+	}
+
+	b := strings.Builder{}
+	for _, a := range attributes {
+		b.WriteString(fmt.Sprintf("%s%-*s = %s\n", indent, width, a[0], a[1]))
+	}
+
+	return b.String()
+}
+
+// Block returns a block nested into a resource
+func Block(name string, attributes [][2]string) string {
+	return fmt.Sprintf("  %s {\n%s  }\n", name, Attributes(attributes, "    "))
+}
+
+// Import returns an import block of an existing resource
+func Import(to, id string) string {
+	return fmt.Sprintf("import {\n  to = %s\n  id = %s\n}\n", to, HCL(id))
+}
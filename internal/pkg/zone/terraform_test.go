@@ -0,0 +1,28 @@
+package zone_test
+
+import (
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+)
+
+func TestHCL(t *testing.T) {
+	suite := map[string]string{
+		"v=spf1 -all":              `"v=spf1 -all"`,
+		`say "hi"`:                 `"say \"hi\""`,
+		`C:\dns`:                   `"C:\\dns"`,
+		"line\r\nnext\tcolumn":     `"line\r\nnext\tcolumn"`,
+		"bell\a null\x00 del\x7f":  `"bell\u0007 null\u0000 del\u007F"`,
+		"nbsp\u00a0 tag\U000E0001": `"nbsp\u00A0 tag\U000E0001"`,
+		"žluťoučký 🙂":              `"žluťoučký 🙂"`,
+		"invalid \xff byte":        "\"invalid \uFFFD byte\"",
+		"${var.name} %{if x}":      `"$${var.name} %%{if x}"`,
+	}
+
+	for value, expected := range suite {
+		got := zone.HCL(value)
+		if got != expected {
+			t.Errorf("\nEXPECTED HCL string: \n%s\n\nGOT HCL string: \n%s\n\n", expected, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package zone
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// order of record types in a zonefile, types that are not listed share a single section after them
+var order = []string{"NS", "MX", "A", "AAAA", "CNAME", "TXT", "SRV", "PTR", "SPF", "NAPTR", "CAA", "DS", "HTTPS", "SVCB", "TLSA", "SSHFP"}
+
+// other is a section of record types that are not listed in 'order'
+const other = "Other"
+
+// ConvertToZonefile returns formatted zonefile
+func (z *Zone) ConvertToZonefile() (bytes.Buffer, error) {
+	b := bytes.Buffer{}
+	p := bytes.Buffer{}
+
+	var aliasHeaderMarker bool
+
+	types, groups := z.sections()
+
+	// SOA
+	if soa, ok := groups["SOA"]; ok && len(soa[0].Value) > 0 {
+		_, err := b.WriteString(fmt.Sprintf(";; SOA Record\n%s\t%v\tIN\tSOA\t%s\n\n", soa[0].Name, soa[0].TTL, soa[0].Value[0]))
+		if err != nil {
+			return bytes.Buffer{}, errors.Wrap(err, "error formatting records of type 'SOA'")
+		}
+	}
+
+	for _, t := range types {
+		records := groups[t]
+		if len(records) == 0 {
+			continue
+		}
+
+		err := format(&aliasHeaderMarker, z.Provider, &records, &b, &p, t)
+		if err != nil {
+			return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error formatting records of type '%s'", t))
+		}
+	}
+
+	_, err := b.WriteString(p.String())
+	if err != nil {
+		return bytes.Buffer{}, errors.Wrap(err, fmt.Sprintf("error appending %s Aliases", z.Provider))
+	}
+
+	return b, nil
+}
+
+// sections returns zonefile sections in their order and records of every section, keyed by a record type,
+// unknown record types are collected into a single 'Other' section
+func (z *Zone) sections() ([]string, map[string][]Record) {
+	groups := make(map[string][]Record)
+	for _, record := range z.Records {
+		section := record.Type
+		if !contains(order, section) && section != "SOA" {
+			section = other
+		}
+
+		groups[section] = append(groups[section], record)
+	}
+
+	return append(append([]string{}, order...), other), groups
+}
+
+// format zonefile content
+func format(marker *bool, provider string, r *[]Record, b, p *bytes.Buffer, t string) error {
+	_, err := b.WriteString(fmt.Sprintf(";; %s Records\n", t))
+	if err != nil {
+		return errors.New("error formatting zonefile")
+	}
+
+	for _, record := range *r {
+		if !record.Alias {
+			if record.Comment != "" {
+				_, err := b.WriteString(fmt.Sprintf("; %s\n", comment(record.Comment)))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+
+			if record.Routing != nil {
+				_, err := b.WriteString(fmt.Sprintf("; routing: %s\n", record.Routing))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+
+			if a := record.attributes(); a != "" {
+				_, err := b.WriteString(fmt.Sprintf("; %s\n", a))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+
+			// disabled records are kept commented out
+			var prefix string
+			if record.Disabled {
+				prefix = "; disabled: "
+			}
+
+			for _, rec := range record.Value {
+				_, err := b.WriteString(fmt.Sprintf("%s%s\t%v\tIN\t%s\t%s\n", prefix, record.Name, record.TTL, record.kind(t), rec))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+		} else {
+			if !*marker {
+				_, err := p.WriteString(fmt.Sprintf(";; %s Alias Records\n", provider))
+				if err != nil {
+					return errors.New("error adding alias title")
+				}
+				*marker = true
+			}
+
+			if record.Routing != nil {
+				_, err := p.WriteString(fmt.Sprintf("; routing: %s\n", record.Routing))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+
+			if record.Target != nil {
+				_, err := p.WriteString(fmt.Sprintf("; alias: %s\n", record.Target))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+
+			for _, rec := range record.Value {
+				_, err := p.WriteString(fmt.Sprintf("%s\t%sIN\t%s\t%s\n", record.Name, record.ttl(), record.kind(t), rec))
+				if err != nil {
+					return fmt.Errorf("error formatting records of type: %s", t)
+				}
+			}
+		}
+	}
+
+	_, err = b.WriteString("\n")
+	if err != nil {
+		return fmt.Errorf("error formatting records of type: %s", t)
+	}
+	return nil
+}
+
+// ttl returns a TTL column of a record, empty for aliases without a TTL of their own (Route53 aliases answer with a TTL of their target)
+func (r *Record) ttl() string {
+	if r.Alias && r.TTL == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%v\t", r.TTL)
+}
+
+// kind returns a type of a record, or a type of a section it is formatted in when a record does not carry one
+func (r *Record) kind(t string) string {
+	if r.Type != "" {
+		return r.Type
+	}
+
+	return t
+}
+
+// contains reports whether a list contains a value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Quote returns a zonefile character string, quotes and backslashes are escaped and bytes other than printable ASCII are written as '\DDD',
+// as zonefiles do not accept Go escapes such as '\x' or '\u'
+func Quote(s string) string {
+	b := strings.Builder{}
+	b.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c > '~':
+			b.WriteString(fmt.Sprintf("\\%03d", c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// QuoteTXT returns TXT record data of a value, split into character strings of 255 bytes at most
+func QuoteTXT(s string) string {
+	var chunks []string
+	for len(s) > 255 {
+		chunks = append(chunks, Quote(s[:255]))
+		s = s[255:]
+	}
+
+	return strings.Join(append(chunks, Quote(s)), " ")
+}
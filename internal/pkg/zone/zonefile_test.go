@@ -0,0 +1,606 @@
+package zone_test
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"dns-exporter/internal/pkg/zone"
+
+	"github.com/miekg/dns"
+)
+
+func TestFormat(t *testing.T) {
+	suite := []map[*[]zone.Record]map[string]*bytes.Buffer{}
+
+	// SOA
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "domain.com.",
+				Value: []string{"ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"},
+				Alias: false,
+				TTL:   900,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("SOA")),
+			"records": bytes.NewBuffer([]byte(`;; SOA Records
+domain.com.	900	IN	SOA	ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// NS
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "domain.com.",
+				Value: []string{"ns-1800.awsdns-33.co.uk."},
+				Alias: false,
+				TTL:   172800,
+			},
+			zone.Record{
+				Name:  "domain.com.",
+				Value: []string{"ns3.amazon.net"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("NS")),
+			"records": bytes.NewBuffer([]byte(`;; NS Records
+domain.com.	172800	IN	NS	ns-1800.awsdns-33.co.uk.
+domain.com.	300	IN	NS	ns3.amazon.net
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// MX
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "mail1.domain.com.",
+				Value: []string{"10 mailserver1.google.com"},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "mail2.domain.com.",
+				Value: []string{"20 mailserver2.google.com"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("MX")),
+			"records": bytes.NewBuffer([]byte(`;; MX Records
+mail1.domain.com.	300	IN	MX	10 mailserver1.google.com
+mail2.domain.com.	300	IN	MX	20 mailserver2.google.com
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// A
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "a-alias.domain.com.",
+				Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
+				Alias: true,
+				TTL:   0,
+			},
+			zone.Record{
+				Name:  "a-multiple.domain.com.",
+				Value: []string{"192.168.1.51"},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "a-multiple.domain.com.",
+				Value: []string{"192.168.1.52"},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "low-ttl.domain.com.",
+				Value: []string{"192.168.1.53"},
+				Alias: false,
+				TTL:   60,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("A")),
+			"records": bytes.NewBuffer([]byte(`;; A Records
+a-multiple.domain.com.	300	IN	A	192.168.1.51
+a-multiple.domain.com.	300	IN	A	192.168.1.52
+low-ttl.domain.com.	60	IN	A	192.168.1.53
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
+a-alias.domain.com.	IN	A	a0123456789abcdef.awsglobalaccelerator.com.
+`)),
+		}})
+
+	// AAAA
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "ipv6.domain.com.",
+				Value: []string{"fe80:0:0:0:202:b3ff:fe1e:8329"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("AAAA")),
+			"records": bytes.NewBuffer([]byte(`;; AAAA Records
+ipv6.domain.com.	300	IN	AAAA	fe80:0:0:0:202:b3ff:fe1e:8329
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// CNAME
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "website.domain.com.",
+				Value: []string{"www.domain.com"},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "www.domain.com.",
+				Value: []string{"c0123456789abcdef.awsglobalaccelerator.com."},
+				Alias: true,
+				TTL:   0,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("CNAME")),
+			"records": bytes.NewBuffer([]byte(`;; CNAME Records
+website.domain.com.	300	IN	CNAME	www.domain.com
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
+www.domain.com.	IN	CNAME	c0123456789abcdef.awsglobalaccelerator.com.
+`)),
+		}})
+
+	// TXT
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "txt.domain.com.",
+				Value: []string{"some string here"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("TXT")),
+			"records": bytes.NewBuffer([]byte(`;; TXT Records
+txt.domain.com.	300	IN	TXT	some string here
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// SRV
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "dc.domain.com.",
+				Value: []string{"1 10 5269 controller.domain.com."},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("SRV")),
+			"records": bytes.NewBuffer([]byte(`;; SRV Records
+dc.domain.com.	300	IN	SRV	1 10 5269 controller.domain.com.
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// PTRC
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "pointer.domain.com.",
+				Value: []string{"www.domain.com"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("PTR")),
+			"records": bytes.NewBuffer([]byte(`;; PTR Records
+pointer.domain.com.	300	IN	PTR	www.domain.com
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// SPF
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "policy.domain.com.",
+				Value: []string{"v=spf1 ip4:192.168.0.0/16-all"},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("SPF")),
+			"records": bytes.NewBuffer([]byte(`;; SPF Records
+policy.domain.com.	300	IN	SPF	v=spf1 ip4:192.168.0.0/16-all
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// NAPTR
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "name-auth.domain.com.",
+				Value: []string{"100 100 \"U\" \"\" \"!^.*$!sip:info@bar.example.com!\" ."},
+				Alias: false,
+				TTL:   300,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("NAPTR")),
+			"records": bytes.NewBuffer([]byte(`;; NAPTR Records
+name-auth.domain.com.	300	IN	NAPTR	100 100 "U" "" "!^.*$!sip:info@bar.example.com!" .
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(``)),
+		}})
+
+	// CAA
+	suite = append(suite, map[*[]zone.Record]map[string]*bytes.Buffer{
+		&[]zone.Record{
+			zone.Record{
+				Name:  "ca1.domain.com.",
+				Value: []string{"0 issue \"caa.example.com\""},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "ca2.domain.com.",
+				Value: []string{"issuewild \";\""},
+				Alias: false,
+				TTL:   300,
+			},
+			zone.Record{
+				Name:  "ca3.domain.com.",
+				Value: []string{"b0123456789abcdef.awsglobalaccelerator.com."},
+				Alias: true,
+				TTL:   0,
+			},
+		}: map[string]*bytes.Buffer{
+			"type": bytes.NewBuffer([]byte("CAA")),
+			"records": bytes.NewBuffer([]byte(`;; CAA Records
+ca1.domain.com.	300	IN	CAA	0 issue "caa.example.com"
+ca2.domain.com.	300	IN	CAA	issuewild ";"
+
+`)),
+			"aliases": bytes.NewBuffer([]byte(`;; Route53 Alias Records
+ca3.domain.com.	IN	CAA	b0123456789abcdef.awsglobalaccelerator.com.
+`)),
+		}})
+
+	for _, test := range suite {
+		for input, expected := range test {
+			records := bytes.Buffer{}
+			aliases := bytes.Buffer{}
+			var marker bool
+
+			err := zone.FormatRecords(&marker, "Route53", input, &records, &aliases, expected["type"].String())
+
+			if err != nil {
+				t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+			}
+
+			if !reflect.DeepEqual(expected["records"].String(), records.String()) {
+				t.Errorf("\nEXPECTED records: \n'%+v'\n\nGOT records: \n'%+v'\n\n", expected["records"].String(), records.String())
+			}
+
+			if !reflect.DeepEqual(expected["aliases"].String(), aliases.String()) {
+				t.Errorf("\nEXPECTED aliases: \n'%+v'\n\nGOT aliases: \n'%+v'\n\n", expected["aliases"].String(), aliases.String())
+			}
+		}
+	}
+}
+
+func TestConvertToZonefile(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{
+				Name:  "domain.com.",
+				Type:  "A",
+				TTL:   300,
+				Value: []string{"192.168.1.51"},
+			},
+			{
+				Name:  "domain.com.",
+				Type:  "SOA",
+				TTL:   900,
+				Value: []string{"ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"},
+			},
+			{
+				Name:  "_443._tcp.domain.com.",
+				Type:  "TLSA",
+				TTL:   300,
+				Value: []string{"3 1 1 0123456789abcdef"},
+			},
+			{
+				Name:  "alias.domain.com.",
+				Type:  "A",
+				Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
+				Alias: true,
+			},
+		},
+	}
+
+	expected := `;; SOA Record
+domain.com.	900	IN	SOA	ns-265.awsdns-33.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400
+
+;; A Records
+domain.com.	300	IN	A	192.168.1.51
+
+;; TLSA Records
+_443._tcp.domain.com.	300	IN	TLSA	3 1 1 0123456789abcdef
+
+;; Route53 Alias Records
+alias.domain.com.	IN	A	a0123456789abcdef.awsglobalaccelerator.com.
+`
+
+	zonefile, err := z.ConvertToZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, zonefile.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected, zonefile.String())
+	}
+}
+
+func TestConvertToZonefileRouting(t *testing.T) {
+	blue, green := int64(10), int64(0)
+
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "Route53",
+		Records: []zone.Record{
+			{
+				Name:  "weighted.domain.com.",
+				Type:  "A",
+				TTL:   300,
+				Value: []string{"192.168.1.51"},
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier:   "blue",
+					Weight:          &blue,
+					HealthCheckID:   "abcdef11-2222-3333-4444-555555fedcba",
+					HealthCheckName: "HTTPS api.domain.com:443/health",
+				},
+			},
+			{
+				Name:  "weighted.domain.com.",
+				Type:  "A",
+				TTL:   300,
+				Value: []string{"192.168.1.52"},
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier: "green",
+					Weight:        &green,
+				},
+			},
+			{
+				Name:  "geo.domain.com.",
+				Type:  "CNAME",
+				TTL:   300,
+				Value: []string{"us.domain.com"},
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier: "us-ca",
+					GeoLocation: &zone.GeoLocation{
+						CountryCode:     "US",
+						SubdivisionCode: "CA",
+					},
+				},
+			},
+			{
+				Name:  "latency.domain.com.",
+				Type:  "AAAA",
+				Value: []string{"a0123456789abcdef.awsglobalaccelerator.com."},
+				Alias: true,
+				Routing: &zone.RoutingPolicy{
+					SetIdentifier:    "eu",
+					Region:           "eu-west-1",
+					Failover:         "PRIMARY",
+					MultiValueAnswer: true,
+				},
+				Target: &zone.AliasTarget{
+					HostedZoneID:         "Z2BJ6XQ5FK7U4H",
+					EvaluateTargetHealth: true,
+					Service:              "Global Accelerator",
+				},
+			},
+		},
+	}
+
+	expected := `;; A Records
+; routing: set-identifier="blue" weight=10 health-check=abcdef11-2222-3333-4444-555555fedcba health-check-name="HTTPS api.domain.com:443/health"
+weighted.domain.com.	300	IN	A	192.168.1.51
+; routing: set-identifier="green" weight=0
+weighted.domain.com.	300	IN	A	192.168.1.52
+
+;; AAAA Records
+
+;; CNAME Records
+; routing: set-identifier="us-ca" geolocation=US/CA
+geo.domain.com.	300	IN	CNAME	us.domain.com
+
+;; Route53 Alias Records
+; routing: set-identifier="eu" region=eu-west-1 failover=PRIMARY multivalue=true
+; alias: service="Global Accelerator" hosted-zone=Z2BJ6XQ5FK7U4H evaluate-target-health=true
+latency.domain.com.	IN	AAAA	a0123456789abcdef.awsglobalaccelerator.com.
+`
+
+	zonefile, err := z.ConvertToZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, zonefile.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected, zonefile.String())
+	}
+}
+
+func TestConvertToZonefileComment(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "PowerDNS",
+		Records: []zone.Record{
+			{
+				Name:    "www.domain.com.",
+				Type:    "A",
+				TTL:     300,
+				Value:   []string{"1.2.3.4"},
+				Comment: "first line\nevil.domain.com.\t300\tIN\tA\t6.6.6.6\r\nlast line",
+			},
+		},
+	}
+
+	expected := `;; A Records
+; first line\nevil.domain.com.	300	IN	A	6.6.6.6\nlast line
+www.domain.com.	300	IN	A	1.2.3.4
+
+`
+
+	zonefile, err := z.ConvertToZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, zonefile.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected, zonefile.String())
+	}
+}
+
+func TestConvertToZonefileOther(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "AXFR",
+		Records: []zone.Record{
+			{Name: "domain.com.", Type: "TYPE65534", TTL: 0, Value: []string{`\# 2 1234`}},
+			{Name: "domain.com.", Type: "A", TTL: 300, Value: []string{"1.2.3.4"}},
+			{Name: "domain.com.", Type: "LOC", TTL: 300, Value: []string{"52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m"}},
+		},
+	}
+
+	expected := `;; A Records
+domain.com.	300	IN	A	1.2.3.4
+
+;; Other Records
+domain.com.	0	IN	TYPE65534	\# 2 1234
+domain.com.	300	IN	LOC	52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m
+
+`
+
+	zonefile, err := z.ConvertToZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, zonefile.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected, zonefile.String())
+	}
+}
+
+func TestConvertToZonefileAliasTTL(t *testing.T) {
+	z := zone.Zone{
+		Name:     "domain.com.",
+		Provider: "AzureDNS",
+		Records: []zone.Record{
+			{Name: "cdn.domain.com.", Type: "A", TTL: 60, Value: []string{"/subscriptions/sub-1/resourceGroups/cdn/providers/Microsoft.Cdn/profiles/cdn/endpoints/web"}, Alias: true},
+			{Name: "www.domain.com.", Type: "A", Value: []string{"cdn.domain.com."}, Alias: true},
+		},
+	}
+
+	// aliases with a TTL of their own keep it, the ones without have no TTL column
+	expected := `;; A Records
+
+;; AzureDNS Alias Records
+cdn.domain.com.	60	IN	A	/subscriptions/sub-1/resourceGroups/cdn/providers/Microsoft.Cdn/profiles/cdn/endpoints/web
+www.domain.com.	IN	A	cdn.domain.com.
+`
+
+	zonefile, err := z.ConvertToZonefile()
+	if err != nil {
+		t.Fatal("\nEXPECTED error: \n<nil>\n\nGOT error:", err)
+	}
+
+	if !reflect.DeepEqual(expected, zonefile.String()) {
+		t.Errorf("\nEXPECTED zonefile: \n'%+v'\n\nGOT zonefile: \n'%+v'\n\n", expected, zonefile.String())
+	}
+}
+
+func TestQuote(t *testing.T) {
+	values := []string{
+		`v=spf1 -all`,
+		`say "hi" \ bye`,
+		"tab\tnewline\ncafé",
+		strings.Repeat("x", 600),
+	}
+
+	for _, value := range values {
+		rr, err := dns.NewRR("domain.com. 300 IN TXT " + zone.QuoteTXT(value))
+		if err != nil {
+			t.Fatalf("\nEXPECTED error: \n<nil>\n\nGOT error parsing %q: %v", value, err)
+		}
+
+		got := strings.Join(rr.(*dns.TXT).Txt, "")
+		unquoted, err := unescape(got)
+		if err != nil || unquoted != value {
+			t.Errorf("\nEXPECTED value: \n%q\n\nGOT value: \n%q\n\n", value, unquoted)
+		}
+	}
+
+	expected := `"caf\195\169 \"a\\b\""`
+	if got := zone.Quote(`café "a\b"`); got != expected {
+		t.Errorf("\nEXPECTED quote: \n%s\n\nGOT quote: \n%s\n\n", expected, got)
+	}
+}
+
+// unescape decodes the presentation format miekg/dns uses for TXT data
+func unescape(s string) (string, error) {
+	b := strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+3 < len(s) && isDigit(s[i+1]) && isDigit(s[i+2]) && isDigit(s[i+3]) {
+			n, err := strconv.Atoi(s[i+1 : i+4])
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(byte(n))
+			i += 3
+			continue
+		}
+
+		b.WriteByte(s[i+1])
+		i++
+	}
+
+	return b.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
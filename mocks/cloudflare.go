@@ -14,3 +14,53 @@ func (c *Cloudflare) ListZones(context.Context, ...string) ([]cloudflare.Zone, e
 	args := c.Called()
 	return args.Get(0).([]cloudflare.Zone), args.Error(1)
 }
+
+func (c *Cloudflare) ZoneDetails(ctx context.Context, zoneID string) (cloudflare.Zone, error) {
+	args := c.Called(zoneID)
+	return args.Get(0).(cloudflare.Zone), args.Error(1)
+}
+
+func (c *Cloudflare) ListDNSRecords(ctx context.Context, rc *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+	args := c.Called(rc.Identifier)
+	return args.Get(0).([]cloudflare.DNSRecord), nil, args.Error(1)
+}
+
+func (c *Cloudflare) VerifyAPIToken(context.Context) (cloudflare.APITokenVerifyBody, error) {
+	args := c.Called()
+	return args.Get(0).(cloudflare.APITokenVerifyBody), args.Error(1)
+}
+
+func (c *Cloudflare) ZoneSettings(ctx context.Context, zoneID string) (*cloudflare.ZoneSettingResponse, error) {
+	args := c.Called(zoneID)
+	return args.Get(0).(*cloudflare.ZoneSettingResponse), args.Error(1)
+}
+
+func (c *Cloudflare) ZoneDNSSECSetting(ctx context.Context, zoneID string) (cloudflare.ZoneDNSSEC, error) {
+	args := c.Called(zoneID)
+	return args.Get(0).(cloudflare.ZoneDNSSEC), args.Error(1)
+}
+
+func (c *Cloudflare) ListPageRules(ctx context.Context, zoneID string) ([]cloudflare.PageRule, error) {
+	args := c.Called(zoneID)
+	return args.Get(0).([]cloudflare.PageRule), args.Error(1)
+}
+
+func (c *Cloudflare) GetZoneRulesetPhase(ctx context.Context, zoneID, phase string) (cloudflare.Ruleset, error) {
+	args := c.Called(zoneID, phase)
+	return args.Get(0).(cloudflare.Ruleset), args.Error(1)
+}
+
+func (c *Cloudflare) CreateDNSRecord(ctx context.Context, rc *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error) {
+	args := c.Called(rc.Identifier, params)
+	return args.Get(0).(cloudflare.DNSRecord), args.Error(1)
+}
+
+func (c *Cloudflare) UpdateDNSRecord(ctx context.Context, rc *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+	args := c.Called(rc.Identifier, params)
+	return args.Get(0).(cloudflare.DNSRecord), args.Error(1)
+}
+
+func (c *Cloudflare) DeleteDNSRecord(ctx context.Context, rc *cloudflare.ResourceContainer, recordID string) error {
+	args := c.Called(rc.Identifier, recordID)
+	return args.Error(0)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/stretchr/testify/mock"
+)
+
+type Organizations struct {
+	mock.Mock
+}
+
+func (c *Organizations) ListAccounts(*organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*organizations.ListAccountsOutput), args.Error(1)
+}
@@ -18,3 +18,58 @@ func (c *Route53) ListResourceRecordSets(*route53.ListResourceRecordSetsInput) (
 	args := c.Called()
 	return args.Get(0).(*route53.ListResourceRecordSetsOutput), args.Error(1)
 }
+
+func (c *Route53) GetHostedZone(*route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.GetHostedZoneOutput), args.Error(1)
+}
+
+func (c *Route53) ListTagsForResource(*route53.ListTagsForResourceInput) (*route53.ListTagsForResourceOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListTagsForResourceOutput), args.Error(1)
+}
+
+func (c *Route53) GetDNSSEC(*route53.GetDNSSECInput) (*route53.GetDNSSECOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.GetDNSSECOutput), args.Error(1)
+}
+
+func (c *Route53) ListQueryLoggingConfigs(*route53.ListQueryLoggingConfigsInput) (*route53.ListQueryLoggingConfigsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListQueryLoggingConfigsOutput), args.Error(1)
+}
+
+func (c *Route53) ListHealthChecks(*route53.ListHealthChecksInput) (*route53.ListHealthChecksOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListHealthChecksOutput), args.Error(1)
+}
+
+func (c *Route53) ListTagsForResources(*route53.ListTagsForResourcesInput) (*route53.ListTagsForResourcesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListTagsForResourcesOutput), args.Error(1)
+}
+
+func (c *Route53) ListTrafficPolicies(*route53.ListTrafficPoliciesInput) (*route53.ListTrafficPoliciesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListTrafficPoliciesOutput), args.Error(1)
+}
+
+func (c *Route53) ListTrafficPolicyVersions(*route53.ListTrafficPolicyVersionsInput) (*route53.ListTrafficPolicyVersionsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListTrafficPolicyVersionsOutput), args.Error(1)
+}
+
+func (c *Route53) ListTrafficPolicyInstances(*route53.ListTrafficPolicyInstancesInput) (*route53.ListTrafficPolicyInstancesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ListTrafficPolicyInstancesOutput), args.Error(1)
+}
+
+func (c *Route53) ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.ChangeResourceRecordSetsOutput), args.Error(1)
+}
+
+func (c *Route53) GetChange(*route53.GetChangeInput) (*route53.GetChangeOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53.GetChangeOutput), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go/service/route53resolver"
+	"github.com/stretchr/testify/mock"
+)
+
+type Route53Resolver struct {
+	mock.Mock
+}
+
+func (c *Route53Resolver) ListResolverEndpoints(*route53resolver.ListResolverEndpointsInput) (*route53resolver.ListResolverEndpointsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListResolverEndpointsOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListResolverEndpointIpAddresses(*route53resolver.ListResolverEndpointIpAddressesInput) (*route53resolver.ListResolverEndpointIpAddressesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListResolverEndpointIpAddressesOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListResolverRules(*route53resolver.ListResolverRulesInput) (*route53resolver.ListResolverRulesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListResolverRulesOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListResolverRuleAssociations(*route53resolver.ListResolverRuleAssociationsInput) (*route53resolver.ListResolverRuleAssociationsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListResolverRuleAssociationsOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListFirewallRuleGroups(*route53resolver.ListFirewallRuleGroupsInput) (*route53resolver.ListFirewallRuleGroupsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListFirewallRuleGroupsOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListFirewallRules(*route53resolver.ListFirewallRulesInput) (*route53resolver.ListFirewallRulesOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListFirewallRulesOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListFirewallRuleGroupAssociations(*route53resolver.ListFirewallRuleGroupAssociationsInput) (*route53resolver.ListFirewallRuleGroupAssociationsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListFirewallRuleGroupAssociationsOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListFirewallDomainLists(*route53resolver.ListFirewallDomainListsInput) (*route53resolver.ListFirewallDomainListsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListFirewallDomainListsOutput), args.Error(1)
+}
+
+func (c *Route53Resolver) ListFirewallDomains(*route53resolver.ListFirewallDomainsInput) (*route53resolver.ListFirewallDomainsOutput, error) {
+	args := c.Called()
+	return args.Get(0).(*route53resolver.ListFirewallDomainsOutput), args.Error(1)
+}
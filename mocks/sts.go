@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/mock"
+)
+
+type STS struct {
+	mock.Mock
+}
+
+func (c *STS) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	args := c.Called(*input.RoleArn)
+	return args.Get(0).(*sts.AssumeRoleOutput), args.Error(1)
+}